@@ -0,0 +1,67 @@
+package timezones
+
+import "time"
+
+// Anomaly is a change of the offset that makes local wall-clock times skipped or repeated,
+// see Template.Anomalies.
+type Anomaly struct {
+	// Gap is true if the local times are skipped, usually when clocks are set forward,
+	// and false if they are repeated, usually when clocks are set back.
+	Gap bool
+
+	// Change is the instant at which the zone changes from Before to After.
+	Change time.Time
+
+	// Before and After are the zones in effect before and after Change.
+	Before, After Zone
+
+	// LocalStart and LocalEnd delimit the skipped or repeated local times [LocalStart, LocalEnd).
+	// They are in UTC, so that their date and clock read the local time.
+	LocalStart, LocalEnd time.Time
+
+	// Start and End delimit the instants [Start, End) at which the repeated local times occur.
+	// For a gap, both are Change.
+	Start, End time.Time
+}
+
+// Anomalies returns the gaps and folds of the local time whose first affected local time is in the given year,
+// ordered by time.
+// Changes that don't change the offset, for example only the designation, are not anomalies.
+func (t *Template) Anomalies(year int) []Anomaly {
+	zl := newZoneLookup(t)
+	minOffset, maxOffset := zl.offsetRange()
+	yearStart := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+	yearEnd := time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+	// The first affected local time of a change at sec is sec plus the smaller of the offsets.
+	from, to := yearStart-maxOffset, yearEnd-minOffset
+
+	var anomalies []Anomaly
+	prev, _, sec := zl.lookup(from)
+	for sec < to {
+		zone, _, next := zl.lookup(sec)
+		before, after := offsetSeconds(prev), offsetSeconds(zone)
+		if before != after {
+			a := Anomaly{
+				Gap:    after > before,
+				Change: time.Unix(sec, 0).UTC(),
+				Before: prev,
+				After:  zone,
+				Start:  time.Unix(sec, 0).UTC(),
+				End:    time.Unix(sec, 0).UTC(),
+			}
+			localStart, localEnd := sec+before, sec+after
+			if !a.Gap {
+				localStart, localEnd = localEnd, localStart
+				a.Start = time.Unix(sec-(before-after), 0).UTC()
+				a.End = time.Unix(sec+(before-after), 0).UTC()
+			}
+			a.LocalStart, a.LocalEnd = time.Unix(localStart, 0).UTC(), time.Unix(localEnd, 0).UTC()
+			if yearStart <= localStart && localStart < yearEnd {
+				anomalies = append(anomalies, a)
+			}
+		}
+		prev = zone
+		sec = next
+	}
+	return anomalies
+}
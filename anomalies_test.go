@@ -0,0 +1,66 @@
+package timezones
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTemplate_Anomalies(t *testing.T) {
+	cet := Zone{Name: "CET", Offset: time.Hour}
+	cest := Zone{Name: "CEST", Offset: 2 * time.Hour, IsDST: true}
+	template := Template{Zones: []Zone{cet}, Extend: "CET-1CEST,M3.5.0,M10.5.0/3"}
+	utc := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2022, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	expected := []Anomaly{
+		{
+			Gap:        true,
+			Change:     utc(time.March, 27, 1),
+			Before:     cet,
+			After:      cest,
+			LocalStart: utc(time.March, 27, 2),
+			LocalEnd:   utc(time.March, 27, 3),
+			Start:      utc(time.March, 27, 1),
+			End:        utc(time.March, 27, 1),
+		},
+		{
+			Gap:        false,
+			Change:     utc(time.October, 30, 1),
+			Before:     cest,
+			After:      cet,
+			LocalStart: utc(time.October, 30, 2),
+			LocalEnd:   utc(time.October, 30, 3),
+			Start:      utc(time.October, 30, 0),
+			End:        utc(time.October, 30, 2),
+		},
+	}
+	if anomalies := template.Anomalies(2022); !reflect.DeepEqual(anomalies, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, anomalies)
+	}
+
+	// Only the designation changes.
+	renamed := Template{
+		Zones:   []Zone{cet, {Name: "MET", Offset: time.Hour}},
+		Changes: []Change{{Start: utc(time.June, 1, 0), ZoneIndex: 1}},
+	}
+	if anomalies := renamed.Anomalies(2022); len(anomalies) != 0 {
+		t.Fatalf("expected no anomalies, got %+v", anomalies)
+	}
+}
+
+func TestTemplate_Anomalies_YearBoundary(t *testing.T) {
+	// Clocks set back from 00:30 on 1 January 2031 to 23:30 on 31 December 2030.
+	template := Template{
+		Zones: []Zone{{Name: "AAA", Offset: 2 * time.Hour}, {Name: "BBB", Offset: time.Hour}},
+		Changes: []Change{
+			{Start: time.Date(2030, time.December, 31, 22, 30, 0, 0, time.UTC), ZoneIndex: 1},
+		},
+	}
+	if anomalies := template.Anomalies(2030); len(anomalies) != 1 {
+		t.Fatalf("expected the fold in 2030, got %+v", anomalies)
+	}
+	if anomalies := template.Anomalies(2031); len(anomalies) != 0 {
+		t.Fatalf("expected no anomalies in 2031, got %+v", anomalies)
+	}
+}
@@ -0,0 +1,43 @@
+package timezones
+
+import "sort"
+
+// ConvertBatch returns the zones in effect at the given Unix times in the zone described by the template,
+// the same zones a time.Location built from the template uses.
+// The result has the same length as times and its element i is the zone at times[i].
+//
+// ConvertBatch looks up the times in the template's Index and each zone change only once, so converting
+// many times at once is much faster than converting them one by one, especially if times are sorted.
+// It returns the same error as Validate if the template is not valid.
+func ConvertBatch(times []int64, template Template) ([]Zone, error) {
+	if err := Validate(template); err != nil {
+		return nil, err
+	}
+	zones := make([]Zone, len(times))
+	if len(times) == 0 {
+		return zones, nil
+	}
+	var order []int
+	if !sort.SliceIsSorted(times, func(i, j int) bool { return times[i] < times[j] }) {
+		order = make([]int, len(times))
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(i, j int) bool { return times[order[i]] < times[order[j]] })
+	}
+
+	x := template.Index()
+	var zone Zone
+	var start, end int64
+	for k := range times {
+		i := k
+		if order != nil {
+			i = order[k]
+		}
+		if sec := times[i]; k == 0 || sec < start || sec >= end {
+			zone, start, end = x.lookup(sec)
+		}
+		zones[i] = zone
+	}
+	return zones, nil
+}
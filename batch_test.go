@@ -0,0 +1,62 @@
+package timezones
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestConvertBatch(t *testing.T) {
+	template := benchTemplate()
+	template.Extend = "<Std>-2:23"
+	loc, err := NewLocation(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rand.New(rand.NewSource(1))
+	times := make([]int64, 10000)
+	for i := range times {
+		times[i] = time.Date(1970+r.Intn(150), time.January, 1, 0, 0, 0, 0, time.UTC).Unix() + r.Int63n(366*24*3600)
+	}
+	sorted := append([]int64(nil), times...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for _, ts := range [][]int64{times, sorted} {
+		zones, err := ConvertBatch(ts, template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(zones) != len(ts) {
+			t.Fatalf("expected %d zones, got %d", len(ts), len(zones))
+		}
+		for i, sec := range ts {
+			name, offset := time.Unix(sec, 0).In(loc).Zone()
+			if zones[i].Name != name || zones[i].Offset != time.Duration(offset)*time.Second {
+				t.Fatalf("%d: expected %s %d, got %+v", sec, name, offset, zones[i])
+			}
+		}
+	}
+
+	if zones, err := ConvertBatch(nil, template); err != nil || len(zones) != 0 {
+		t.Fatalf("expected no zones, got %v %v", zones, err)
+	}
+	if _, err := ConvertBatch(times, Template{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func BenchmarkConvertBatch(b *testing.B) {
+	template := benchTemplate()
+	times := make([]int64, 100000)
+	start := time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+	for i := range times {
+		times[i] = start + int64(i)*3600
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ConvertBatch(times, template); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package timezones
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Provenance describes where the data of a bundle came from, for supply-chain auditing,
+// see WriteBundleProvenance.
+type Provenance struct {
+	// Source identifies the source data, e.g. "tzdata 2024a".
+	Source string `json:"source,omitempty"`
+
+	// Generated is when the bundle was generated.
+	Generated time.Time `json:"generated"`
+
+	// Tool identifies the tool that generated the bundle and its version, e.g. "tzbundle v1.4.0".
+	Tool string `json:"tool,omitempty"`
+}
+
+// provenancePrefix starts the zip file comment holding the provenance of a bundle.
+const provenancePrefix = "timezones provenance: "
+
+// WriteBundle writes a zip file with the TZif files of the named zones from fsys to w,
+// e.g. to embed only the zones an application needs instead of importing time/tzdata.
+// The files are checked to be valid TZif data for the GoCompatible profile and copied unchanged,
+// so system zoneinfo files written by zic can be bundled.
+// Like Go's lib/time/zoneinfo.zip, the files are stored uncompressed, so the bundle can also be used
+// by Go's time package through the ZONEINFO environment variable.
+// Use LoadBundle to load the bundle.
+func WriteBundle(w io.Writer, fsys fs.FS, names []string) error {
+	return writeBundle(w, fsys, names, nil)
+}
+
+// WriteBundleProvenance is like WriteBundle, but also records the provenance of the data in the bundle.
+// The provenance is stored in the zip file comment, so the bundle loads the same way as without it.
+// Use BundleProvenance to read it.
+func WriteBundleProvenance(w io.Writer, fsys fs.FS, names []string, provenance Provenance) error {
+	return writeBundle(w, fsys, names, &provenance)
+}
+
+func writeBundle(w io.Writer, fsys fs.FS, names []string, provenance *Provenance) error {
+	names = append([]string(nil), names...)
+	sort.Strings(names)
+	zw := zip.NewWriter(w)
+	d := GoCompatible.Decoder()
+	for i, name := range names {
+		if i > 0 && names[i-1] == name {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		if _, err := d.Decode(data); err != nil {
+			return withTemplateName(&Template{Name: name}, err)
+		}
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(data); err != nil {
+			return err
+		}
+	}
+	if provenance != nil {
+		comment, err := json.Marshal(provenance)
+		if err != nil {
+			return err
+		}
+		if err := zw.SetComment(provenancePrefix + string(comment)); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// LoadBundle loads all zones from a bundle written by WriteBundle, see LoadAll.
+func LoadBundle(bundle []byte) (map[string]*Template, error) {
+	zr, err := zip.NewReader(bytes.NewReader(bundle), int64(len(bundle)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return LoadAll(zr, LoadOptions{})
+}
+
+// BundleProvenance returns the provenance recorded in a bundle written by WriteBundleProvenance.
+// It returns false if the bundle has no provenance.
+func BundleProvenance(bundle []byte) (provenance Provenance, ok bool, err error) {
+	zr, err := zip.NewReader(bytes.NewReader(bundle), int64(len(bundle)))
+	if err != nil {
+		return Provenance{}, false, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if !strings.HasPrefix(zr.Comment, provenancePrefix) {
+		return Provenance{}, false, nil
+	}
+	if err := json.Unmarshal([]byte(zr.Comment[len(provenancePrefix):]), &provenance); err != nil {
+		return Provenance{}, false, fmt.Errorf("%w: provenance: %v", ErrInvalid, err)
+	}
+	return provenance, true, nil
+}
@@ -0,0 +1,97 @@
+package timezones
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestWriteBundle(t *testing.T) {
+	fsys := testFS(t)
+	var buf bytes.Buffer
+	if err := WriteBundle(&buf, fsys, []string{"Etc/MyFixed", "Custom/Bench", "Etc/MyFixed"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	templates, err := LoadBundle(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if expected := []string{"Custom/Bench", "Etc/MyFixed"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+	expected, err := LoadFile(fsys, "Custom/Bench")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(templates["Custom/Bench"], expected) {
+		t.Fatalf("expected %+v, got %+v", expected, templates["Custom/Bench"])
+	}
+
+	// zic writes 0 indicators.
+	wall := append([]byte(nil), fsys["Custom/Bench"].Data...)
+	footer := bytes.LastIndexByte(wall[:len(wall)-1], '\n')
+	fill(wall[footer-2*len(benchTemplate().Changes):footer], 0)
+	fsys["Custom/Wall"] = &fstest.MapFile{Data: wall}
+	buf.Reset()
+	if err := WriteBundle(&buf, fsys, []string{"Custom/Wall"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if templates, err := LoadBundle(buf.Bytes()); err != nil || templates["Custom/Wall"] == nil {
+		t.Fatalf("expected Custom/Wall in bundle, got %v %v", templates, err)
+	}
+
+	if err := WriteBundle(&buf, fsys, []string{"zone.tab"}); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid, got %v", err)
+	}
+	if err := WriteBundle(&buf, fsys, []string{"Etc/Missing"}); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected fs.ErrNotExist, got %v", err)
+	}
+	if _, err := LoadBundle([]byte("not a zip")); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid, got %v", err)
+	}
+}
+
+func TestWriteBundleProvenance(t *testing.T) {
+	fsys := testFS(t)
+	provenance := Provenance{
+		Source:    "tzdata 2024a",
+		Generated: time.Date(2024, time.February, 1, 12, 0, 0, 0, time.UTC),
+		Tool:      "tzbundle v1.0.0",
+	}
+	var buf bytes.Buffer
+	if err := WriteBundleProvenance(&buf, fsys, []string{"Etc/MyFixed"}, provenance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, ok, err := BundleProvenance(buf.Bytes())
+	if err != nil || !ok {
+		t.Fatalf("expected provenance, got %v, %v", ok, err)
+	}
+	if !reflect.DeepEqual(got, provenance) {
+		t.Fatalf("expected %+v, got %+v", provenance, got)
+	}
+	templates, err := LoadBundle(buf.Bytes())
+	if err != nil || len(templates) != 1 {
+		t.Fatalf("unexpected templates %v, error %v", templates, err)
+	}
+
+	buf.Reset()
+	if err := WriteBundle(&buf, fsys, []string{"Etc/MyFixed"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := BundleProvenance(buf.Bytes()); ok || err != nil {
+		t.Fatalf("expected no provenance, got %v, %v", ok, err)
+	}
+	if _, _, err := BundleProvenance([]byte("not a zip")); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid, got %v", err)
+	}
+}
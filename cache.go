@@ -0,0 +1,109 @@
+package timezones
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// Fingerprint returns a hash of the template.
+// Templates that produce identical TZif data with TZData and have the same Name have the same fingerprint.
+// Version, Trailing and Metadata are not part of the fingerprint, as TZData does not encode them.
+func (t *Template) Fingerprint() [sha256.Size]byte {
+	if t.FirstZoneIndex != 0 {
+		// Encode normalizes the first zone the same way.
+		normalized := t.withFirstZoneAtZero()
+		t = &normalized
+	}
+	h := sha256.New()
+	var buf [8]byte
+	writeInt := func(v int64) {
+		binary.BigEndian.PutUint64(buf[:], uint64(v))
+		h.Write(buf[:])
+	}
+	writeString := func(s string) {
+		writeInt(int64(len(s)))
+		h.Write([]byte(s))
+	}
+	writeString(t.Name)
+	writeInt(int64(len(t.Zones)))
+	for i := range t.Zones {
+		writeString(t.Zones[i].Name)
+		writeInt(int64(t.Zones[i].Offset))
+		if t.Zones[i].IsDST {
+			writeInt(1)
+		} else {
+			writeInt(0)
+		}
+	}
+	writeInt(int64(len(t.Changes)))
+	for i := range t.Changes {
+		// TZif stores whole seconds, so sub-second parts don't change the result.
+		writeInt(t.Changes[i].Start.Unix())
+		writeInt(int64(t.Changes[i].ZoneIndex))
+	}
+	writeString(t.Extend)
+	writeInt(int64(len(t.LeapSeconds)))
+	for i := range t.LeapSeconds {
+		writeInt(t.LeapSeconds[i].Occurrence.Unix())
+		writeInt(int64(t.LeapSeconds[i].Correction))
+	}
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// LocationCache caches locations created from templates, keyed by Template.Fingerprint.
+//
+// NewLocation encodes the template and parses the result again every time it is called.
+// Code that repeatedly constructs the same location can use a LocationCache instead,
+// turning repeated calls into a map lookup.
+//
+// Cached locations are never evicted, use Clear to release them.
+// The zero value is an empty cache ready to use.
+// A LocationCache is safe for concurrent use by multiple goroutines.
+type LocationCache struct {
+	mu        sync.RWMutex
+	locations map[[sha256.Size]byte]*time.Location
+}
+
+// NewLocation returns a location for the template, creating it with NewLocation if it is not cached yet.
+func (c *LocationCache) NewLocation(template Template) (*time.Location, error) {
+	key := template.Fingerprint()
+	c.mu.RLock()
+	loc, ok := c.locations[key]
+	c.mu.RUnlock()
+	if ok {
+		return loc, nil
+	}
+	loc, err := NewLocation(template)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, ok := c.locations[key]; ok {
+		// Another goroutine was faster, return the same location to all callers.
+		return existing, nil
+	}
+	if c.locations == nil {
+		c.locations = make(map[[sha256.Size]byte]*time.Location)
+	}
+	c.locations[key] = loc
+	return loc, nil
+}
+
+// Len returns the number of cached locations.
+func (c *LocationCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.locations)
+}
+
+// Clear removes all cached locations.
+func (c *LocationCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.locations = nil
+}
@@ -0,0 +1,136 @@
+package timezones
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTemplate_Fingerprint(t *testing.T) {
+	base := benchTemplate()
+	fp := base.Fingerprint()
+
+	same := benchTemplate()
+	for i := range same.Changes {
+		// Location and sub-second parts of Start don't influence the output.
+		same.Changes[i].Start = same.Changes[i].Start.In(time.Local).Add(time.Millisecond)
+	}
+	// TZData does not encode these.
+	same.Version = 2
+	same.Trailing = []byte("extension\n")
+	same.Metadata = &Metadata{Comments: []string{"ignored"}}
+	if same.Fingerprint() != fp {
+		t.Fatal("expected equal fingerprints for equivalent templates")
+	}
+
+	modifications := map[string]func(tmpl *Template){
+		"name":       func(tmpl *Template) { tmpl.Name = "Other" },
+		"zone name":  func(tmpl *Template) { tmpl.Zones[1].Name = "Other" },
+		"offset":     func(tmpl *Template) { tmpl.Zones[1].Offset += time.Second },
+		"dst":        func(tmpl *Template) { tmpl.Zones[1].IsDST = false },
+		"start":      func(tmpl *Template) { tmpl.Changes[3].Start = tmpl.Changes[3].Start.Add(time.Second) },
+		"zone index": func(tmpl *Template) { tmpl.Changes[3].ZoneIndex = 1 },
+		"changes":    func(tmpl *Template) { tmpl.Changes = tmpl.Changes[:10] },
+		"extend":     func(tmpl *Template) { tmpl.Extend = "UTC0" },
+		"leap seconds": func(tmpl *Template) {
+			tmpl.LeapSeconds = []LeapSecond{{Occurrence: time.Unix(78796800, 0), Correction: 1}}
+		},
+	}
+	for name, modify := range modifications {
+		t.Run(name, func(t *testing.T) {
+			tmpl := benchTemplate()
+			modify(&tmpl)
+			if tmpl.Fingerprint() == fp {
+				t.Fatal("expected different fingerprint")
+			}
+		})
+	}
+}
+
+func TestLocationCache(t *testing.T) {
+	var cache LocationCache
+	loc1, err := cache.NewLocation(benchTemplate())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loc2, err := cache.NewLocation(benchTemplate())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loc1 != loc2 {
+		t.Fatal("expected the cached location to be returned")
+	}
+	if loc1.String() != "MyChanges" {
+		t.Fatalf("unexpected location name %q", loc1.String())
+	}
+	if cache.Len() != 1 {
+		t.Fatalf("expected 1 cached location, got %d", cache.Len())
+	}
+
+	other := benchTemplate()
+	other.Name = "Other"
+	loc3, err := cache.NewLocation(other)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loc3 == loc1 {
+		t.Fatal("expected a different location for a different template")
+	}
+
+	// Go ignores leap seconds, but invalid ones must not be served from the cache.
+	invalid := benchTemplate()
+	invalid.LeapSeconds = []LeapSecond{{Occurrence: time.Unix(78796800, 0), Correction: 5}}
+	if _, err := cache.NewLocation(invalid); !errors.Is(err, ErrInvalidLeapSecond) {
+		t.Fatalf("expected ErrInvalidLeapSecond, got %v", err)
+	}
+
+	if _, err := cache.NewLocation(Template{}); err == nil {
+		t.Fatal("expected error for an invalid template")
+	}
+	if cache.Len() != 2 {
+		t.Fatalf("expected 2 cached locations, got %d", cache.Len())
+	}
+
+	cache.Clear()
+	if cache.Len() != 0 {
+		t.Fatalf("expected empty cache, got %d", cache.Len())
+	}
+}
+
+func TestLocationCache_Concurrent(t *testing.T) {
+	var cache LocationCache
+	var wg sync.WaitGroup
+	locs := make([]*time.Location, 8)
+	for i := range locs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			loc, err := cache.NewLocation(benchTemplate())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			locs[i] = loc
+		}(i)
+	}
+	wg.Wait()
+	for i := range locs {
+		if locs[i] != locs[0] {
+			t.Fatal("expected all goroutines to get the same location")
+		}
+	}
+}
+
+func BenchmarkLocationCache_NewLocation(b *testing.B) {
+	var cache LocationCache
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		template := benchTemplate()
+		loc, err := cache.NewLocation(template)
+		if err != nil {
+			b.Fatal(err)
+		}
+		benchLoc = loc
+	}
+}
@@ -0,0 +1,80 @@
+package timezones
+
+import "time"
+
+// CivilDiff returns the difference between from and to in local calendar terms in the zone described by
+// the template: the number of whole local days and the rest.
+// A local day is from a local time to the same local time on the next day, so it is 23 or 25 hours long
+// when clocks are set forward or back, e.g. the difference between noon before and noon after a change
+// is one day and no rest.
+//
+// Days are counted from from itself, even if its local time occurs twice. If the local time of from
+// does not occur on another day reached, it is interpreted with the offset in effect before the gap,
+// and if it occurs twice, the earlier occurrence is used.
+// If to is before from, both days and rest are negative or zero.
+// It returns the same error as Validate if the template is not valid.
+func CivilDiff(from, to time.Time, template Template) (days int, rest time.Duration, err error) {
+	if err := Validate(template); err != nil {
+		return 0, 0, err
+	}
+	if to.Before(from) {
+		days, rest = civilDiff(to, from, newZoneLookup(&template))
+		return -days, -rest, nil
+	}
+	days, rest = civilDiff(from, to, newZoneLookup(&template))
+	return days, rest, nil
+}
+
+// civilDiff is CivilDiff for from not after to.
+func civilDiff(from, to time.Time, zl *zoneLookup) (int, time.Duration) {
+	fromZone, _, _ := zl.lookup(from.Unix())
+	toZone, _, _ := zl.lookup(to.Unix())
+	fromWall := time.Unix(from.Unix()+offsetSeconds(fromZone), int64(from.Nanosecond())).UTC()
+	toWall := time.Unix(to.Unix()+offsetSeconds(toZone), int64(to.Nanosecond())).UTC()
+	days := int(toWall.Sub(fromWall) / (24 * time.Hour))
+	// The estimate is off by at most a day due to changes of the offset.
+	for days > 0 && civilAddDays(from, fromWall, days, zl).After(to) {
+		days--
+	}
+	for !civilAddDays(from, fromWall, days+1, zl).After(to) {
+		days++
+	}
+	return days, to.Sub(civilAddDays(from, fromWall, days, zl))
+}
+
+// civilAddDays returns from moved by days in local calendar terms, see CivilDiff.
+// wall is the local time of from, in UTC so that its clock reads the local time.
+func civilAddDays(from, wall time.Time, days int, zl *zoneLookup) time.Time {
+	if days == 0 {
+		// Resolving wall again would pick the earlier occurrence if from is the later one.
+		return from
+	}
+	moved := wall.AddDate(0, 0, days)
+	r := zl.resolveLocal(moved.Unix())
+	u, _ := r.pick(moved, ResolveLatest)
+	if len(r.instants) > 1 {
+		u = r.instants[0]
+	}
+	return time.Unix(u, int64(moved.Nanosecond())).UTC()
+}
+
+// DayLength returns the length of the local day year-month-day in the zone described by the template,
+// from the first instant of the day to the first instant of the next day,
+// e.g. 23 hours when clocks are set forward and 25 hours when they are set back.
+// If midnight is skipped, the day starts when the gap ends.
+// Values outside the usual ranges are normalized the same way as by time.Date.
+// It returns the same error as Validate if the template is not valid.
+func DayLength(year int, month time.Month, day int, template Template) (time.Duration, error) {
+	if err := Validate(template); err != nil {
+		return 0, err
+	}
+	zl := newZoneLookup(&template)
+	startOfDay := func(day int) int64 {
+		midnight := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		r := zl.resolveLocal(midnight.Unix())
+		// ResolveShiftForward never fails.
+		u, _ := r.pick(midnight, ResolveShiftForward)
+		return u
+	}
+	return time.Duration(startOfDay(day+1)-startOfDay(day)) * time.Second, nil
+}
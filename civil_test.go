@@ -0,0 +1,66 @@
+package timezones
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCivilDiff(t *testing.T) {
+	template := Template{Extend: "CET-1CEST,M3.5.0,M10.5.0/3"}
+	utc := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2022, month, day, hour, min, 0, 0, time.UTC)
+	}
+	for _, tc := range []struct {
+		name     string
+		from, to time.Time
+		days     int
+		rest     time.Duration
+	}{
+		// Noon to noon across the 23-hour day.
+		{"spring", utc(time.March, 26, 11, 0), utc(time.March, 27, 10, 0), 1, 0},
+		// Noon to noon across the 25-hour day.
+		{"autumn", utc(time.October, 29, 10, 0), utc(time.October, 30, 11, 0), 1, 0},
+		{"autumn short", utc(time.October, 29, 10, 0), utc(time.October, 30, 10, 59), 0, 24*time.Hour + 59*time.Minute},
+		{"rest", utc(time.January, 1, 0, 0), utc(time.July, 1, 12, 30), 181, 13*time.Hour + 30*time.Minute},
+		{"same", utc(time.January, 1, 0, 0), utc(time.January, 1, 0, 0), 0, 0},
+		{"negative", utc(time.March, 27, 10, 0), utc(time.March, 26, 10, 0), -1, -time.Hour},
+		// 02:30 on 27 March does not exist, it is 03:30 instead.
+		{"gap", utc(time.March, 26, 1, 30), utc(time.March, 27, 1, 30), 1, 0},
+		// 02:30 on 30 October occurs twice, from is the later occurrence.
+		{"fold later", utc(time.October, 30, 1, 30), utc(time.October, 30, 1, 30), 0, 0},
+		{"fold later rest", utc(time.October, 30, 1, 30), utc(time.October, 30, 2, 0), 0, 30 * time.Minute},
+		{"fold later next day", utc(time.October, 30, 1, 30), utc(time.October, 31, 1, 30), 1, 0},
+		{"fold later negative", utc(time.October, 30, 1, 30), utc(time.October, 30, 0, 30), 0, -time.Hour},
+	} {
+		days, rest, err := CivilDiff(tc.from, tc.to, template)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if days != tc.days || rest != tc.rest {
+			t.Fatalf("%s: expected %d days %v, got %d days %v", tc.name, tc.days, tc.rest, days, rest)
+		}
+	}
+}
+
+func TestDayLength(t *testing.T) {
+	for _, tc := range []struct {
+		template Template
+		month    time.Month
+		day      int
+		expected time.Duration
+	}{
+		{Template{Extend: "CET-1CEST,M3.5.0,M10.5.0/3"}, time.March, 27, 23 * time.Hour},
+		{Template{Extend: "CET-1CEST,M3.5.0,M10.5.0/3"}, time.October, 30, 25 * time.Hour},
+		{Template{Extend: "CET-1CEST,M3.5.0,M10.5.0/3"}, time.July, 1, 24 * time.Hour},
+		// Midnight is skipped on 13 March 2022.
+		{Template{Extend: "MDS3MDD,M3.2.0/0,M11.1.0/0"}, time.March, 13, 23 * time.Hour},
+		{Template{Extend: "MDS3MDD,M3.2.0/0,M11.1.0/0"}, time.March, 12, 24 * time.Hour},
+		// Midnight of 6 November 2022 occurs twice.
+		{Template{Extend: "MDS3MDD,M3.2.0/0,M11.1.0/0"}, time.November, 5, 25 * time.Hour},
+	} {
+		length, err := DayLength(2022, tc.month, tc.day, tc.template)
+		if err != nil || length != tc.expected {
+			t.Fatalf("%s %v %d: expected %v, got %v %v", tc.template.Extend, tc.month, tc.day, tc.expected, length, err)
+		}
+	}
+}
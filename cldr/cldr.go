@@ -0,0 +1,239 @@
+// Package cldr provides localized display names of zones from the Unicode CLDR data,
+// e.g. "Mitteleuropäische Sommerzeit" or "Los Angeles", for user-facing zone pickers.
+//
+// The data is not bundled with this package as it is large and updated independently.
+// Load reads it from the JSON distribution of CLDR, https://github.com/unicode-org/cldr-json,
+// for example the cldr-core and cldr-dates-full packages merged into a single tree.
+package cldr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+// Data holds the CLDR data needed for display names of zones.
+type Data struct {
+	// metazones maps zone IDs to the metazones they use over time.
+	metazones map[string][]metazoneUse
+	// locales maps locale IDs to their names.
+	locales map[string]*localeNames
+}
+
+// metazoneUse is a metazone used by a zone during [from, to). Zero from or to means unbounded.
+type metazoneUse struct {
+	metazone string
+	from, to time.Time
+}
+
+type localeNames struct {
+	// exemplarCities maps zone IDs to exemplar cities.
+	exemplarCities map[string]string
+	// metazones maps metazone IDs to their long names.
+	metazones map[string]metazoneNames
+}
+
+type metazoneNames struct {
+	Generic  string `json:"generic"`
+	Standard string `json:"standard"`
+	Daylight string `json:"daylight"`
+}
+
+// Load loads the metazone mapping from supplemental/metaZones.json and the names of each of the locales
+// from main/<locale>/timeZoneNames.json in fsys.
+func Load(fsys fs.FS, locales ...string) (*Data, error) {
+	d := &Data{
+		metazones: make(map[string][]metazoneUse),
+		locales:   make(map[string]*localeNames),
+	}
+	if err := d.loadMetazones(fsys); err != nil {
+		return nil, err
+	}
+	for _, locale := range locales {
+		if err := d.loadLocale(fsys, locale); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+func (d *Data) loadMetazones(fsys fs.FS) error {
+	const name = "supplemental/metaZones.json"
+	var file struct {
+		Supplemental struct {
+			MetaZones struct {
+				MetazoneInfo struct {
+					Timezone map[string]json.RawMessage `json:"timezone"`
+				} `json:"metazoneInfo"`
+			} `json:"metaZones"`
+		} `json:"supplemental"`
+	}
+	if err := readJSON(fsys, name, &file); err != nil {
+		return err
+	}
+	return walkZones(file.Supplemental.MetaZones.MetazoneInfo.Timezone, "", func(zone string, raw json.RawMessage) (bool, error) {
+		var uses []struct {
+			UsesMetazone struct {
+				Metazone string `json:"_mzone"`
+				From     string `json:"_from"`
+				To       string `json:"_to"`
+			} `json:"usesMetazone"`
+		}
+		if err := json.Unmarshal(raw, &uses); err != nil {
+			// Not a leaf, but a further level of the zone ID.
+			return false, nil
+		}
+		for _, u := range uses {
+			use := metazoneUse{metazone: u.UsesMetazone.Metazone}
+			var err error
+			if use.from, err = parseTime(u.UsesMetazone.From); err != nil {
+				return true, fmt.Errorf("%s: %s: %w", name, zone, err)
+			}
+			if use.to, err = parseTime(u.UsesMetazone.To); err != nil {
+				return true, fmt.Errorf("%s: %s: %w", name, zone, err)
+			}
+			d.metazones[zone] = append(d.metazones[zone], use)
+		}
+		return true, nil
+	})
+}
+
+func (d *Data) loadLocale(fsys fs.FS, locale string) error {
+	var file struct {
+		Main map[string]struct {
+			Dates struct {
+				TimeZoneNames struct {
+					Zone     map[string]json.RawMessage `json:"zone"`
+					Metazone map[string]struct {
+						Long metazoneNames `json:"long"`
+					} `json:"metazone"`
+				} `json:"timeZoneNames"`
+			} `json:"dates"`
+		} `json:"main"`
+	}
+	name := path.Join("main", locale, "timeZoneNames.json")
+	if err := readJSON(fsys, name, &file); err != nil {
+		return err
+	}
+	main, ok := file.Main[locale]
+	if !ok {
+		return fmt.Errorf("%s: locale %s not found", name, locale)
+	}
+	names := &localeNames{
+		exemplarCities: make(map[string]string),
+		metazones:      make(map[string]metazoneNames),
+	}
+	for id, mz := range main.Dates.TimeZoneNames.Metazone {
+		names.metazones[id] = mz.Long
+	}
+	err := walkZones(main.Dates.TimeZoneNames.Zone, "", func(zone string, raw json.RawMessage) (bool, error) {
+		var leaf struct {
+			ExemplarCity *string `json:"exemplarCity"`
+		}
+		if err := json.Unmarshal(raw, &leaf); err != nil || leaf.ExemplarCity == nil {
+			return false, nil
+		}
+		names.exemplarCities[zone] = *leaf.ExemplarCity
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+	d.locales[locale] = names
+	return nil
+}
+
+// walkZones calls leaf for each node of the tree of zone ID components, e.g. "America" → "Argentina" →
+// "Buenos_Aires", until leaf reports that the node is a leaf.
+func walkZones(tree map[string]json.RawMessage, prefix string, leaf func(zone string, raw json.RawMessage) (bool, error)) error {
+	for component, raw := range tree {
+		zone := prefix + component
+		isLeaf, err := leaf(zone, raw)
+		if err != nil {
+			return err
+		}
+		if isLeaf {
+			continue
+		}
+		var subtree map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &subtree); err != nil {
+			continue
+		}
+		if err := walkZones(subtree, zone+"/", leaf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readJSON(fsys fs.FS, name string, v interface{}) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// parseTime parses the UTC times used by metaZones.json, e.g. "1970-01-01 00:00".
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02 15:04", s)
+}
+
+// Metazone returns the ID of the metazone the zone uses at the given instant, e.g. "Europe_Central".
+// It returns false if the zone does not use any metazone at that time.
+func (d *Data) Metazone(zone string, at time.Time) (string, bool) {
+	for _, use := range d.metazones[zone] {
+		if (use.from.IsZero() || !at.Before(use.from)) && (use.to.IsZero() || at.Before(use.to)) {
+			return use.metazone, true
+		}
+	}
+	return "", false
+}
+
+// ExemplarCity returns the name of the city representing the zone in the locale, e.g. "Los Angeles".
+// If the locale does not name the city, the last component of the zone ID is used with underscores
+// replaced by spaces, as CLDR recommends.
+func (d *Data) ExemplarCity(zone, locale string) string {
+	if names := d.locales[locale]; names != nil {
+		if city, ok := names.exemplarCities[zone]; ok {
+			return city
+		}
+	}
+	return strings.ReplaceAll(path.Base(zone), "_", " ")
+}
+
+// DisplayName returns the long localized name of the zone of the template at the given instant,
+// e.g. "Mitteleuropäische Sommerzeit", using the Name of the template as the zone ID.
+// The standard or daylight name is chosen by the IsDST flag of the zone in effect, see
+// timezones.Template.ZoneAt.
+// It returns false if the locale was not loaded or does not name the metazone.
+func (d *Data) DisplayName(template *timezones.Template, at time.Time, locale string) (string, bool) {
+	names := d.locales[locale]
+	if names == nil {
+		return "", false
+	}
+	metazone, ok := d.Metazone(template.Name, at)
+	if !ok {
+		return "", false
+	}
+	mzNames := names.metazones[metazone]
+	name := mzNames.Standard
+	if template.ZoneAt(at).IsDST {
+		name = mzNames.Daylight
+	}
+	if name == "" {
+		name = mzNames.Generic
+	}
+	return name, name != ""
+}
@@ -0,0 +1,105 @@
+package cldr
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"supplemental/metaZones.json": {Data: []byte(`{"supplemental": {"metaZones": {"metazoneInfo": {"timezone": {
+			"Europe": {"Prague": [{"usesMetazone": {"_mzone": "Europe_Central"}}]},
+			"America": {
+				"Argentina": {"Buenos_Aires": [{"usesMetazone": {"_mzone": "Argentina"}}]},
+				"Indiana": {"Knox": [
+					{"usesMetazone": {"_to": "1991-10-27 07:00", "_mzone": "America_Central"}},
+					{"usesMetazone": {"_to": "2006-04-02 07:00", "_from": "1991-10-27 07:00", "_mzone": "America_Eastern"}},
+					{"usesMetazone": {"_from": "2006-04-02 07:00", "_mzone": "America_Central"}}
+				]}
+			}
+		}}}}}`)},
+		"main/de/timeZoneNames.json": {Data: []byte(`{"main": {"de": {"dates": {"timeZoneNames": {
+			"zone": {"Europe": {"Prague": {"exemplarCity": "Prag"}}},
+			"metazone": {"Europe_Central": {"long": {
+				"generic": "Mitteleuropäische Zeit",
+				"standard": "Mitteleuropäische Normalzeit",
+				"daylight": "Mitteleuropäische Sommerzeit"
+			}}}
+		}}}}}`)},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	data, err := Load(testFS(), "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if city := data.ExemplarCity("Europe/Prague", "de"); city != "Prag" {
+		t.Errorf("expected Prag, got %q", city)
+	}
+	if city := data.ExemplarCity("America/Argentina/Buenos_Aires", "de"); city != "Buenos Aires" {
+		t.Errorf("expected Buenos Aires, got %q", city)
+	}
+	if _, err := Load(testFS(), "fr"); err == nil {
+		t.Error("expected an error for a missing locale")
+	}
+}
+
+func TestData_Metazone(t *testing.T) {
+	data, err := Load(testFS())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		at       time.Time
+		expected string
+	}{
+		{time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC), "America_Central"},
+		{time.Date(1991, time.October, 27, 7, 0, 0, 0, time.UTC), "America_Eastern"},
+		{time.Date(2006, time.April, 2, 6, 59, 0, 0, time.UTC), "America_Eastern"},
+		{time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC), "America_Central"},
+	}
+	for _, test := range tests {
+		metazone, ok := data.Metazone("America/Indiana/Knox", test.at)
+		if !ok || metazone != test.expected {
+			t.Errorf("%v: expected %s, got %q, %v", test.at, test.expected, metazone, ok)
+		}
+	}
+	if _, ok := data.Metazone("Etc/Unknown", time.Now()); ok {
+		t.Error("expected no metazone for an unknown zone")
+	}
+}
+
+func TestData_DisplayName(t *testing.T) {
+	data, err := Load(testFS(), "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &timezones.Template{
+		Name: "Europe/Prague",
+		Zones: []timezones.Zone{
+			{Name: "CET", Offset: time.Hour},
+			{Name: "CEST", Offset: 2 * time.Hour, IsDST: true},
+		},
+		Extend: "CET-1CEST,M3.5.0,M10.5.0/3",
+	}
+	tests := []struct {
+		at       time.Time
+		expected string
+	}{
+		{time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC), "Mitteleuropäische Normalzeit"},
+		{time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC), "Mitteleuropäische Sommerzeit"},
+	}
+	for _, test := range tests {
+		name, ok := data.DisplayName(template, test.at, "de")
+		if !ok || name != test.expected {
+			t.Errorf("%v: expected %s, got %q, %v", test.at, test.expected, name, ok)
+		}
+	}
+	if _, ok := data.DisplayName(template, tests[0].at, "fr"); ok {
+		t.Error("expected no name for a locale that was not loaded")
+	}
+}
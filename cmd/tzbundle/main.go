@@ -0,0 +1,165 @@
+// Command tzbundle writes a bundle with a subset of zones for embedding into a program.
+//
+// Usage:
+//
+//	tzbundle [-src data] [-o bundle.zip] [-source version] [-go file] [-pkg name] zone...
+//
+// The data is a directory or a zip file containing a tree of TZif files, like Go's lib/time/zoneinfo.zip,
+// which is used by default. The named zones are written to a zip file, see timezones.WriteBundle,
+// which is much smaller than time/tzdata if a program needs only a few zones.
+//
+// With -source, the bundle records its provenance: the given version of the source data,
+// the generation time and the version of tzbundle, see timezones.WriteBundleProvenance.
+//
+// With -go, tzbundle also writes a Go source file in package -pkg that embeds the bundle and
+// declares a LoadLocation function loading locations from it.
+// The Go file must be in the same directory as the bundle.
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"text/template"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "tzbundle: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stderr io.Writer) error {
+	flags := flag.NewFlagSet("tzbundle", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	src := flags.String("src", filepath.Join(runtime.GOROOT(), "lib", "time", "zoneinfo.zip"),
+		"directory or zip `file` with TZif files")
+	output := flags.String("o", "tzbundle.zip", "write the bundle to `file`")
+	source := flags.String("source", "", "record provenance with the `version` of the source data, e.g. \"tzdata 2024a\"")
+	goFile := flags.String("go", "", "write a Go `file` embedding the bundle")
+	pkg := flags.String("pkg", "main", "package `name` of the Go file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return errors.New("expected at least one zone")
+	}
+	if *goFile != "" && filepath.Dir(*goFile) != filepath.Dir(*output) {
+		return errors.New("the Go file must be in the same directory as the bundle")
+	}
+
+	fsys, closeSource, err := openSource(*src)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+	var bundle bytes.Buffer
+	if *source == "" {
+		err = timezones.WriteBundle(&bundle, fsys, flags.Args())
+	} else {
+		err = timezones.WriteBundleProvenance(&bundle, fsys, flags.Args(), timezones.Provenance{
+			Source:    *source,
+			Generated: time.Now().UTC(),
+			Tool:      toolVersion(),
+		})
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*output, bundle.Bytes(), 0o644); err != nil {
+		return err
+	}
+	if *goFile == "" {
+		return nil
+	}
+	goSource, err := loaderSource(*pkg, filepath.Base(*output))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(*goFile, goSource, 0o644)
+}
+
+// toolVersion returns the name of the tool with the version of its module, if known.
+func toolVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return "tzbundle " + info.Main.Version
+	}
+	return "tzbundle"
+}
+
+// openSource opens a directory or a zip file.
+func openSource(source string) (fs.FS, func() error, error) {
+	fi, err := os.Stat(source)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fi.IsDir() {
+		return os.DirFS(source), func() error { return nil }, nil
+	}
+	zr, err := zip.OpenReader(source)
+	if err != nil {
+		return nil, nil, err
+	}
+	return zr, zr.Close, nil
+}
+
+var loaderTemplate = template.Must(template.New("loader").Parse(`// Code generated by tzbundle; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	_ "embed"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+//go:embed {{.Bundle}}
+var tzbundle []byte
+
+var (
+	tzbundleOnce      sync.Once
+	tzbundleTemplates map[string]*timezones.Template
+	tzbundleErr       error
+)
+
+// LoadLocation returns the location with the given name from the embedded bundle.
+func LoadLocation(name string) (*time.Location, error) {
+	tzbundleOnce.Do(func() {
+		tzbundleTemplates, tzbundleErr = timezones.LoadBundle(tzbundle)
+	})
+	if tzbundleErr != nil {
+		return nil, tzbundleErr
+	}
+	template, ok := tzbundleTemplates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown time zone %s", name)
+	}
+	return timezones.NewLocation(*template)
+}
+`))
+
+// loaderSource returns the Go source embedding the bundle.
+func loaderSource(pkg, bundle string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := loaderTemplate.Execute(&buf, struct{ Package, Bundle string }{pkg, bundle})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func TestRun(t *testing.T) {
+	src := t.TempDir()
+	for name, offset := range map[string]time.Duration{"Etc/One": time.Hour, "Etc/Two": 2 * time.Hour} {
+		data, err := timezones.TZData(timezones.Template{Zones: []timezones.Zone{{Name: "ZZZ", Offset: offset}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(src, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := t.TempDir()
+	bundlePath := filepath.Join(out, "zones.zip")
+	goPath := filepath.Join(out, "zones.go")
+	var stderr bytes.Buffer
+	err := run([]string{"-src", src, "-o", bundlePath, "-go", goPath, "-pkg", "zones", "Etc/Two"}, &stderr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bundle, err := os.ReadFile(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	templates, err := timezones.LoadBundle(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 1 || templates["Etc/Two"] == nil {
+		t.Fatalf("expected only Etc/Two, got %v", templates)
+	}
+	if _, ok, err := timezones.BundleProvenance(bundle); ok || err != nil {
+		t.Fatalf("expected no provenance, got %v, %v", ok, err)
+	}
+	source, err := os.ReadFile(goPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"package zones\n", "//go:embed zones.zip\n", "func LoadLocation(name string)"} {
+		if !strings.Contains(string(source), s) {
+			t.Fatalf("expected Go source to contain %q, got:\n%s", s, source)
+		}
+	}
+
+	if err := run([]string{"-src", src, "-o", bundlePath, "-source", "tzdata 2024a", "Etc/One"}, &stderr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bundle, err = os.ReadFile(bundlePath); err != nil {
+		t.Fatal(err)
+	}
+	provenance, ok, err := timezones.BundleProvenance(bundle)
+	if err != nil || !ok {
+		t.Fatalf("expected provenance, got %v, %v", ok, err)
+	}
+	if provenance.Source != "tzdata 2024a" || provenance.Generated.IsZero() || !strings.HasPrefix(provenance.Tool, "tzbundle") {
+		t.Fatalf("unexpected provenance %+v", provenance)
+	}
+
+	if err := run([]string{"-src", src, "-o", bundlePath, "Etc/Missing"}, &stderr); err == nil {
+		t.Fatal("expected error for a missing zone")
+	}
+	if err := run([]string{"-src", src, "-o", bundlePath}, &stderr); err == nil {
+		t.Fatal("expected error without zones")
+	}
+	if err := run([]string{"-src", src, "-o", bundlePath, "-go", filepath.Join(src, "zones.go"), "Etc/One"}, &stderr); err == nil {
+		t.Fatal("expected error for the Go file in another directory")
+	}
+}
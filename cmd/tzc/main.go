@@ -0,0 +1,149 @@
+// Command tzc compiles zone definitions in JSON to TZif files.
+//
+// Usage:
+//
+//	tzc [-o file] [-dir directory] [-profile name] [input.json]
+//
+// The input is either a single timezones.Template or an array of templates, encoded by encoding/json.
+// Offsets are in nanoseconds and change start times in RFC 3339 format, for example:
+//
+//	{
+//		"Name": "Custom/Zone",
+//		"Zones": [{"Name": "CUS", "Offset": 3600000000000}, {"Name": "CUD", "Offset": 7200000000000, "IsDST": true}],
+//		"Changes": [{"Start": "2024-03-31T01:00:00Z", "ZoneIndex": 1}],
+//		"Extend": "CUS-1CUD,M3.5.0/2,M10.5.0/3"
+//	}
+//
+// A single template is written to the file given by -o or to the standard output.
+// With -dir, each template is written to the file named by its Name within the directory,
+// creating subdirectories as needed, e.g. Custom/Zone.
+// If no input file is given, the standard input is read.
+//
+// Only JSON input is supported. YAML would need a parser from outside the standard library,
+// which the module does not depend on, and zic source needs a compiler for its rules,
+// which the timezones package does not have.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "tzc: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("tzc", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	output := flags.String("o", "", "write the single template to `file` instead of the standard output")
+	dir := flags.String("dir", "", "write templates to a tree rooted at `directory`")
+	profileName := flags.String("profile", "go", "validation profile: go, strict or lenient")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		return errors.New("at most one input file can be given")
+	}
+	if *output != "" && *dir != "" {
+		return errors.New("-o and -dir can't be used together")
+	}
+	profile, err := parseProfile(*profileName)
+	if err != nil {
+		return err
+	}
+
+	var input []byte
+	if flags.NArg() == 0 || flags.Arg(0) == "-" {
+		input, err = io.ReadAll(stdin)
+	} else {
+		input, err = os.ReadFile(flags.Arg(0))
+	}
+	if err != nil {
+		return err
+	}
+	templates, err := parseTemplates(input)
+	if err != nil {
+		return err
+	}
+
+	encoder := profile.Encoder()
+	encoder.Warn = func(err error) {
+		fmt.Fprintf(stderr, "tzc: warning: %v\n", err)
+	}
+	if *dir == "" {
+		if len(templates) != 1 {
+			return fmt.Errorf("input has %d templates, use -dir to write more than one", len(templates))
+		}
+		data, err := encoder.Encode(templates[0])
+		if err != nil {
+			return err
+		}
+		if *output == "" {
+			_, err = stdout.Write(data)
+			return err
+		}
+		return os.WriteFile(*output, data, 0o644)
+	}
+
+	for i := range templates {
+		name := templates[i].Name
+		if !fs.ValidPath(name) || name == "." {
+			return fmt.Errorf("template %d: name %q is not a valid relative path", i, name)
+		}
+		data, err := encoder.Encode(templates[i])
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(*dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseTemplates decodes a single template or an array of templates.
+func parseTemplates(input []byte) ([]timezones.Template, error) {
+	input = bytes.TrimSpace(input)
+	if len(input) > 0 && input[0] == '[' {
+		var templates []timezones.Template
+		if err := json.Unmarshal(input, &templates); err != nil {
+			return nil, err
+		}
+		return templates, nil
+	}
+	var template timezones.Template
+	if err := json.Unmarshal(input, &template); err != nil {
+		return nil, err
+	}
+	return []timezones.Template{template}, nil
+}
+
+func parseProfile(name string) (timezones.Profile, error) {
+	switch name {
+	case "go":
+		return timezones.GoCompatible, nil
+	case "strict":
+		return timezones.Strict, nil
+	case "lenient":
+		return timezones.Lenient, nil
+	default:
+		return 0, fmt.Errorf("unknown profile %q", name)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+const testInput = `{
+	"Name": "Custom/Zone",
+	"Zones": [{"Name": "CUS", "Offset": 3600000000000}, {"Name": "CUD", "Offset": 7200000000000, "IsDST": true}],
+	"Changes": [{"Start": "2024-03-31T01:00:00Z", "ZoneIndex": 1}],
+	"Extend": "CUS-1CUD,M3.5.0/2,M10.5.0/3"
+}`
+
+func TestRun_Single(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if err := run(nil, strings.NewReader(testInput), &stdout, &stderr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	template, err := timezones.LoadTZData(stdout.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(template.Zones) != 2 || template.Extend != "CUS-1CUD,M3.5.0/2,M10.5.0/3" {
+		t.Fatalf("unexpected template %+v", template)
+	}
+	if !template.Changes[0].Start.Equal(time.Date(2024, time.March, 31, 1, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected changes %+v", template.Changes)
+	}
+}
+
+func TestRun_Dir(t *testing.T) {
+	dir := t.TempDir()
+	input := "[" + testInput + `, {"Name": "Etc/Fixed", "Zones": [{"Name": "FIX", "Offset": 0}]}]`
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"-dir", dir}, strings.NewReader(input), &stdout, &stderr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range []string{"Custom/Zone", "Etc/Fixed"} {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := timezones.LoadTZData(data); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	tests := []struct {
+		args  []string
+		input string
+	}{
+		{args: nil, input: "[" + testInput + "," + testInput + "]"},
+		{args: []string{"-dir", "x"}, input: `{"Name": "../escape", "Extend": "UTC0"}`},
+		{args: []string{"-profile", "unknown"}, input: testInput},
+		{args: []string{"-profile", "strict"}, input: `{"Zones": [{"Name": "x"}]}`},
+		{args: nil, input: `{"Zones": 1}`},
+	}
+	for _, test := range tests {
+		var stdout, stderr bytes.Buffer
+		if err := run(test.args, strings.NewReader(test.input), &stdout, &stderr); err == nil {
+			t.Fatalf("expected error for %v %s", test.args, test.input)
+		}
+	}
+}
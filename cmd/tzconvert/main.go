@@ -0,0 +1,97 @@
+// Command tzconvert converts zone definitions between TZif and JSON.
+//
+// Usage:
+//
+//	tzconvert -to format [-o file] [input]
+//
+// The format of the input is detected automatically: data starting with the TZif magic is TZif,
+// anything else is parsed as a JSON-encoded timezones.Template, see cmd/tzc.
+// TZif is decoded with the timezones.GoCompatible decoder, so that system zoneinfo files can be converted.
+// The output format is either "tzif" or "json".
+// If no input file is given, the standard input is read. The output is written to the file
+// given by -o or to the standard output.
+//
+// Only TZif and JSON are supported. VTIMEZONE, moment-timezone data, zic source and MySQL time zone
+// tables are not, as the timezones package has no importers or exporters for them.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "tzconvert: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("tzconvert", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("to", "", "output `format`: tzif or json")
+	output := flags.String("o", "", "write the output to `file` instead of the standard output")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		return errors.New("at most one input file can be given")
+	}
+
+	var input []byte
+	var err error
+	if flags.NArg() == 0 || flags.Arg(0) == "-" {
+		input, err = io.ReadAll(stdin)
+	} else {
+		input, err = os.ReadFile(flags.Arg(0))
+	}
+	if err != nil {
+		return err
+	}
+	template, err := decode(input)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch *format {
+	case "tzif":
+		data, err = timezones.TZData(*template)
+	case "json":
+		data, err = json.MarshalIndent(template, "", "\t")
+		data = append(data, '\n')
+	case "":
+		return errors.New("output format must be given with -to")
+	default:
+		return fmt.Errorf("unsupported output format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*output, data, 0o644)
+}
+
+// decode decodes TZif or JSON input.
+func decode(input []byte) (*timezones.Template, error) {
+	if bytes.HasPrefix(input, []byte("TZif")) {
+		d := timezones.GoCompatible.Decoder()
+		return d.Decode(input)
+	}
+	var template timezones.Template
+	if err := json.Unmarshal(input, &template); err != nil {
+		return nil, fmt.Errorf("input is neither TZif nor JSON: %w", err)
+	}
+	return &template, nil
+}
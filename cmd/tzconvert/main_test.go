@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func TestRun_RoundTrip(t *testing.T) {
+	template := timezones.Template{
+		Zones: []timezones.Zone{
+			{Name: "CET", Offset: time.Hour},
+			{Name: "CEST", Offset: 2 * time.Hour, IsDST: true},
+		},
+		Changes: []timezones.Change{{Start: time.Date(2021, time.March, 28, 1, 0, 0, 0, time.UTC), ZoneIndex: 1}},
+		Extend:  "CET-1CEST,M3.5.0,M10.5.0/3",
+	}
+	tzdata, err := timezones.TZData(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var jsonOut, stderr bytes.Buffer
+	if err := run([]string{"-to", "json"}, bytes.NewReader(tzdata), &jsonOut, &stderr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(jsonOut.String(), `"Extend": "CET-1CEST,M3.5.0,M10.5.0/3"`) {
+		t.Fatalf("unexpected JSON:\n%s", jsonOut.String())
+	}
+
+	var tzifOut bytes.Buffer
+	if err := run([]string{"-to", "tzif"}, &jsonOut, &tzifOut, &stderr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := timezones.LoadTZData(tzifOut.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := timezones.LoadTZData(tzdata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestRun_Indicators(t *testing.T) {
+	tzdata, err := timezones.TZData(timezones.Template{
+		Zones:   []timezones.Zone{{Name: "CET", Offset: time.Hour}, {Name: "CEST", Offset: 2 * time.Hour, IsDST: true}},
+		Changes: []timezones.Change{{Start: time.Date(2021, time.March, 28, 1, 0, 0, 0, time.UTC), ZoneIndex: 1}},
+		Extend:  "CET-1CEST,M3.5.0,M10.5.0/3",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// zic writes 0 indicators, which are at the end of the data block, before the footer.
+	footer := bytes.LastIndexByte(tzdata[:len(tzdata)-1], '\n')
+	tzdata[footer-1], tzdata[footer-2] = 0, 0
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"-to", "json"}, bytes.NewReader(tzdata), &stdout, &stderr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), `"Extend": "CET-1CEST,M3.5.0,M10.5.0/3"`) {
+		t.Fatalf("unexpected JSON:\n%s", stdout.String())
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	for _, args := range [][]string{nil, {"-to", "vtimezone"}} {
+		var stdout, stderr bytes.Buffer
+		if err := run(args, strings.NewReader(`{"Extend": "UTC0"}`), &stdout, &stderr); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"-to", "json"}, strings.NewReader("garbage"), &stdout, &stderr); err == nil {
+		t.Fatal("expected error for garbage input")
+	}
+}
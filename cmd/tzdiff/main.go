@@ -0,0 +1,162 @@
+// Command tzdiff compares the behavior of two TZif files or two trees of TZif files.
+//
+// Usage:
+//
+//	tzdiff [-from year] [-to year] [-json] old new
+//
+// If old and new are files, tzdiff prints the intervals where they use different zones.
+// If they are directories, it loads all TZif files in them, see timezones.LoadAll, and prints
+// the differences of files present in both trees along with the files present in only one of them.
+// Only the years from -from up to, but not including, -to are compared.
+//
+// With -json, tzdiff prints a JSON array with an object for each file that differs instead, e.g.
+//
+//	[{"name":"Europe/Zone","status":"changed","differences":[{"start":"2021-03-28T01:00:00Z",
+//	  "end":"2021-10-31T01:00:00Z","zone":{"name":"CEST","offset":7200,"dst":true},
+//	  "other":{"name":"CET","offset":3600,"dst":false}}]}]
+//
+// The status is "added", "removed" or "changed"; differences are only present for changed files,
+// see timezones.Difference.MarshalJSON. The name is empty when comparing two files.
+//
+// tzdiff exits with status 1 if there are differences and 2 on errors.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func main() {
+	differ, err := run(os.Args[1:], os.Stdout, os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tzdiff: %v\n", err)
+		os.Exit(2)
+	}
+	if differ {
+		os.Exit(1)
+	}
+}
+
+// run returns whether there are differences.
+func run(args []string, stdout, stderr io.Writer) (bool, error) {
+	flags := flag.NewFlagSet("tzdiff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	fromYear := flags.Int("from", 1800, "first `year` to compare")
+	toYear := flags.Int("to", 2100, "`year` to stop comparing at")
+	jsonOutput := flags.Bool("json", false, "print the differences as JSON")
+	if err := flags.Parse(args); err != nil {
+		return false, err
+	}
+	if flags.NArg() != 2 {
+		return false, errors.New("expected two files or directories")
+	}
+	from := time.Date(*fromYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(*toYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	oldTemplates, err := load(flags.Arg(0))
+	if err != nil {
+		return false, err
+	}
+	newTemplates, err := load(flags.Arg(1))
+	if err != nil {
+		return false, err
+	}
+
+	names := make([]string, 0, len(oldTemplates)+len(newTemplates))
+	for name := range oldTemplates {
+		names = append(names, name)
+	}
+	for name := range newTemplates {
+		if oldTemplates[name] == nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	reports := make([]fileReport, 0, len(names))
+	for _, name := range names {
+		oldTemplate, newTemplate := oldTemplates[name], newTemplates[name]
+		switch {
+		case newTemplate == nil:
+			reports = append(reports, fileReport{Name: name, Status: "removed"})
+		case oldTemplate == nil:
+			reports = append(reports, fileReport{Name: name, Status: "added"})
+		default:
+			if diffs := oldTemplate.Diff(newTemplate, from, to); len(diffs) > 0 {
+				reports = append(reports, fileReport{Name: name, Status: "changed", Differences: diffs})
+			}
+		}
+	}
+
+	if *jsonOutput {
+		data, err := json.Marshal(reports)
+		if err != nil {
+			return false, err
+		}
+		if _, err := fmt.Fprintf(stdout, "%s\n", data); err != nil {
+			return false, err
+		}
+		return len(reports) > 0, nil
+	}
+	for _, r := range reports {
+		if r.Status != "changed" {
+			fmt.Fprintf(stdout, "%s: %s\n", r.Name, r.Status)
+			continue
+		}
+		prefix := ""
+		if r.Name != "" {
+			prefix = r.Name + ": "
+		}
+		for _, d := range r.Differences {
+			fmt.Fprintf(stdout, "%s%s to %s: %s -> %s\n", prefix, d.Start.Format(time.RFC3339),
+				d.End.Format(time.RFC3339), formatZone(d.Zone), formatZone(d.Other))
+		}
+	}
+	return len(reports) > 0, nil
+}
+
+// fileReport describes the differences of a single file.
+type fileReport struct {
+	Name        string                 `json:"name"`
+	Status      string                 `json:"status"`
+	Differences []timezones.Difference `json:"differences,omitempty"`
+}
+
+// load loads a single file, keyed by an empty name, or all files in a directory.
+// Like LoadAll, it ignores standard/wall and UT/local indicators, so that system zoneinfo files can be compared.
+func load(path string) (map[string]*timezones.Template, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		return timezones.LoadAll(os.DirFS(path), timezones.LoadOptions{})
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	d := timezones.GoCompatible.Decoder()
+	template, err := d.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return map[string]*timezones.Template{"": template}, nil
+}
+
+func formatZone(z timezones.Zone) string {
+	offset := timezones.OffsetOf(z)
+	dst := ""
+	if z.IsDST {
+		dst = " DST"
+	}
+	return fmt.Sprintf("%s (UTC%s%s)", z.Name, offset, dst)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func writeTZData(t *testing.T, path string, template timezones.Template) {
+	t.Helper()
+	data, err := timezones.TZData(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRun_Indicators(t *testing.T) {
+	data, err := timezones.TZData(timezones.Template{
+		Zones:   []timezones.Zone{{Name: "CET", Offset: time.Hour}, {Name: "CEST", Offset: 2 * time.Hour, IsDST: true}},
+		Changes: []timezones.Change{{Start: time.Date(2021, time.March, 28, 1, 0, 0, 0, time.UTC), ZoneIndex: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// zic writes 0 indicators, which are at the end of the data block, before the footer.
+	footer := bytes.LastIndexByte(data[:len(data)-1], '\n')
+	data[footer-1], data[footer-2] = 0, 0
+	dir := t.TempDir()
+	file := filepath.Join(dir, "Europe", "Zone")
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	for _, args := range [][]string{{file, file}, {dir, dir}} {
+		differ, err := run(args, &stdout, &stderr)
+		if err != nil || differ {
+			t.Fatalf("%v: expected no differences, got %v %v", args, differ, err)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	cet := timezones.Zone{Name: "CET", Offset: time.Hour}
+	oldDir, newDir := t.TempDir(), t.TempDir()
+	writeTZData(t, filepath.Join(oldDir, "Europe", "Zone"), timezones.Template{
+		Zones:  []timezones.Zone{cet},
+		Extend: "CET-1CEST,M3.5.0,M10.5.0/3",
+	})
+	writeTZData(t, filepath.Join(newDir, "Europe", "Zone"), timezones.Template{
+		Zones:   []timezones.Zone{cet, {Name: "CEST", Offset: 2 * time.Hour, IsDST: true}},
+		Changes: []timezones.Change{{Start: time.Date(2021, time.March, 28, 1, 0, 0, 0, time.UTC), ZoneIndex: 1}},
+		Extend:  "CET-1CEST,M3.5.0,M10.5.0/3",
+	})
+	writeTZData(t, filepath.Join(oldDir, "Etc", "Old"), timezones.Template{Zones: []timezones.Zone{cet}})
+	writeTZData(t, filepath.Join(newDir, "Etc", "New"), timezones.Template{Zones: []timezones.Zone{cet}})
+
+	var stdout, stderr bytes.Buffer
+	differ, err := run([]string{"-from", "2020", "-to", "2022", oldDir, newDir}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "Etc/New: added\n" +
+		"Etc/Old: removed\n" +
+		"Europe/Zone: 2020-03-29T01:00:00Z to 2020-10-25T01:00:00Z: CEST (UTC+02:00 DST) -> CET (UTC+01:00)\n"
+	if !differ || stdout.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, stdout.String())
+	}
+
+	stdout.Reset()
+	file := filepath.Join(oldDir, "Europe", "Zone")
+	differ, err = run([]string{file, file}, &stdout, &stderr)
+	if err != nil || differ || stdout.Len() != 0 {
+		t.Fatalf("expected no differences, got %v %v %q", differ, err, stdout.String())
+	}
+
+	if _, err := run([]string{file}, &stdout, &stderr); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestRun_JSON(t *testing.T) {
+	cet := timezones.Zone{Name: "CET", Offset: time.Hour}
+	oldDir, newDir := t.TempDir(), t.TempDir()
+	writeTZData(t, filepath.Join(oldDir, "Europe", "Zone"), timezones.Template{
+		Zones:  []timezones.Zone{cet},
+		Extend: "CET-1CEST,M3.5.0,M10.5.0/3",
+	})
+	writeTZData(t, filepath.Join(newDir, "Europe", "Zone"), timezones.Template{Zones: []timezones.Zone{cet}})
+	writeTZData(t, filepath.Join(newDir, "Etc", "New"), timezones.Template{Zones: []timezones.Zone{cet}})
+
+	var stdout, stderr bytes.Buffer
+	differ, err := run([]string{"-from", "2021", "-to", "2022", "-json", oldDir, newDir}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `[{"name":"Etc/New","status":"added"},{"name":"Europe/Zone","status":"changed","differences":[` +
+		`{"start":"2021-03-28T01:00:00Z","end":"2021-10-31T01:00:00Z",` +
+		`"zone":{"name":"CEST","offset":7200,"dst":true},"other":{"name":"CET","offset":3600,"dst":false}}]}]` + "\n"
+	if !differ || stdout.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, stdout.String())
+	}
+
+	stdout.Reset()
+	differ, err = run([]string{"-json", oldDir, oldDir}, &stdout, &stderr)
+	if err != nil || differ || stdout.String() != "[]\n" {
+		t.Fatalf("expected no differences, got %v %v %q", differ, err, stdout.String())
+	}
+}
@@ -0,0 +1,69 @@
+// Command tzdump prints TZif files in human-readable form.
+//
+// Usage:
+//
+//	tzdump [-raw] file...
+//
+// For each file, tzdump prints the TZif version and the zones, transitions, footer and leap seconds
+// as decoded by the timezones.GoCompatible decoder, which ignores standard/wall and UT/local indicators
+// like Go does.
+// With -raw, the zones are printed in the order they are stored in the file, see timezones.Decoder.Raw.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "tzdump: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("tzdump", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	raw := flags.Bool("raw", false, "print zones in the order stored in the file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return errors.New("no files given")
+	}
+	d := timezones.GoCompatible.Decoder()
+	d.Raw = *raw
+	for i, path := range flags.Args() {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		if err := dump(stdout, &d, path); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func dump(w io.Writer, d *timezones.Decoder, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	info, err := timezones.QuickInfo(data)
+	if err != nil {
+		return err
+	}
+	template, err := d.Decode(data)
+	if err != nil {
+		return err
+	}
+	template.Name = path
+	fmt.Fprintf(w, "Version: %d\n", info.Version)
+	return template.Dump(w)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func TestRun(t *testing.T) {
+	data, err := timezones.TZData(timezones.Template{
+		Zones:   []timezones.Zone{{Name: "CET", Offset: time.Hour}, {Name: "CEST", Offset: 2 * time.Hour, IsDST: true}},
+		Changes: []timezones.Change{{Start: time.Unix(1000, 0), ZoneIndex: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "zone")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{path}, &stdout, &stderr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, s := range []string{"Version: 3\n", "Zones: 2\n", "1970-01-01T00:16:40Z   1 CEST   +02:00 dst\n"} {
+		if !strings.Contains(stdout.String(), s) {
+			t.Fatalf("expected output to contain %q, got:\n%s", s, stdout.String())
+		}
+	}
+
+	stdout.Reset()
+	if err := run([]string{"-raw", path}, &stdout, &stderr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Zones: 3\n") {
+		t.Fatalf("expected synthetic zone in raw output, got:\n%s", stdout.String())
+	}
+
+	// zic writes 0 indicators and right/ zones have leap seconds.
+	e := timezones.Encoder{Indicators: timezones.WallIndicators}
+	data, err = e.Encode(timezones.Template{
+		Zones:       []timezones.Zone{{Name: "UTC"}},
+		Extend:      "UTC0",
+		LeapSeconds: []timezones.LeapSecond{{Occurrence: time.Unix(78796800, 0), Correction: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	if err := run([]string{path}, &stdout, &stderr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Leap seconds: 1\n  1972-07-01T00:00:00Z +1\n") {
+		t.Fatalf("expected leap seconds in output, got:\n%s", stdout.String())
+	}
+
+	if err := run(nil, &stdout, &stderr); err == nil {
+		t.Fatal("expected error without files")
+	}
+	if err := run([]string{filepath.Join(t.TempDir(), "missing")}, &stdout, &stderr); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
@@ -0,0 +1,142 @@
+// Command tzlocal prints information about the local time zone configuration of the machine.
+//
+// Usage:
+//
+//	tzlocal [-file path] [-n count] [-zoneinfo zip]
+//
+// tzlocal prints the file the local time zone is read from (/etc/localtime by default) and where it points to
+// if it is a symbolic link, the IANA name of the zone derived from that path, the TZif version,
+// the upcoming transitions and whether the zone behaves differently than the same zone in Go's
+// lib/time/zoneinfo.zip, if that is available.
+package main
+
+import (
+	"archive/zip"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func main() {
+	if err := run(os.Args[1:], time.Now(), os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "tzlocal: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, now time.Time, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("tzlocal", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	file := flags.String("file", "/etc/localtime", "`path` of the local time zone file")
+	count := flags.Int("n", 5, "number of upcoming transitions to print")
+	zoneinfo := flags.String("zoneinfo", filepath.Join(runtime.GOROOT(), "lib", "time", "zoneinfo.zip"),
+		"`path` of the zip file with the zones to compare with")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "File: %s\n", *file)
+	name := ""
+	if target, err := filepath.EvalSymlinks(*file); err == nil && target != *file {
+		fmt.Fprintf(stdout, "Points to: %s\n", target)
+		name = zoneName(target)
+	}
+	if name != "" {
+		fmt.Fprintf(stdout, "Name: %s\n", name)
+	} else {
+		fmt.Fprintln(stdout, "Name: unknown")
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+	info, err := timezones.QuickInfo(data)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Version: %d\n", info.Version)
+	if info.Extend != "" {
+		fmt.Fprintf(stdout, "TZ string: %s\n", info.Extend)
+	}
+	d := timezones.GoCompatible.Decoder()
+	template, err := d.Decode(data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, "Upcoming transitions:")
+	for _, z := range upcoming(template, now, *count) {
+		fmt.Fprintf(stdout, "  %s %s %s\n", z.start.UTC().Format(time.RFC3339), z.Name, timezones.OffsetOf(z.Zone))
+	}
+
+	if name == "" {
+		return nil
+	}
+	bundled, err := loadBundled(*zoneinfo, name)
+	if err != nil {
+		fmt.Fprintf(stdout, "Bundled: not available (%v)\n", err)
+		return nil
+	}
+	diffs := template.Diff(bundled, now, now.AddDate(10, 0, 0))
+	if len(diffs) == 0 {
+		fmt.Fprintln(stdout, "Bundled: same for the next 10 years")
+		return nil
+	}
+	fmt.Fprintf(stdout, "Bundled: differs from %s\n", diffs[0].Start.Format(time.RFC3339))
+	return nil
+}
+
+// zoneName derives the IANA name from the path of a zoneinfo file, e.g. /usr/share/zoneinfo/Europe/Bratislava.
+func zoneName(path string) string {
+	path = filepath.ToSlash(path)
+	i := strings.LastIndex(path, "/zoneinfo/")
+	if i < 0 {
+		return ""
+	}
+	name := path[i+len("/zoneinfo/"):]
+	// Some systems have posix/ and right/ variants of the tree.
+	for _, prefix := range []string{"posix/", "right/"} {
+		if strings.HasPrefix(name, prefix) {
+			return name[len(prefix):]
+		}
+	}
+	return name
+}
+
+// transition is a zone that starts at the given time.
+type transition struct {
+	timezones.Zone
+	start time.Time
+}
+
+// upcoming returns up to n transitions after now, generating them from Extend if needed.
+func upcoming(template *timezones.Template, now time.Time, n int) []transition {
+	fat, err := template.MaterializeExtend(now.AddDate(n+1, 0, 0))
+	if err != nil {
+		fat = *template
+	}
+	var transitions []transition
+	for _, c := range fat.Changes {
+		if c.Start.After(now) && len(transitions) < n {
+			transitions = append(transitions, transition{Zone: fat.Zones[c.ZoneIndex], start: c.Start})
+		}
+	}
+	return transitions
+}
+
+func loadBundled(zipPath, name string) (*timezones.Template, error) {
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return timezones.LoadFile(zr, name)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func TestRun(t *testing.T) {
+	template := timezones.Template{
+		Zones:   []timezones.Zone{{Name: "CET", Offset: time.Hour}},
+		Changes: []timezones.Change{{Start: time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC)}},
+		Extend:  "CET-1CEST,M3.5.0,M10.5.0/3",
+	}
+	data, err := timezones.TZData(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// zic writes 0 indicators, which are at the end of the data block, before the footer.
+	footer := bytes.LastIndexByte(data[:len(data)-1], '\n')
+	data[footer-1], data[footer-2] = 0, 0
+	dir := t.TempDir()
+	zonePath := filepath.Join(dir, "zoneinfo", "Europe", "Zone")
+	if err := os.MkdirAll(filepath.Dir(zonePath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(zonePath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	localtime := filepath.Join(dir, "localtime")
+	if err := os.Symlink(zonePath, localtime); err != nil {
+		t.Skipf("symbolic links not supported: %v", err)
+	}
+
+	// The bundled zone has no DST.
+	template.Extend = "CET-1"
+	bundledData, err := timezones.TZData(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zipPath := filepath.Join(dir, "zoneinfo.zip")
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("Europe/Zone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(bundledData); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(zipPath, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if err := run([]string{"-file", localtime, "-n", "2", "-zoneinfo", zipPath}, now, &stdout, &stderr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, s := range []string{
+		"Name: Europe/Zone\n",
+		"Version: 3\n",
+		"  2024-03-31T01:00:00Z CEST +02:00\n  2024-10-27T01:00:00Z CET +01:00\n",
+		"Bundled: differs from 2024-03-31T01:00:00Z\n",
+	} {
+		if !strings.Contains(stdout.String(), s) {
+			t.Fatalf("expected output to contain %q, got:\n%s", s, stdout.String())
+		}
+	}
+}
+
+func TestZoneName(t *testing.T) {
+	tests := map[string]string{
+		"/usr/share/zoneinfo/Europe/Bratislava":      "Europe/Bratislava",
+		"/usr/share/zoneinfo/posix/America/New_York": "America/New_York",
+		"/usr/share/zoneinfo/right/Europe/Berlin":    "Europe/Berlin",
+		"/var/db/timezone/zoneinfo/Etc/UTC":          "Etc/UTC",
+		"/etc/localtime":                             "",
+	}
+	for path, expected := range tests {
+		if got := zoneName(path); got != expected {
+			t.Fatalf("%s: expected %q, got %q", path, expected, got)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Command tzserve serves TZif files over HTTP.
+//
+// Usage:
+//
+//	tzserve [-addr address] [-reload interval] data
+//
+// The data is a directory, a zip file like Go's lib/time/zoneinfo.zip or a tarball (.tar, .tar.gz or .tgz)
+// containing a tree of TZif files. The files are loaded with timezones.LoadAll, so system zoneinfo
+// directories work as well, and re-encoded with timezones.TZData, so clients always get data
+// in the same format.
+//
+// GET /zones returns the names of all zones, one per line.
+// GET /zones/{name} returns the TZif data of the zone, e.g. /zones/Europe/Bratislava.
+//
+// With -reload, the data is reloaded periodically. If loading fails, the previous data is kept.
+// The tzdist protocol (RFC 7808) is not implemented.
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing/fstest"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "tzserve: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stderr io.Writer) error {
+	flags := flag.NewFlagSet("tzserve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	addr := flags.String("addr", "localhost:8080", "`address` to listen on")
+	reload := flags.Duration("reload", 0, "reload the data every `interval`, zero disables reloading")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("expected a single data directory or zip file")
+	}
+	s := &server{source: flags.Arg(0)}
+	if err := s.load(); err != nil {
+		return err
+	}
+	if *reload > 0 {
+		go func() {
+			for range time.Tick(*reload) {
+				if err := s.load(); err != nil {
+					log.Printf("reload failed, keeping previous data: %v", err)
+				}
+			}
+		}()
+	}
+	return http.ListenAndServe(*addr, s)
+}
+
+// server serves the zones loaded from source.
+type server struct {
+	source string
+
+	mu    sync.RWMutex
+	zones map[string][]byte
+	names []byte
+}
+
+// load loads the zones from the source and replaces the zones being served.
+func (s *server) load() error {
+	templates, err := loadSource(s.source)
+	if err != nil {
+		return err
+	}
+	zones := make(map[string][]byte, len(templates))
+	names := make([]string, 0, len(templates))
+	for name, template := range templates {
+		data, err := timezones.TZData(*template)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		zones[name] = data
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var list strings.Builder
+	for _, name := range names {
+		list.WriteString(name)
+		list.WriteByte('\n')
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.zones = zones
+	s.names = []byte(list.String())
+	return nil
+}
+
+func loadSource(source string) (map[string]*timezones.Template, error) {
+	fi, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+	var fsys fs.FS
+	switch {
+	case fi.IsDir():
+		fsys = os.DirFS(source)
+	case strings.HasSuffix(source, ".tar") || strings.HasSuffix(source, ".tar.gz") || strings.HasSuffix(source, ".tgz"):
+		if fsys, err = loadTar(source); err != nil {
+			return nil, err
+		}
+	default:
+		zr, err := zip.OpenReader(source)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		fsys = zr
+	}
+	return timezones.LoadAll(fsys, timezones.LoadOptions{})
+}
+
+// loadTar reads the regular files of the tarball at source into memory.
+// Hard links and symbolic links to regular files within the tarball are resolved to copies,
+// other entries are skipped.
+func loadTar(source string) (fs.FS, error) {
+	f, err := os.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if !strings.HasSuffix(source, ".tar") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	files := make(fstest.MapFS)
+	links := make(map[string]string)
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+		name := path.Clean(h.Name)
+		if !fs.ValidPath(name) || name == "." {
+			continue
+		}
+		switch h.Typeflag {
+		case tar.TypeReg:
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", source, err)
+			}
+			files[name] = &fstest.MapFile{Data: data, Mode: 0o644}
+		case tar.TypeLink:
+			links[name] = path.Clean(h.Linkname)
+		case tar.TypeSymlink:
+			links[name] = path.Join(path.Dir(name), h.Linkname)
+		}
+	}
+	for name, target := range links {
+		// Follow chains of links, but not cycles.
+		for i := 0; i < len(links); i++ {
+			next, ok := links[target]
+			if !ok {
+				break
+			}
+			target = next
+		}
+		if file, ok := files[target]; ok {
+			files[name] = file
+		}
+	}
+	return files, nil
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.mu.RLock()
+	zones, names := s.zones, s.names
+	s.mu.RUnlock()
+
+	if r.URL.Path == "/zones" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write(names)
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, "/zones/")
+	data, ok := zones[name]
+	if name == r.URL.Path || !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(data)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	data, err := timezones.TZData(timezones.Template{
+		Zones: []timezones.Zone{{Name: "MyFixed", Offset: time.Hour}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "Etc"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Etc", "MyFixed"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s := &server{source: dir}
+	if err := s.load(); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	get := func(path string) (int, []byte) {
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, body
+	}
+
+	if status, body := get("/zones"); status != http.StatusOK || string(body) != "Etc/MyFixed\n" {
+		t.Fatalf("unexpected list %d %q", status, body)
+	}
+	status, body := get("/zones/Etc/MyFixed")
+	if status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+	if _, err := time.LoadLocationFromTZData("Etc/MyFixed", body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, path := range []string{"/zones/Etc/Missing", "/Etc/MyFixed"} {
+		if status, _ := get(path); status != http.StatusNotFound {
+			t.Fatalf("%s: expected not found, got %d", path, status)
+		}
+	}
+
+	// A failed reload keeps the previous data.
+	s.source = filepath.Join(dir, "missing")
+	if err := s.load(); err == nil {
+		t.Fatal("expected error")
+	}
+	if status, _ := get("/zones/Etc/MyFixed"); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+}
+
+func TestLoadSource_Tar(t *testing.T) {
+	data, err := timezones.TZData(timezones.Template{
+		Zones:   []timezones.Zone{{Name: "CET", Offset: time.Hour}, {Name: "CEST", Offset: 2 * time.Hour, IsDST: true}},
+		Changes: []timezones.Change{{Start: time.Date(2021, time.March, 28, 1, 0, 0, 0, time.UTC), ZoneIndex: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// zic writes 0 indicators, which are at the end of the data block, before the footer.
+	footer := bytes.LastIndexByte(data[:len(data)-1], '\n')
+	data[footer-1], data[footer-2] = 0, 0
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, h := range []*tar.Header{
+		{Name: "./Europe/", Typeflag: tar.TypeDir, Mode: 0o755},
+		{Name: "./Europe/Berlin", Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(data))},
+		{Name: "./Europe/Busingen", Typeflag: tar.TypeLink, Linkname: "./Europe/Berlin"},
+		{Name: "./Europe/Copenhagen", Typeflag: tar.TypeSymlink, Linkname: "Busingen"},
+		{Name: "./Europe/Dangling", Typeflag: tar.TypeSymlink, Linkname: "Missing"},
+		{Name: "../Outside", Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(data))},
+	} {
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+		if h.Typeflag == tar.TypeReg {
+			if _, err := tw.Write(data); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	source := filepath.Join(t.TempDir(), "zoneinfo.tar.gz")
+	if err := os.WriteFile(source, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	templates, err := loadSource(source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(templates) != 3 {
+		t.Fatalf("expected 3 templates, got %v", templates)
+	}
+	for _, name := range []string{"Europe/Berlin", "Europe/Busingen", "Europe/Copenhagen"} {
+		if templates[name] == nil {
+			t.Fatalf("%s not loaded", name)
+		}
+	}
+}
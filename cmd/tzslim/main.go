@@ -0,0 +1,108 @@
+// Command tzslim rewrites TZif files to be slim or fat, like zic -b slim and zic -b fat.
+//
+// Usage:
+//
+//	tzslim [-fat] [-until year] path...
+//
+// Each path is a TZif file or a directory with a tree of TZif files, which are all rewritten in place.
+// The files are decoded with the timezones.GoCompatible decoder, so system zoneinfo files can be rewritten.
+// By default, transitions that the TZ string in the footer predicts are removed,
+// see timezones.Template.CompressToExtend.
+// With -fat, transitions are generated from the TZ string until the start of the given year instead,
+// see timezones.Template.MaterializeExtend.
+// The behavior of the rewritten files does not change.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "tzslim: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stderr io.Writer) error {
+	flags := flag.NewFlagSet("tzslim", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	fat := flags.Bool("fat", false, "generate transitions from the TZ string instead of removing them")
+	untilYear := flags.Int("until", 2038, "with -fat, generate transitions until the start of `year`")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return errors.New("no files given")
+	}
+	until := time.Date(*untilYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+	rewrite := func(template *timezones.Template) (timezones.Template, error) {
+		if *fat {
+			return template.MaterializeExtend(until)
+		}
+		return template.CompressToExtend(), nil
+	}
+
+	for _, root := range flags.Args() {
+		fi, err := os.Stat(root)
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			if err := rewriteFile(root, rewrite); err != nil {
+				return fmt.Errorf("%s: %w", root, err)
+			}
+			continue
+		}
+		templates, err := timezones.LoadAll(os.DirFS(root), timezones.LoadOptions{})
+		if err != nil {
+			return err
+		}
+		for name, template := range templates {
+			path := filepath.Join(root, filepath.FromSlash(name))
+			if err := writeTemplate(path, template, rewrite); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+func rewriteFile(path string, rewrite func(*timezones.Template) (timezones.Template, error)) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	d := timezones.GoCompatible.Decoder()
+	template, err := d.Decode(data)
+	if err != nil {
+		return err
+	}
+	return writeTemplate(path, template, rewrite)
+}
+
+func writeTemplate(path string, template *timezones.Template,
+	rewrite func(*timezones.Template) (timezones.Template, error)) error {
+	rewritten, err := rewrite(template)
+	if err != nil {
+		return err
+	}
+	data, err := timezones.TZData(rewritten)
+	if err != nil {
+		return err
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, fi.Mode()&fs.ModePerm)
+}
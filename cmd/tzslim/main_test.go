@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func loadFile(t *testing.T, path string) *timezones.Template {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template, err := timezones.LoadTZData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return template
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Europe", "Zone")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	original := timezones.Template{
+		Zones:   []timezones.Zone{{Name: "CET", Offset: time.Hour}},
+		Changes: []timezones.Change{{Start: time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC)}},
+		Extend:  "CET-1CEST,M3.5.0,M10.5.0/3",
+	}
+	data, err := timezones.TZData(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stderr bytes.Buffer
+	if err := run([]string{"-fat", "-until", "2000", dir}, &stderr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(loadFile(t, path).Changes); n != 21 {
+		t.Fatalf("expected 21 changes in fat file, got %d", n)
+	}
+
+	if err := run([]string{path}, &stderr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	slim := loadFile(t, path)
+	if len(slim.Changes) != 1 {
+		t.Fatalf("expected 1 change in slim file, got %d", len(slim.Changes))
+	}
+	from := time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if diffs := original.Diff(slim, from, to); len(diffs) != 0 {
+		t.Fatalf("unexpected differences %+v", diffs)
+	}
+
+	// zic writes 0 indicators, which are at the end of the data block, before the footer.
+	footer := bytes.LastIndexByte(data[:len(data)-1], '\n')
+	data[footer-1], data[footer-2] = 0, 0
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := run([]string{path}, &stderr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diffs := original.Diff(loadFile(t, path), from, to); len(diffs) != 0 {
+		t.Fatalf("unexpected differences %+v", diffs)
+	}
+
+	if err := run(nil, &stderr); err == nil {
+		t.Fatal("expected error without files")
+	}
+}
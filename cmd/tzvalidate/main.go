@@ -0,0 +1,139 @@
+// Command tzvalidate checks TZif files for problems.
+//
+// Usage:
+//
+//	tzvalidate [-profile name] [-werror] path...
+//
+// Each path is a TZif file or a directory, which is searched for TZif files recursively.
+// For each file, tzvalidate
+//
+//   - decodes the file with the decoder of the validation profile, see timezones.Profile,
+//   - checks that Go's time package interprets the file the same way as RFC 8536, see timezones.AnalyzeCompatibility,
+//   - validates the decoded zones, changes and TZ string with the encoder of the profile, and
+//   - checks that re-encoding the file does not change its behavior, see timezones.VerifyRoundTrip.
+//
+// Problems are printed one per line prefixed with the path of the file.
+// Warnings of the encoder, such as a last change that disagrees with the TZ string in non-strict profiles,
+// are printed too, but only fail the validation with -werror.
+// Standard/wall and UT/local indicators other than 1, which zic writes for most zones, are printed as notes
+// and never fail the validation: only the "posixrules" file uses them and Go ignores them.
+// tzvalidate exits with status 1 if any file fails the validation and 2 on other errors.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func main() {
+	ok, err := run(os.Args[1:], os.Stdout, os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tzvalidate: %v\n", err)
+		os.Exit(2)
+	}
+	if !ok {
+		os.Exit(1)
+	}
+}
+
+// run returns whether all files passed the validation.
+func run(args []string, stdout, stderr io.Writer) (bool, error) {
+	flags := flag.NewFlagSet("tzvalidate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	profileName := flags.String("profile", "strict", "validation profile: go, strict or lenient")
+	werror := flags.Bool("werror", false, "treat warnings as errors")
+	if err := flags.Parse(args); err != nil {
+		return false, err
+	}
+	if flags.NArg() == 0 {
+		return false, errors.New("no files given")
+	}
+	var profile timezones.Profile
+	switch *profileName {
+	case "go":
+		profile = timezones.GoCompatible
+	case "strict":
+		profile = timezones.Strict
+	case "lenient":
+		profile = timezones.Lenient
+	default:
+		return false, fmt.Errorf("unknown profile %q", *profileName)
+	}
+
+	ok := true
+	for _, root := range flags.Args() {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if path != root && !bytes.HasPrefix(data, []byte("TZif")) {
+				// Skip zone.tab and similar files in directories.
+				return nil
+			}
+			problems, warnings, notes := validate(data, profile)
+			for _, n := range notes {
+				fmt.Fprintf(stdout, "%s: note: %s\n", path, n)
+			}
+			for _, w := range warnings {
+				fmt.Fprintf(stdout, "%s: warning: %v\n", path, w)
+			}
+			for _, p := range problems {
+				fmt.Fprintf(stdout, "%s: %v\n", path, p)
+			}
+			if len(problems) > 0 || *werror && len(warnings) > 0 {
+				ok = false
+			}
+			return nil
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+	return ok, nil
+}
+
+// validate returns the problems, warnings and notes found in the TZif data.
+func validate(data []byte, profile timezones.Profile) (problems, warnings []error, notes []string) {
+	d := profile.Decoder()
+	// The indicators are reported as a note below.
+	d.IgnoreIndicators = true
+	template, err := d.Decode(data)
+	if err != nil {
+		return []error{err}, nil, nil
+	}
+	report, err := timezones.AnalyzeCompatibility(data)
+	if err != nil {
+		problems = append(problems, err)
+	} else {
+		if report.Divergent() {
+			problems = append(problems, fmt.Errorf("Go interprets the data differently: %v", report))
+		}
+		if report.NonUTIndicators > 0 {
+			notes = append(notes, fmt.Sprintf("%d standard/wall or UT/local indicators are not 1, "+
+				"which only matters if the file is used as posixrules", report.NonUTIndicators))
+		}
+	}
+	e := profile.Encoder()
+	e.Warn = func(err error) {
+		warnings = append(warnings, err)
+	}
+	if _, err := e.Encode(*template); err != nil {
+		problems = append(problems, err)
+	}
+	if err := timezones.VerifyRoundTrip(data); err != nil {
+		problems = append(problems, err)
+	}
+	return problems, warnings, notes
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, template timezones.Template) string {
+		data, err := timezones.TZData(template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	good := write("good", timezones.Template{
+		Zones:  []timezones.Zone{{Name: "CET", Offset: time.Hour}},
+		Extend: "CET-1CEST,M3.5.0,M10.5.0/3",
+	})
+	short := write("short", timezones.Template{
+		Zones: []timezones.Zone{{Name: "X", Offset: time.Hour}},
+	})
+	discontinuous := write("discontinuous", timezones.Template{
+		Zones:   []timezones.Zone{{Name: "CET", Offset: time.Hour}, {Name: "EET", Offset: 2 * time.Hour}},
+		Changes: []timezones.Change{{Start: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC), ZoneIndex: 1}},
+		Extend:  "CET-1",
+	})
+	if err := os.WriteFile(filepath.Join(dir, "zone.tab"), []byte("# not TZif\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	ok, err := run([]string{good}, &stdout, &stderr)
+	if err != nil || !ok || stdout.Len() != 0 {
+		t.Fatalf("expected %s to pass, got %v %v %q", good, ok, err, stdout.String())
+	}
+
+	ok, err = run([]string{"-profile", "strict", short}, &stdout, &stderr)
+	if err != nil || ok || !strings.Contains(stdout.String(), "invalid designation") {
+		t.Fatalf("expected %s to fail, got %v %v %q", short, ok, err, stdout.String())
+	}
+
+	stdout.Reset()
+	ok, err = run([]string{"-profile", "go", dir}, &stdout, &stderr)
+	if err != nil || !ok || !strings.Contains(stdout.String(), discontinuous+": warning: ") {
+		t.Fatalf("expected warnings only, got %v %v %q", ok, err, stdout.String())
+	}
+	ok, err = run([]string{"-profile", "go", "-werror", dir}, &stdout, &stderr)
+	if err != nil || ok {
+		t.Fatalf("expected failure with -werror, got %v %v", ok, err)
+	}
+
+	data, err := timezones.TZData(timezones.Template{
+		Zones:   []timezones.Zone{{Name: "CET", Offset: time.Hour}, {Name: "CEST", Offset: 2 * time.Hour, IsDST: true}},
+		Changes: []timezones.Change{{Start: time.Date(2021, time.March, 28, 1, 0, 0, 0, time.UTC), ZoneIndex: 1}},
+		Extend:  "CET-1CEST,M3.5.0,M10.5.0/3",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// zic writes 0 indicators, which are at the end of the data block, before the footer.
+	footer := bytes.LastIndexByte(data[:len(data)-1], '\n')
+	data[footer-1], data[footer-2] = 0, 0
+	wall := filepath.Join(t.TempDir(), "wall")
+	if err := os.WriteFile(wall, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	ok, err = run([]string{"-werror", wall}, &stdout, &stderr)
+	if err != nil || !ok || stdout.String() != wall+": note: 2 standard/wall or UT/local indicators are not 1, "+
+		"which only matters if the file is used as posixrules\n" {
+		t.Fatalf("expected a note only, got %v %v %q", ok, err, stdout.String())
+	}
+
+	if _, err := run([]string{"-profile", "unknown", dir}, &stdout, &stderr); err == nil {
+		t.Fatal("expected error for unknown profile")
+	}
+}
@@ -0,0 +1,105 @@
+package timezones
+
+import (
+	"fmt"
+	"time"
+)
+
+// CompactChange is a compact representation of Change.
+//
+// Change stores a time.Time, which takes 24 bytes and carries location and monotonic clock data that
+// are irrelevant for zone transitions. CompactChange takes 16 bytes, which matters when holding
+// hundreds of thousands of transitions in memory.
+// Index stores changes in this form, see Index.Changes.
+type CompactChange struct {
+	// Start is the time of the change in seconds since the Unix epoch.
+	Start int64
+
+	// ZoneIndex which takes effect since Start.
+	ZoneIndex uint8
+}
+
+// Change converts the compact change to a Change.
+func (c CompactChange) Change() Change {
+	return Change{
+		Start:     time.Unix(c.Start, 0),
+		ZoneIndex: int(c.ZoneIndex),
+	}
+}
+
+// CompactChanges converts changes to the compact form.
+// Sub-second parts of Start are dropped, the same way as TZData does.
+func CompactChanges(changes []Change) ([]CompactChange, error) {
+	compact := make([]CompactChange, len(changes))
+	for i := range changes {
+		if changes[i].ZoneIndex < 0 || changes[i].ZoneIndex > MaxZones-1 {
+			return nil, &FieldError{
+				Field:  "Changes",
+				Index:  i,
+				Err:    ErrZoneIndex,
+				Detail: fmt.Sprintf("zone index %d does not fit into compact change", changes[i].ZoneIndex),
+			}
+		}
+		if !fitsUnix(changes[i].Start) {
+			return nil, &FieldError{Field: "Changes", Index: i, Err: ErrStartOutOfRange}
+		}
+		compact[i].Start = changes[i].Start.Unix()
+		compact[i].ZoneIndex = uint8(changes[i].ZoneIndex)
+	}
+	return compact, nil
+}
+
+// ExpandChanges converts compact changes back to Changes.
+func ExpandChanges(compact []CompactChange) []Change {
+	changes := make([]Change, len(compact))
+	for i := range compact {
+		changes[i] = compact[i].Change()
+	}
+	return changes
+}
+
+// ChangeIterator iterates over compact changes, expanding them one at a time.
+// This avoids materializing the whole []Change when only a pass over the changes is needed.
+//
+//	it := NewChangeIterator(compact)
+//	for it.Next() {
+//		c := it.Change()
+//		...
+//	}
+type ChangeIterator struct {
+	changes []CompactChange
+	pos     int
+}
+
+// NewChangeIterator returns an iterator over the compact changes.
+func NewChangeIterator(changes []CompactChange) *ChangeIterator {
+	return &ChangeIterator{
+		changes: changes,
+		pos:     -1,
+	}
+}
+
+// Next advances the iterator to the next change.
+// It returns false when there are no more changes.
+func (it *ChangeIterator) Next() bool {
+	if it.pos >= len(it.changes) {
+		return false
+	}
+	it.pos++
+	return it.pos < len(it.changes)
+}
+
+// Index returns the index of the current change.
+func (it *ChangeIterator) Index() int {
+	return it.pos
+}
+
+// Compact returns the current change in the compact form.
+func (it *ChangeIterator) Compact() CompactChange {
+	return it.changes[it.pos]
+}
+
+// Change returns the current change.
+func (it *ChangeIterator) Change() Change {
+	return it.changes[it.pos].Change()
+}
@@ -0,0 +1,80 @@
+package timezones
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestCompactChanges(t *testing.T) {
+	changes := []Change{
+		{
+			Start:     time.Date(2022, time.January, 9, 10, 0, 0, 0, time.UTC),
+			ZoneIndex: 1,
+		},
+		{
+			Start:     time.Date(2022, time.January, 9, 11, 0, 0, 0, time.UTC),
+			ZoneIndex: 0,
+		},
+	}
+	compact, err := CompactChanges(changes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []CompactChange{
+		{Start: 1641722400, ZoneIndex: 1},
+		{Start: 1641726000, ZoneIndex: 0},
+	}
+	if !reflect.DeepEqual(compact, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, compact)
+	}
+	expanded := ExpandChanges(compact)
+	for i := range expanded {
+		expanded[i].Start = expanded[i].Start.In(time.UTC)
+	}
+	if !reflect.DeepEqual(expanded, changes) {
+		t.Fatalf("expected %+v, got %+v", changes, expanded)
+	}
+}
+
+func TestCompactChanges_ZoneIndexOutOfRange(t *testing.T) {
+	for _, idx := range []int{-1, MaxZones} {
+		_, err := CompactChanges([]Change{{ZoneIndex: idx}})
+		if err == nil {
+			t.Fatalf("expected error for zone index %d", idx)
+		}
+	}
+}
+
+func TestCompactChange_Size(t *testing.T) {
+	if s := unsafe.Sizeof(CompactChange{}); s != 16 {
+		t.Fatalf("expected CompactChange to take 16 bytes, got %d", s)
+	}
+}
+
+func TestChangeIterator(t *testing.T) {
+	compact := []CompactChange{
+		{Start: 1641722400, ZoneIndex: 1},
+		{Start: 1641726000, ZoneIndex: 0},
+	}
+	it := NewChangeIterator(compact)
+	var got []Change
+	for it.Next() {
+		if it.Compact() != compact[it.Index()] {
+			t.Fatalf("unexpected compact change at index %d", it.Index())
+		}
+		got = append(got, it.Change())
+	}
+	if it.Next() {
+		t.Fatal("expected iterator to stay exhausted")
+	}
+	if !reflect.DeepEqual(got, ExpandChanges(compact)) {
+		t.Fatalf("unexpected changes %+v", got)
+	}
+
+	it = NewChangeIterator(nil)
+	if it.Next() {
+		t.Fatal("expected empty iterator")
+	}
+}
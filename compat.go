@@ -0,0 +1,212 @@
+package timezones
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CompatibilityReport describes where Go's time package interprets TZif data differently than
+// a strict RFC 8536 reader, see AnalyzeCompatibility.
+type CompatibilityReport struct {
+	// GoFirstZone is the index of the local time type Go uses for times before the first transition.
+	// RFC 8536 always uses local time type 0, Go chooses a different one in some cases,
+	// see time.Location.lookupFirstZone.
+	GoFirstZone int
+
+	// NonUTIndicators is the number of standard/wall and UT/local indicators that are not 1.
+	// Go ignores the indicators. RFC 8536 readers only use them when the data is used as
+	// the "posixrules" file to complete TZ strings without DST rules, so they don't change
+	// the interpretation of the data itself.
+	NonUTIndicators int
+
+	// LeapSeconds is the number of leap second records, which Go ignores.
+	// An RFC 8536 reader applies the corrections, shifting UTC times by LeapCorrection seconds
+	// after the last leap second.
+	LeapSeconds int
+
+	// LeapCorrection is the correction of the last leap second record.
+	LeapCorrection int
+
+	// InvalidExtend reports whether the TZ string in the footer is invalid.
+	// Go silently ignores an invalid TZ string, while a strict reader rejects the data.
+	InvalidExtend bool
+
+	// TrailingData is the number of bytes after a footer with a TZ string, see Template.Trailing.
+	// Go reads the footer up to the end of the data, so it ignores the TZ string followed by them,
+	// while a strict reader uses the TZ string.
+	TrailingData int
+}
+
+// Divergent reports whether Go interprets the data differently than a strict RFC 8536 reader.
+func (r *CompatibilityReport) Divergent() bool {
+	return r.GoFirstZone != 0 || r.LeapSeconds > 0 || r.InvalidExtend || r.TrailingData > 0
+}
+
+// String describes the differences in human-readable form.
+func (r *CompatibilityReport) String() string {
+	var issues []string
+	if r.GoFirstZone != 0 {
+		issues = append(issues, fmt.Sprintf("Go uses local time type %d instead of 0 before the first transition",
+			r.GoFirstZone))
+	}
+	if r.LeapSeconds > 0 {
+		issues = append(issues, fmt.Sprintf("Go ignores %d leap second records (last correction %d s)",
+			r.LeapSeconds, r.LeapCorrection))
+	}
+	if r.InvalidExtend {
+		issues = append(issues, "Go ignores the invalid TZ string in the footer")
+	}
+	if r.TrailingData > 0 {
+		issues = append(issues, fmt.Sprintf("Go ignores the TZ string in the footer followed by %d bytes of trailing data",
+			r.TrailingData))
+	}
+	if r.NonUTIndicators > 0 {
+		issues = append(issues, fmt.Sprintf("Go ignores %d standard/wall or UT/local indicators (only relevant for posixrules)",
+			r.NonUTIndicators))
+	}
+	if len(issues) == 0 {
+		return "compatible"
+	}
+	return strings.Join(issues, "; ")
+}
+
+// AnalyzeCompatibility reports whether Go's time package would interpret the TZif data differently
+// than a strict RFC 8536 reader.
+//
+// Data created by TZData is always interpreted the same way by both.
+func AnalyzeCompatibility(tzdata []byte) (*CompatibilityReport, error) {
+	h, rest, err := parseHeaders(tzdata)
+	if err != nil {
+		return nil, err
+	}
+	var report CompatibilityReport
+
+	rest = rest[int(h.timecnt)*h.tsize:] // transition times are not needed
+	types, rest := rest[:h.timecnt], rest[h.timecnt:]
+	ltt, rest := rest[:h.typecnt*6], rest[h.typecnt*6:]
+	rest = rest[h.charcnt:]
+	leapLen := int(h.leapcnt) * (h.tsize + 4)
+	leap, rest := rest[:leapLen], rest[leapLen:]
+	indicators, rest := rest[:h.isstdcnt+h.isutcnt], rest[h.isstdcnt+h.isutcnt:]
+
+	zones := make([]Zone, h.typecnt)
+	for i := range zones {
+		zones[i].IsDST = ltt[i*6+4] != 0
+	}
+	changes := make([]Change, h.timecnt)
+	zeroIsUsed := false
+	for i := range types {
+		if int(types[i]) >= len(zones) {
+			return nil, ErrInvalid
+		}
+		changes[i].ZoneIndex = int(types[i])
+		if types[i] == 0 {
+			zeroIsUsed = true
+		}
+	}
+	if len(zones) > 0 {
+		report.GoFirstZone = firstZone(zones, changes, zeroIsUsed)
+	}
+
+	for i := range indicators {
+		if indicators[i] != 1 {
+			report.NonUTIndicators++
+		}
+	}
+
+	report.LeapSeconds = int(h.leapcnt)
+	if h.leapcnt > 0 {
+		last := leap[leapLen-4:]
+		report.LeapCorrection = int(int32(binary.BigEndian.Uint32(last)))
+	}
+
+	if h.version > 1 {
+		if extend, _, trailing := splitFooter(rest); extend != "" {
+			if _, err := parseTZRule(extend); err != nil {
+				report.InvalidExtend = true
+			}
+			report.TrailingData = len(trailing)
+		}
+	}
+	return &report, nil
+}
+
+// ChooseFirstZone returns the index of the zone Go's time package uses for times before the first change
+// in TZif data with the given local time types and transitions, along with a human-readable reason.
+// Unlike Template, zones and changes are the raw local time types and transitions of the data, so zone 0
+// may be used by changes, see time.Location.lookupFirstZone.
+//
+// RFC 8536 readers always use zone 0; templates avoid the difference by storing the first zone separately.
+// ChooseFirstZone returns 0 if a change refers to a zone that does not exist.
+func ChooseFirstZone(zones []Zone, changes []Change) (index int, reason string) {
+	zeroIsUsed := false
+	for _, c := range changes {
+		if c.ZoneIndex < 0 || c.ZoneIndex >= len(zones) {
+			return 0, fmt.Sprintf("zone index %d out of range", c.ZoneIndex)
+		}
+		if c.ZoneIndex == 0 {
+			zeroIsUsed = true
+		}
+	}
+	return chooseFirstZone(zones, changes, zeroIsUsed)
+}
+
+// VerifyRoundTrip checks that decoding tzdata with the GoCompatible decoder and encoding the result with TZData
+// produces data that Go's time package interprets the same way as tzdata.
+// The zone in effect is compared around every transition and weekly from the last transition
+// until the end of 2100 to cover the TZ string in the footer.
+//
+// It returns an error wrapping ErrRoundTrip describing the first difference found,
+// or the error from decoding or encoding.
+func VerifyRoundTrip(tzdata []byte) error {
+	d := GoCompatible.Decoder()
+	template, err := d.Decode(tzdata)
+	if err != nil {
+		return err
+	}
+	rebuilt, err := TZData(*template)
+	if err != nil {
+		return err
+	}
+	original, err := time.LoadLocationFromTZData("original", tzdata)
+	if err != nil {
+		return err
+	}
+	roundTripped, err := time.LoadLocationFromTZData("rebuilt", rebuilt)
+	if err != nil {
+		return err
+	}
+
+	check := func(sec int64) error {
+		t := time.Unix(sec, 0)
+		name1, offset1 := t.In(original).Zone()
+		name2, offset2 := t.In(roundTripped).Zone()
+		isDST1, isDST2 := t.In(original).IsDST(), t.In(roundTripped).IsDST()
+		if name1 != name2 || offset1 != offset2 || isDST1 != isDST2 {
+			return fmt.Errorf("%w: at %s original has %s %+d (dst %v), rebuilt has %s %+d (dst %v)",
+				ErrRoundTrip, formatTime(t), name1, offset1, isDST1, name2, offset2, isDST2)
+		}
+		return nil
+	}
+
+	last := int64(0)
+	for i := range template.Changes {
+		sec := template.Changes[i].Start.Unix()
+		if err := check(sec - 1); err != nil {
+			return err
+		}
+		if err := check(sec); err != nil {
+			return err
+		}
+		last = sec
+	}
+	end := time.Date(2101, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+	for sec := last; sec < end; sec += 7 * secondsPerDay {
+		if err := check(sec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package timezones
+
+import (
+	"archive/zip"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeCompatibility_TZData(t *testing.T) {
+	tzdata, err := TZData(benchTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := AnalyzeCompatibility(tzdata)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Divergent() || *report != (CompatibilityReport{}) {
+		t.Fatalf("expected compatible data, got %+v", report)
+	}
+	if report.String() != "compatible" {
+		t.Fatalf("unexpected description %q", report.String())
+	}
+}
+
+func TestAnalyzeCompatibility_FirstZone(t *testing.T) {
+	// Type 0 is DST and it is used by the first transition, so Go uses type 1 before the first transition.
+	data := rawTZif{
+		version: '2',
+		times:   []int64{100, 200},
+		types:   []byte{0, 1},
+		ltt: []rawLocalTimeType{
+			{utoff: 7200, isdst: 1, idx: 0},
+			{utoff: 3600, isdst: 0, idx: 5},
+		},
+		chars:  "CEST\x00CET\x00",
+		footer: "\n\n",
+	}
+	report, err := AnalyzeCompatibility(data.bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.GoFirstZone != 1 || !report.Divergent() {
+		t.Fatalf("expected Go to use zone 1 first, got %+v", report)
+	}
+}
+
+func TestAnalyzeCompatibility_LeapSecondsAndIndicators(t *testing.T) {
+	data := rawTZif{
+		version: '2',
+		times:   []int64{100},
+		types:   []byte{1},
+		ltt: []rawLocalTimeType{
+			{utoff: 0, isdst: 0, idx: 0},
+			{utoff: 3600, isdst: 0, idx: 4},
+		},
+		chars:  "UTC\x00CET\x00",
+		leaps:  []rawLeap{{occur: 78796800, corr: 1}, {occur: 94694401, corr: 2}},
+		isstd:  []byte{0},
+		isut:   []byte{0},
+		footer: "\nCET-1CEST,M3.5.0\n",
+	}
+	report, err := AnalyzeCompatibility(data.bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := CompatibilityReport{
+		GoFirstZone:     0,
+		NonUTIndicators: 2,
+		LeapSeconds:     2,
+		LeapCorrection:  2,
+		InvalidExtend:   true,
+	}
+	if *report != expected {
+		t.Fatalf("expected %+v, got %+v", expected, report)
+	}
+	if !report.Divergent() {
+		t.Fatal("expected divergent report")
+	}
+}
+
+func TestAnalyzeCompatibility_Trailing(t *testing.T) {
+	data := rawTZif{
+		version: '2',
+		ltt:     []rawLocalTimeType{{utoff: 3600, isdst: 0, idx: 0}},
+		chars:   "CET\x00",
+		footer:  "\nCET-1\nextension\n",
+	}
+	report, err := AnalyzeCompatibility(data.bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := (CompatibilityReport{TrailingData: 10}); *report != expected || !report.Divergent() {
+		t.Fatalf("expected %+v, got %+v", expected, report)
+	}
+	// Go ignores the whole footer.
+	loc, err := time.LoadLocationFromTZData("trailing", data.bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, _ := time.Date(2030, time.January, 1, 0, 0, 0, 0, loc).Zone(); name != "CET" {
+		t.Fatalf("unexpected zone %s", name)
+	}
+}
+
+func TestAnalyzeCompatibility_Invalid(t *testing.T) {
+	data := rawTZif{
+		version: '2',
+		times:   []int64{100},
+		types:   []byte{5},
+		ltt:     []rawLocalTimeType{{utoff: 0, isdst: 0, idx: 0}},
+		chars:   "UTC\x00",
+		footer:  "\n\n",
+	}
+	if _, err := AnalyzeCompatibility(data.bytes()); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := AnalyzeCompatibility([]byte("TZif")); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestVerifyRoundTrip(t *testing.T) {
+	tzdata, err := TZData(benchTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyRoundTrip(tzdata); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Go uses type 1 before the first transition, LoadTZData swaps the zones to preserve that.
+	data := rawTZif{
+		version: '2',
+		times:   []int64{100, 200},
+		types:   []byte{0, 1},
+		ltt: []rawLocalTimeType{
+			{utoff: 7200, isdst: 1, idx: 0},
+			{utoff: 3600, isdst: 0, idx: 5},
+		},
+		chars:  "CEST\x00CET\x00",
+		footer: "\nCET-1CEST,M3.5.0,M10.5.0/3\n",
+	}
+	if err := VerifyRoundTrip(data.bytes()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// zic writes 0 indicators, which Go ignores.
+	data.isstd = []byte{0, 0}
+	data.isut = []byte{0, 0}
+	if err := VerifyRoundTrip(data.bytes()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestVerifyRoundTrip_GoZoneinfo(t *testing.T) {
+	zr, err := zip.OpenReader(filepath.Join(runtime.GOROOT(), "lib", "time", "zoneinfo.zip"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			t.Skip("zoneinfo.zip not available")
+		}
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	for _, f := range zr.File {
+		data, err := fs.ReadFile(zr, f.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyRoundTrip(data); err != nil {
+			t.Errorf("%s: %v", f.Name, err)
+		}
+	}
+}
+
+func TestDecoder_Raw(t *testing.T) {
+	data := rawTZif{
+		version: '2',
+		times:   []int64{100, 200},
+		types:   []byte{0, 1},
+		ltt: []rawLocalTimeType{
+			{utoff: 7200, isdst: 1, idx: 0},
+			{utoff: 3600, isdst: 0, idx: 5},
+		},
+		chars:  "CEST\x00CET\x00",
+		footer: "\n\n",
+	}
+	d := Decoder{Raw: true}
+	template, err := d.Decode(data.bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Template{
+		Zones: []Zone{
+			{Name: "CEST", Offset: 2 * time.Hour, IsDST: true},
+			{Name: "CET", Offset: time.Hour},
+		},
+		Changes: []Change{
+			{Start: time.Unix(100, 0), ZoneIndex: 0},
+			{Start: time.Unix(200, 0), ZoneIndex: 1},
+		},
+		Version: 2,
+	}
+	if !reflect.DeepEqual(*template, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, *template)
+	}
+
+	// The synthetic zone added by TZData is kept.
+	tzdata, err := TZData(Template{Zones: []Zone{{Name: "MyFixed", Offset: time.Hour}}, Extend: "<MyFixed>-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	template, err = d.Decode(tzdata)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(template.Zones) != 2 {
+		t.Fatalf("expected 2 zones, got %+v", template.Zones)
+	}
+}
+
+func TestChooseFirstZone(t *testing.T) {
+	std := Zone{Name: "STD"}
+	dst := Zone{Name: "DST", Offset: time.Hour, IsDST: true}
+	for _, tc := range []struct {
+		name     string
+		zones    []Zone
+		changes  []Change
+		expected int
+	}{
+		{"unused zero", []Zone{dst, std}, []Change{{ZoneIndex: 1}}, 0},
+		{"first change to DST", []Zone{std, std, dst}, []Change{{ZoneIndex: 2}, {ZoneIndex: 0}}, 1},
+		{"first standard", []Zone{dst, std}, []Change{{ZoneIndex: 0}}, 1},
+		{"no standard", []Zone{dst, dst}, []Change{{ZoneIndex: 0}}, 0},
+		{"invalid", []Zone{std}, []Change{{ZoneIndex: 3}}, 0},
+	} {
+		index, reason := ChooseFirstZone(tc.zones, tc.changes)
+		if index != tc.expected {
+			t.Errorf("%s: expected %d, got %d (%s)", tc.name, tc.expected, index, reason)
+		}
+		if reason == "" {
+			t.Errorf("%s: expected a reason", tc.name)
+		}
+	}
+}
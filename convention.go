@@ -0,0 +1,79 @@
+package timezones
+
+import "time"
+
+// TimeConvention is the clock in which a source format expresses the instants of changes.
+// Template.Changes are always in universal time, formats like zic source or iCalendar VTIMEZONE
+// usually use the local wall clock time in effect before the change.
+type TimeConvention int
+
+const (
+	// UniversalTime is the UTC clock.
+	UniversalTime TimeConvention = iota
+	// StandardTime is the local standard time clock, i.e. the wall clock without daylight saving time savings.
+	StandardTime
+	// WallTime is the local wall clock, including daylight saving time savings.
+	WallTime
+)
+
+// String returns the suffix zic uses for times in the convention: "u", "s" or "w".
+func (c TimeConvention) String() string {
+	switch c {
+	case UniversalTime:
+		return "u"
+	case StandardTime:
+		return "s"
+	case WallTime:
+		return "w"
+	default:
+		return "?"
+	}
+}
+
+// conventionOffset returns the difference of the clock of the convention from UTC while zone i is in effect.
+// savings are the savings of the DST zones, see Template.Savings.
+func (t *Template) conventionOffset(c TimeConvention, i int, savings []ZoneSavings) time.Duration {
+	if c == UniversalTime || i < 0 || i >= len(t.Zones) {
+		return 0
+	}
+	offset := t.Zones[i].Offset.Round(time.Second)
+	if c == StandardTime {
+		for _, s := range savings {
+			if s.ZoneIndex == i {
+				offset -= s.Savings
+			}
+		}
+	}
+	return offset
+}
+
+// ChangeTimes returns the start of each change in Changes as read on the clock of the convention
+// just before the change, i.e. in the zone in effect before it, like zic Zone and Rule lines
+// and the DTSTART of iCalendar VTIMEZONE observances express them.
+// The readings are returned as times in UTC, so formatting them prints the local clock reading.
+//
+// The standard time of a DST zone is derived from the savings reported by Savings.
+func (t *Template) ChangeTimes(convention TimeConvention) []time.Time {
+	var savings []ZoneSavings
+	if convention == StandardTime {
+		savings = t.Savings()
+	}
+	times := make([]time.Time, len(t.Changes))
+	before := t.FirstZoneIndex
+	for i, c := range t.Changes {
+		times[i] = c.Start.UTC().Add(t.conventionOffset(convention, before, savings))
+		before = c.ZoneIndex
+	}
+	return times
+}
+
+// UniversalTimeOf converts a clock reading in the convention to universal time, the inverse of ChangeTimes.
+// The reading is given as a time in UTC, see ChangeTimes, and zoneIndex is the index of the zone in Zones
+// in effect when the clock shows it, usually the zone before the change.
+func (t *Template) UniversalTimeOf(reading time.Time, convention TimeConvention, zoneIndex int) time.Time {
+	var savings []ZoneSavings
+	if convention == StandardTime {
+		savings = t.Savings()
+	}
+	return reading.UTC().Add(-t.conventionOffset(convention, zoneIndex, savings))
+}
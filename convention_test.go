@@ -0,0 +1,49 @@
+package timezones
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTemplate_ChangeTimes(t *testing.T) {
+	cet := Zone{Name: "CET", Offset: time.Hour}
+	cest := Zone{Name: "CEST", Offset: 2 * time.Hour, IsDST: true}
+	template := Template{
+		Zones: []Zone{cet, cest},
+		Changes: []Change{
+			{Start: time.Date(2020, time.March, 29, 1, 0, 0, 0, time.UTC), ZoneIndex: 1},
+			{Start: time.Date(2020, time.October, 25, 1, 0, 0, 0, time.UTC), ZoneIndex: 0},
+		},
+	}
+	for _, tc := range []struct {
+		convention TimeConvention
+		expected   []string
+	}{
+		{UniversalTime, []string{"2020-03-29T01:00:00Z", "2020-10-25T01:00:00Z"}},
+		{StandardTime, []string{"2020-03-29T02:00:00Z", "2020-10-25T02:00:00Z"}},
+		{WallTime, []string{"2020-03-29T02:00:00Z", "2020-10-25T03:00:00Z"}},
+	} {
+		times := template.ChangeTimes(tc.convention)
+		if len(times) != len(tc.expected) {
+			t.Fatalf("%v: expected %d times, got %d", tc.convention, len(tc.expected), len(times))
+		}
+		for i := range times {
+			if s := times[i].Format(time.RFC3339); s != tc.expected[i] {
+				t.Errorf("%v: change %d: expected %s, got %s", tc.convention, i, tc.expected[i], s)
+			}
+			before := template.FirstZoneIndex
+			if i > 0 {
+				before = template.Changes[i-1].ZoneIndex
+			}
+			if ut := template.UniversalTimeOf(times[i], tc.convention, before); !ut.Equal(template.Changes[i].Start) {
+				t.Errorf("%v: change %d: expected %v, got %v", tc.convention, i, template.Changes[i].Start, ut)
+			}
+		}
+	}
+}
+
+func TestTimeConvention_String(t *testing.T) {
+	if s := UniversalTime.String() + StandardTime.String() + WallTime.String(); s != "usw" {
+		t.Fatalf("expected usw, got %s", s)
+	}
+}
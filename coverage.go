@@ -0,0 +1,44 @@
+package timezones
+
+import (
+	"sort"
+	"time"
+)
+
+// Coverage returns until when the template explicitly describes the zone.
+//
+// If Extend holds a valid TZ string, or if there are no Changes and so a single zone applies at all times,
+// the template describes the zone indefinitely and unbounded is true.
+// Otherwise the template ends with the last change, e.g. version 1 data that lists transitions only
+// through 2037, and end is the start of the last change. Times after end use the zone of the last change,
+// which is likely wrong if the zone observes daylight saving time.
+func (t *Template) Coverage() (end time.Time, unbounded bool) {
+	if t.Extend != "" {
+		if _, err := cachedTZRule(t.Extend); err == nil {
+			return time.Time{}, true
+		}
+	}
+	if len(t.Changes) == 0 {
+		return time.Time{}, true
+	}
+	return t.Changes[len(t.Changes)-1].Start, false
+}
+
+// Covers reports whether the template explicitly describes the zone through until, see Coverage.
+func (t *Template) Covers(until time.Time) bool {
+	end, unbounded := t.Coverage()
+	return unbounded || !until.After(end)
+}
+
+// Stale returns the sorted names of the templates that don't cover until, see Template.Covers.
+// It is meant for monitoring of outdated zone data, e.g. templates loaded by LoadAll.
+func Stale(templates map[string]*Template, until time.Time) []string {
+	var stale []string
+	for name, t := range templates {
+		if !t.Covers(until) {
+			stale = append(stale, name)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
@@ -0,0 +1,78 @@
+package timezones
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTemplate_Coverage(t *testing.T) {
+	bench := benchTemplate()
+	last := bench.Changes[len(bench.Changes)-1].Start
+	tests := []struct {
+		name      string
+		template  Template
+		end       time.Time
+		unbounded bool
+	}{
+		{
+			name:      "fixed",
+			template:  Template{Zones: []Zone{{Name: "MyFixed", Offset: time.Hour}}},
+			unbounded: true,
+		},
+		{
+			name:     "changes",
+			template: bench,
+			end:      last,
+		},
+		{
+			name: "extend",
+			template: Template{
+				Zones:   bench.Zones,
+				Changes: bench.Changes,
+				Extend:  "CET-1CEST,M3.5.0,M10.5.0/3",
+			},
+			unbounded: true,
+		},
+		{
+			name: "invalid extend",
+			template: Template{
+				Zones:   bench.Zones,
+				Changes: bench.Changes,
+				Extend:  "invalid",
+			},
+			end: last,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			end, unbounded := test.template.Coverage()
+			if !end.Equal(test.end) || unbounded != test.unbounded {
+				t.Fatalf("expected %v %v, got %v %v", test.end, test.unbounded, end, unbounded)
+			}
+			if !test.template.Covers(test.end) {
+				t.Fatal("expected template to cover its end")
+			}
+			if test.template.Covers(test.end.Add(time.Second)) != test.unbounded {
+				t.Fatal("unexpected coverage after the end")
+			}
+		})
+	}
+}
+
+func TestStale(t *testing.T) {
+	bench := benchTemplate()
+	last := bench.Changes[len(bench.Changes)-1].Start
+	templates := map[string]*Template{
+		"Etc/MyFixed":  {Zones: []Zone{{Name: "MyFixed", Offset: time.Hour}}},
+		"Custom/Bench": &bench,
+		"Custom/Other": &bench,
+	}
+	if stale := Stale(templates, last); len(stale) != 0 {
+		t.Fatalf("expected no stale templates, got %v", stale)
+	}
+	expected := []string{"Custom/Bench", "Custom/Other"}
+	if stale := Stale(templates, last.AddDate(1, 0, 0)); !reflect.DeepEqual(stale, expected) {
+		t.Fatalf("expected %v, got %v", expected, stale)
+	}
+}
@@ -0,0 +1,185 @@
+package timezones
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DesignationPacker lays out the time zone designations of TZif data, see Encoder.Designations.
+//
+// TZif stores the designations as NUL-terminated strings in a single buffer and each local time type
+// refers to its designation by an index into the buffer. The index may point into the middle of a string
+// to share its suffix, e.g. EST within CEST, which saves space but confuses some readers.
+type DesignationPacker interface {
+	// PackDesignations returns the designations buffer for names and the index of each of the names within it.
+	// The buffer must contain each name at its index followed by a NUL byte.
+	PackDesignations(names []string) (buf []byte, indices []int)
+}
+
+var (
+	// SharedSuffixDesignations shares a designation with the suffix of a designation stored before it,
+	// in the order of the zones. This is the default.
+	SharedSuffixDesignations DesignationPacker = suffixPacker{}
+
+	// DistinctDesignations stores each distinct designation separately, so that all indices point
+	// to the start of a string. Use it for readers that mishandle shared suffixes.
+	DistinctDesignations DesignationPacker = distinctPacker{}
+
+	// PackedDesignations shares suffixes regardless of the order of the zones by storing longer designations
+	// first. It produces the smallest buffer of the three, but the order of designations in it does not
+	// follow the order of zones.
+	PackedDesignations DesignationPacker = suffixPacker{longestFirst: true}
+)
+
+type suffixPacker struct {
+	longestFirst bool
+}
+
+func (p suffixPacker) PackDesignations(names []string) ([]byte, []int) {
+	zd := zoneDesignations{
+		names:   make([]string, 0, len(names)),
+		starts:  make([]int, 0, len(names)),
+		offsets: make([]int, 0, len(names)),
+	}
+	if !p.longestFirst {
+		for _, name := range names {
+			zd.add(name)
+		}
+		return zd.bytes(), zd.offsets
+	}
+	order := make([]int, len(names))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(names[order[i]]) > len(names[order[j]])
+	})
+	for _, i := range order {
+		zd.add(names[i])
+	}
+	indices := make([]int, len(names))
+	for k, i := range order {
+		indices[i] = zd.offsets[k]
+	}
+	return zd.bytes(), indices
+}
+
+type distinctPacker struct{}
+
+func (distinctPacker) PackDesignations(names []string) ([]byte, []int) {
+	var buf []byte
+	indices := make([]int, len(names))
+	starts := make(map[string]int, len(names))
+	for i, name := range names {
+		start, ok := starts[name]
+		if !ok {
+			start = len(buf)
+			starts[name] = start
+			buf = append(append(buf, name...), 0)
+		}
+		indices[i] = start
+	}
+	return buf, indices
+}
+
+// packDesignations packs names with the packer of the encoder and checks the result.
+func (e *Encoder) packDesignations(names []string) ([]byte, []int, error) {
+	packer := e.Designations
+	if packer == nil {
+		packer = SharedSuffixDesignations
+	}
+	buf, indices := packer.PackDesignations(names)
+	if len(indices) != len(names) {
+		return nil, nil, fmt.Errorf("%w: packer returned %d indices for %d designations",
+			ErrInvalidDesignation, len(indices), len(names))
+	}
+	for i, name := range names {
+		start := indices[i]
+		if start < 0 || start+len(name) >= len(buf) || string(buf[start:start+len(name)]) != name || buf[start+len(name)] != 0 {
+			return nil, nil, fmt.Errorf("%w: packer stored %q incorrectly at index %d", ErrInvalidDesignation, name, start)
+		}
+	}
+	return buf, indices, nil
+}
+
+// bytes returns the buffer with the stored designations.
+func (zd *zoneDesignations) bytes() []byte {
+	var sb strings.Builder
+	sb.Grow(zd.charcnt)
+	for _, name := range zd.names {
+		sb.WriteString(name)
+		sb.WriteByte(0)
+	}
+	return []byte(sb.String())
+}
+
+// abbreviateDesignations returns the template with the longest zone names replaced by numeric designations
+// until the designations fit into TZif, see Encoder.AbbreviateDesignations.
+// It returns template itself if no name needs to be replaced.
+// The rewrites are returned rather than reported to Warn, so that they are only reported for templates
+// that pass validation.
+func (e *Encoder) abbreviateDesignations(template *Template) (*Template, []error) {
+	if !e.AbbreviateDesignations || e.designationsFit(template.Zones) {
+		return template, nil
+	}
+	abbreviated := *template
+	zones := append([]Zone(nil), template.Zones...)
+	abbreviated.Zones = zones
+	var rewrites []error
+	// considered marks the zones that were rewritten or whose name is not longer than its numeric form.
+	considered := make([]bool, len(zones))
+	for !e.designationsFit(zones) {
+		longest := -1
+		for i := range zones {
+			if !considered[i] && (longest < 0 || len(zones[i].Name) > len(zones[longest].Name)) {
+				longest = i
+			}
+		}
+		if longest < 0 {
+			// Nothing left to rewrite, computeLayout reports the error.
+			break
+		}
+		name := zones[longest].Name
+		for i := range zones {
+			if considered[i] || zones[i].Name != name {
+				continue
+			}
+			considered[i] = true
+			numeric := OffsetOf(zones[i]).Numeric()
+			if len(numeric) >= len(name) {
+				continue
+			}
+			zones[i].Name = numeric
+			rewrites = append(rewrites, &FieldError{
+				Field:  "Zones",
+				Index:  i,
+				Err:    ErrDesignationRewritten,
+				Detail: fmt.Sprintf("%q rewritten to %q", name, numeric),
+			})
+		}
+	}
+	return &abbreviated, rewrites
+}
+
+// designationsFit reports whether the designations of zones fit into TZif as computeLayout lays them out.
+// Designations the packer can't store are reported as fitting, so that computeLayout reports the actual error.
+func (e *Encoder) designationsFit(zones []Zone) bool {
+	_, _, err := e.layoutDesignations(zones)
+	return !errors.Is(err, ErrDesignationsTooLong)
+}
+
+// layoutDesignations packs the designations of zones in the order they are written to TZif data
+// and checks that they fit.
+func (e *Encoder) layoutDesignations(zones []Zone) ([]byte, []int, error) {
+	designations, indices, err := e.packDesignations(designationNames(zones, e.typeShift()))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(designations) > MaxDesignationBytes {
+		return nil, nil, fmt.Errorf("%w: charcnt=%d, max is %d", ErrDesignationsTooLong,
+			len(designations), MaxDesignationBytes)
+	}
+	return designations, indices, nil
+}
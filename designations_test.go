@@ -0,0 +1,156 @@
+package timezones
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncoder_Designations(t *testing.T) {
+	template := Template{
+		Zones: []Zone{
+			{Name: "EST", Offset: -5 * time.Hour},
+			{Name: "CEST", Offset: 2 * time.Hour, IsDST: true},
+			{Name: "EST", Offset: -5 * time.Hour, IsDST: true},
+		},
+		Changes: []Change{
+			{Start: time.Date(2020, time.March, 29, 1, 0, 0, 0, time.UTC), ZoneIndex: 1},
+			{Start: time.Date(2020, time.October, 25, 1, 0, 0, 0, time.UTC), ZoneIndex: 2},
+		},
+	}
+	for _, tc := range []struct {
+		name     string
+		packer   DesignationPacker
+		charcnt  int
+		suffixes []int
+	}{
+		{name: "default", packer: nil, charcnt: 9},
+		{name: "shared", packer: SharedSuffixDesignations, charcnt: 9},
+		{name: "distinct", packer: DistinctDesignations, charcnt: 9},
+		// CEST is stored first, so all ESTs share its suffix.
+		{name: "packed", packer: PackedDesignations, charcnt: 5, suffixes: []int{0, 1, 3}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			e := Encoder{Designations: tc.packer, SelfCheck: true}
+			data, err := e.Encode(template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			size, err := e.Size(template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size != len(data) {
+				t.Fatalf("Size returned %d, encoded %d bytes", size, len(data))
+			}
+			info, err := QuickInfo(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.CharCount != tc.charcnt {
+				t.Fatalf("expected charcnt %d, got %d", tc.charcnt, info.CharCount)
+			}
+			suffixes, err := SuffixDesignations(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(suffixes, tc.suffixes) {
+				t.Fatalf("expected suffixes %v, got %v", tc.suffixes, suffixes)
+			}
+		})
+	}
+}
+
+type badPacker struct{}
+
+func (badPacker) PackDesignations(names []string) ([]byte, []int) {
+	return []byte("X\x00"), make([]int, len(names))
+}
+
+func TestEncoder_Designations_Invalid(t *testing.T) {
+	e := Encoder{Designations: badPacker{}}
+	_, err := e.Encode(Template{Zones: []Zone{{Name: "CET"}, {Name: "CEST"}}, Changes: []Change{{ZoneIndex: 1}}})
+	if !errors.Is(err, ErrInvalidDesignation) {
+		t.Fatalf("expected ErrInvalidDesignation, got %v", err)
+	}
+}
+
+func TestEncoder_AbbreviateDesignations(t *testing.T) {
+	long := strings.Repeat("Long Standard Time ", 10)
+	template := Template{
+		Zones: []Zone{
+			{Name: "CET", Offset: time.Hour},
+			{Name: long + "East", Offset: 5*time.Hour + 30*time.Minute},
+			{Name: long + "West", Offset: -3 * time.Hour},
+		},
+		Changes: []Change{
+			{Start: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC), ZoneIndex: 1},
+			{Start: time.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC), ZoneIndex: 2},
+			{Start: time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC), ZoneIndex: 0},
+		},
+	}
+	if _, err := TZData(template); !errors.Is(err, ErrDesignationsTooLong) {
+		t.Fatalf("expected ErrDesignationsTooLong, got %v", err)
+	}
+
+	var warnings []error
+	e := Encoder{AbbreviateDesignations: true, SelfCheck: true, Warn: func(err error) {
+		warnings = append(warnings, err)
+	}}
+	data, err := e.Encode(template)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decoded, err := LoadTZData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One rewrite is enough to fit.
+	var names []string
+	for _, z := range decoded.Zones {
+		names = append(names, z.Name)
+	}
+	expected := []string{"CET", "+0530", long + "West"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected names %q, got %q", expected, names)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %v", warnings)
+	}
+	var fe *FieldError
+	if !errors.As(warnings[0], &fe) || fe.Err != ErrDesignationRewritten || fe.Index != 1 {
+		t.Fatalf("unexpected warning %v", warnings[0])
+	}
+	warnings = nil
+	size, err := e.Size(template)
+	if err != nil || size != len(data) {
+		t.Fatalf("expected size %d, got %d, %v", len(data), size, err)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning from Size, got %v", warnings)
+	}
+
+	// Rewrites are not reported for templates that are rejected.
+	warnings = nil
+	invalid := template
+	invalid.Changes = append([]Change{{Start: time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC), ZoneIndex: 3}},
+		template.Changes...)
+	if _, err := e.Encode(invalid); !errors.Is(err, ErrZoneIndex) {
+		t.Fatalf("expected ErrZoneIndex, got %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("expected no warnings, got %v", warnings)
+	}
+
+	// Names that are not longer than the numeric designations are kept.
+	template.Zones = nil
+	for i := 0; i < 100; i++ {
+		template.Zones = append(template.Zones, Zone{Name: fmt.Sprintf("Z%02d", i), Offset: time.Hour})
+	}
+	if _, err := e.Encode(template); !errors.Is(err, ErrDesignationsTooLong) {
+		t.Fatalf("expected ErrDesignationsTooLong, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package timezones
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Difference is a time interval during which two templates use different zones, see Template.Diff.
+type Difference struct {
+	// Start and End delimit the interval [Start, End).
+	Start, End time.Time
+
+	// Zone is the zone of the template Diff was called on, Other is the zone of the other template.
+	Zone, Other Zone
+}
+
+// jsonZone is the JSON form of a zone in a difference report, with the offset in seconds.
+type jsonZone struct {
+	Name   string `json:"name"`
+	Offset int64  `json:"offset"`
+	IsDST  bool   `json:"dst"`
+}
+
+// MarshalJSON encodes the difference as a JSON object for machine-readable reports, e.g.
+//
+//	{"start":"2021-03-28T01:00:00Z","end":"2021-10-31T01:00:00Z",
+//	 "zone":{"name":"CEST","offset":7200,"dst":true},"other":{"name":"CET","offset":3600,"dst":false}}
+//
+// Offsets are in seconds east of UTC and times are in RFC 3339 format in UTC.
+func (d Difference) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Start time.Time `json:"start"`
+		End   time.Time `json:"end"`
+		Zone  jsonZone  `json:"zone"`
+		Other jsonZone  `json:"other"`
+	}{
+		Start: d.Start.UTC(),
+		End:   d.End.UTC(),
+		Zone:  jsonZone{Name: d.Zone.Name, Offset: offsetSeconds(d.Zone), IsDST: d.Zone.IsDST},
+		Other: jsonZone{Name: d.Other.Name, Offset: offsetSeconds(d.Other), IsDST: d.Other.IsDST},
+	})
+}
+
+// Diff returns the intervals within [from, to) where t and other use different zones,
+// i.e. where the locations built from them report a different designation, offset or DST flag.
+// Adjacent intervals with the same pair of zones are merged.
+// Names of the templates are not compared.
+//
+// Diff compares behavior, not representation: templates that list the same transitions
+// in a different way, for example using Extend instead of Changes, don't differ.
+func (t *Template) Diff(other *Template, from, to time.Time) []Difference {
+	a, b := newZoneLookup(t), newZoneLookup(other)
+	var diffs []Difference
+	end := to.Unix()
+	for sec := from.Unix(); sec < end; {
+		zoneA, _, endA := a.lookup(sec)
+		zoneB, _, endB := b.lookup(sec)
+		next := endA
+		if endB < next {
+			next = endB
+		}
+		if next > end {
+			next = end
+		}
+		if !sameZone(zoneA, zoneB) {
+			n := len(diffs)
+			if n > 0 && diffs[n-1].End.Unix() == sec && diffs[n-1].Zone == zoneA && diffs[n-1].Other == zoneB {
+				diffs[n-1].End = time.Unix(next, 0).UTC()
+			} else {
+				diffs = append(diffs, Difference{
+					Start: time.Unix(sec, 0).UTC(),
+					End:   time.Unix(next, 0).UTC(),
+					Zone:  zoneA,
+					Other: zoneB,
+				})
+			}
+		}
+		sec = next
+	}
+	return diffs
+}
@@ -0,0 +1,85 @@
+package timezones
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTemplate_Diff(t *testing.T) {
+	cet := Zone{Name: "CET", Offset: time.Hour}
+	cest := Zone{Name: "CEST", Offset: 2 * time.Hour, IsDST: true}
+	extend := "CET-1CEST,M3.5.0,M10.5.0/3"
+	withChanges := Template{
+		Zones: []Zone{cet, cest},
+		Changes: []Change{
+			{Start: time.Date(2020, time.March, 29, 1, 0, 0, 0, time.UTC), ZoneIndex: 1},
+			{Start: time.Date(2020, time.October, 25, 1, 0, 0, 0, time.UTC), ZoneIndex: 0},
+		},
+		Extend: extend,
+	}
+	extendOnly := Template{Zones: []Zone{cet}, Extend: extend}
+	from := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	if diffs := withChanges.Diff(&extendOnly, from, to); len(diffs) != 0 {
+		t.Fatalf("expected no differences, got %+v", diffs)
+	}
+
+	// Without DST in 2021, summer time differs.
+	noDST2021 := Template{
+		Zones: []Zone{cet, cest},
+		Changes: []Change{
+			{Start: time.Date(2020, time.March, 29, 1, 0, 0, 0, time.UTC), ZoneIndex: 1},
+			{Start: time.Date(2020, time.October, 25, 1, 0, 0, 0, time.UTC), ZoneIndex: 0},
+			{Start: time.Date(2022, time.March, 27, 1, 0, 0, 0, time.UTC), ZoneIndex: 1},
+		},
+		Extend: extend,
+	}
+	expected := []Difference{
+		{
+			Start: time.Date(2021, time.March, 28, 1, 0, 0, 0, time.UTC),
+			End:   time.Date(2021, time.October, 31, 1, 0, 0, 0, time.UTC),
+			Zone:  cest,
+			Other: cet,
+		},
+	}
+	if diffs := extendOnly.Diff(&noDST2021, from, to); !reflect.DeepEqual(diffs, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, diffs)
+	}
+
+	// Differences are clipped to the range.
+	fixed := Template{Zones: []Zone{cet}}
+	expected = []Difference{
+		{
+			Start: time.Date(2021, time.March, 28, 1, 0, 0, 0, time.UTC),
+			End:   time.Date(2021, time.May, 1, 0, 0, 0, 0, time.UTC),
+			Zone:  cest,
+			Other: cet,
+		},
+	}
+	diffs := extendOnly.Diff(&fixed, time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, time.May, 1, 0, 0, 0, 0, time.UTC))
+	if !reflect.DeepEqual(diffs, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, diffs)
+	}
+}
+
+func TestDifference_MarshalJSON(t *testing.T) {
+	d := Difference{
+		Start: time.Date(2021, time.March, 28, 1, 0, 0, 0, time.UTC),
+		End:   time.Date(2021, time.October, 31, 1, 0, 0, 0, time.UTC),
+		Zone:  Zone{Name: "CEST", Offset: 2 * time.Hour, IsDST: true},
+		Other: Zone{Name: "CET", Offset: time.Hour},
+	}
+	data, err := json.Marshal([]Difference{d})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"start":"2021-03-28T01:00:00Z","end":"2021-10-31T01:00:00Z",` +
+		`"zone":{"name":"CEST","offset":7200,"dst":true},"other":{"name":"CET","offset":3600,"dst":false}}]`
+	if string(data) != expected {
+		t.Fatalf("expected %s, got %s", expected, data)
+	}
+}
@@ -0,0 +1,68 @@
+package timezones
+
+import "time"
+
+// NextDSTStart returns the first instant after after at which daylight saving time starts, i.e. the zone
+// changes from standard time to DST, along with the change of the offset, usually an hour.
+// Extend is evaluated for times after the last change.
+// It returns false if daylight saving time never starts after after.
+func (t *Template) NextDSTStart(after time.Time) (start time.Time, delta time.Duration, ok bool) {
+	return t.nextDSTChange(after, true)
+}
+
+// NextDSTEnd returns the first instant after after at which daylight saving time ends, i.e. the zone
+// changes from DST to standard time, along with the change of the offset, usually minus an hour.
+// Extend is evaluated for times after the last change.
+// It returns false if daylight saving time never ends after after.
+func (t *Template) NextDSTEnd(after time.Time) (end time.Time, delta time.Duration, ok bool) {
+	return t.nextDSTChange(after, false)
+}
+
+// nextDSTChange returns the first change after after to a zone with IsDST equal to dst from a zone without.
+func (t *Template) nextDSTChange(after time.Time, dst bool) (time.Time, time.Duration, bool) {
+	zl := newZoneLookup(t)
+	// Extend repeats every year, so if there is no change within two years after the last change,
+	// there is none at all.
+	horizon := after.Unix()
+	if n := len(t.Changes); n > 0 && t.Changes[n-1].Start.Unix() > horizon {
+		horizon = t.Changes[n-1].Start.Unix()
+	}
+	horizon += 2 * 366 * secondsPerDay
+
+	prev, _, sec := zl.lookup(after.Unix())
+	for sec != omega && sec <= horizon {
+		zone, _, next := zl.lookup(sec)
+		if prev.IsDST != dst && zone.IsDST == dst {
+			return time.Unix(sec, 0).UTC(), zone.Offset.Round(time.Second) - prev.Offset.Round(time.Second), true
+		}
+		prev = zone
+		sec = next
+	}
+	return time.Time{}, 0, false
+}
+
+// ZoneAt returns the zone in effect at instant, the same zone a time.Location built from the template uses.
+func (t *Template) ZoneAt(instant time.Time) Zone {
+	zone, _, _ := newZoneLookup(t).lookup(instant.Unix())
+	return zone
+}
+
+// SavingsAt returns the daylight saving time savings in effect at instant: the offset of the zone
+// minus the offset of the closest standard time zone in effect before it, usually an hour.
+// It is zero if the zone is not DST or if no standard time zone is in effect before it.
+// It can be negative, e.g. if the standard time applies in summer and DST in winter, like in Europe/Dublin.
+func (t *Template) SavingsAt(instant time.Time) time.Duration {
+	zl := newZoneLookup(t)
+	zone, start, _ := zl.lookup(instant.Unix())
+	if !zone.IsDST {
+		return 0
+	}
+	for start != alpha {
+		std, stdStart, _ := zl.lookup(start - 1)
+		if !std.IsDST {
+			return zone.Offset.Round(time.Second) - std.Offset.Round(time.Second)
+		}
+		start = stdStart
+	}
+	return 0
+}
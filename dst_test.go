@@ -0,0 +1,107 @@
+package timezones
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTemplate_NextDSTStart(t *testing.T) {
+	template := Template{
+		Zones: []Zone{
+			{Name: "CET", Offset: time.Hour},
+			{Name: "CEST", Offset: 2 * time.Hour, IsDST: true},
+		},
+		Changes: []Change{
+			{Start: time.Date(2020, time.March, 29, 1, 0, 0, 0, time.UTC), ZoneIndex: 1},
+			{Start: time.Date(2020, time.October, 25, 1, 0, 0, 0, time.UTC), ZoneIndex: 0},
+		},
+		Extend: "CET-1CEST,M3.5.0,M10.5.0/3",
+	}
+	for _, tc := range []struct {
+		after, expected time.Time
+	}{
+		{time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, time.March, 29, 1, 0, 0, 0, time.UTC)},
+		// The change at after is not after after.
+		{time.Date(2020, time.March, 29, 1, 0, 0, 0, time.UTC), time.Date(2021, time.March, 28, 1, 0, 0, 0, time.UTC)},
+		{time.Date(2030, time.July, 1, 0, 0, 0, 0, time.UTC), time.Date(2031, time.March, 30, 1, 0, 0, 0, time.UTC)},
+	} {
+		start, delta, ok := template.NextDSTStart(tc.after)
+		if !ok || !start.Equal(tc.expected) || delta != time.Hour {
+			t.Fatalf("%v: expected %v, got %v %v %v", tc.after, tc.expected, start, delta, ok)
+		}
+	}
+
+	end, delta, ok := template.NextDSTEnd(time.Date(2030, time.July, 1, 0, 0, 0, 0, time.UTC))
+	if expected := time.Date(2030, time.October, 27, 1, 0, 0, 0, time.UTC); !ok || !end.Equal(expected) || delta != -time.Hour {
+		t.Fatalf("expected %v, got %v %v %v", expected, end, delta, ok)
+	}
+}
+
+func TestTemplate_NextDSTStart_Never(t *testing.T) {
+	after := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for _, template := range []Template{
+		{Zones: []Zone{{Name: "UTC"}}},
+		{Extend: "<+03>-3"},
+		{
+			Zones:   []Zone{{Name: "CET", Offset: time.Hour}, {Name: "CEST", Offset: 2 * time.Hour, IsDST: true}},
+			Changes: []Change{{Start: time.Date(1990, time.March, 25, 1, 0, 0, 0, time.UTC), ZoneIndex: 1}},
+		},
+	} {
+		if start, _, ok := template.NextDSTStart(after); ok {
+			t.Fatalf("%+v: expected no DST start, got %v", template, start)
+		}
+	}
+}
+
+func TestTemplate_ZoneAt(t *testing.T) {
+	template := Template{Extend: "CET-1CEST,M3.5.0,M10.5.0/3"}
+	if zone := template.ZoneAt(time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)); zone.Name != "CEST" {
+		t.Fatalf("expected CEST, got %+v", zone)
+	}
+	if zone := template.ZoneAt(time.Date(2022, time.December, 1, 0, 0, 0, 0, time.UTC)); zone.Name != "CET" {
+		t.Fatalf("expected CET, got %+v", zone)
+	}
+}
+
+func TestTemplate_SavingsAt(t *testing.T) {
+	gmt := Zone{Name: "GMT"}
+	bst := Zone{Name: "BST", Offset: time.Hour, IsDST: true}
+	bdst := Zone{Name: "BDST", Offset: 2 * time.Hour, IsDST: true}
+	template := Template{
+		Zones: []Zone{gmt, bst, bdst},
+		Changes: []Change{
+			{Start: time.Date(1941, time.March, 1, 0, 0, 0, 0, time.UTC), ZoneIndex: 1},
+			{Start: time.Date(1941, time.May, 4, 1, 0, 0, 0, time.UTC), ZoneIndex: 2},
+			{Start: time.Date(1941, time.August, 10, 1, 0, 0, 0, time.UTC), ZoneIndex: 1},
+			{Start: time.Date(1945, time.October, 7, 1, 0, 0, 0, time.UTC), ZoneIndex: 0},
+		},
+		Extend: "GMT0BST,M3.5.0/1,M10.5.0",
+	}
+	for _, tc := range []struct {
+		at       time.Time
+		expected time.Duration
+	}{
+		{time.Date(1940, time.January, 1, 0, 0, 0, 0, time.UTC), 0},
+		{time.Date(1941, time.April, 1, 0, 0, 0, 0, time.UTC), time.Hour},
+		// Double summer time is two hours ahead of the standard time, not of BST.
+		{time.Date(1941, time.June, 1, 0, 0, 0, 0, time.UTC), 2 * time.Hour},
+		{time.Date(1943, time.January, 1, 0, 0, 0, 0, time.UTC), time.Hour},
+		{time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC), time.Hour},
+	} {
+		if savings := template.SavingsAt(tc.at); savings != tc.expected {
+			t.Fatalf("%v: expected %v, got %v", tc.at, tc.expected, savings)
+		}
+	}
+
+	// Negative DST like Europe/Dublin.
+	dublin := Template{Extend: "IST-1GMT0,M10.5.0,M3.5.0/1"}
+	if savings := dublin.SavingsAt(time.Date(2022, time.December, 1, 0, 0, 0, 0, time.UTC)); savings != -time.Hour {
+		t.Fatalf("expected -1h, got %v", savings)
+	}
+
+	// No standard time before.
+	allDST := Template{Zones: []Zone{bst}}
+	if savings := allDST.SavingsAt(time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)); savings != 0 {
+		t.Fatalf("expected 0, got %v", savings)
+	}
+}
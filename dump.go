@@ -0,0 +1,59 @@
+package timezones
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Dump writes a human-readable description of the template to w, listing the zones,
+// changes with the zone that takes effect, the extend string and leap seconds.
+// It is meant for debugging and its format may change.
+func (t *Template) Dump(w io.Writer) error {
+	var buf bytes.Buffer
+	if t.Name != "" {
+		fmt.Fprintf(&buf, "Name: %s\n", t.Name)
+	}
+	fmt.Fprintf(&buf, "Zones: %d\n", len(t.Zones))
+	for i := range t.Zones {
+		fmt.Fprintf(&buf, "  %3d %s\n", i, formatZone(t.Zones[i]))
+	}
+	if t.FirstZoneIndex != 0 {
+		fmt.Fprintf(&buf, "First zone: %d\n", t.FirstZoneIndex)
+	}
+	fmt.Fprintf(&buf, "Changes: %d\n", len(t.Changes))
+	for i := range t.Changes {
+		c := t.Changes[i]
+		zone := "invalid zone"
+		if c.ZoneIndex >= 0 && c.ZoneIndex < len(t.Zones) {
+			zone = formatZone(t.Zones[c.ZoneIndex])
+		}
+		fmt.Fprintf(&buf, "  %s %3d %s\n", formatTime(c.Start), c.ZoneIndex, zone)
+	}
+	if t.Extend != "" {
+		fmt.Fprintf(&buf, "Extend: %s\n", t.Extend)
+	}
+	if len(t.LeapSeconds) > 0 {
+		fmt.Fprintf(&buf, "Leap seconds: %d\n", len(t.LeapSeconds))
+		for _, ls := range t.LeapSeconds {
+			fmt.Fprintf(&buf, "  %s %+d\n", formatTime(ls.Occurrence), ls.Correction)
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// formatZone formats the zone as designation, offset and std or dst.
+func formatZone(z Zone) string {
+	kind := "std"
+	if z.IsDST {
+		kind = "dst"
+	}
+	return fmt.Sprintf("%-6s %s %s", z.Name, formatOffset(z.Offset), kind)
+}
+
+// formatOffset formats a UTC offset as ±hh:mm, or ±hh:mm:ss if it is not a whole number of minutes.
+func formatOffset(d time.Duration) string {
+	return DurationOffset(d).String()
+}
@@ -0,0 +1,61 @@
+package timezones
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplate_Dump(t *testing.T) {
+	template := Template{
+		Name: "Europe/Moscow",
+		Zones: []Zone{
+			{Name: "LMT", Offset: 2*time.Hour + 30*time.Minute + 17*time.Second},
+			{Name: "MSK", Offset: 3 * time.Hour},
+			{Name: "MSD", Offset: 4 * time.Hour, IsDST: true},
+		},
+		Changes: []Change{
+			{Start: time.Date(1919, time.July, 1, 0, 0, 0, 0, time.UTC), ZoneIndex: 1},
+			{Start: time.Date(1981, time.March, 31, 21, 0, 0, 0, time.UTC), ZoneIndex: 2},
+		},
+		Extend: "MSK-3",
+		LeapSeconds: []LeapSecond{
+			{Occurrence: time.Unix(78796800, 0), Correction: 1},
+			{Occurrence: time.Unix(94694401, 0), Correction: 2},
+		},
+	}
+	var sb strings.Builder
+	if err := template.Dump(&sb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `Name: Europe/Moscow
+Zones: 3
+    0 LMT    +02:30:17 std
+    1 MSK    +03:00 std
+    2 MSD    +04:00 dst
+Changes: 2
+  1919-07-01T00:00:00Z   1 MSK    +03:00 std
+  1981-03-31T21:00:00Z   2 MSD    +04:00 dst
+Extend: MSK-3
+Leap seconds: 2
+  1972-07-01T00:00:00Z +1
+  1973-01-01T00:00:01Z +2
+`
+	if sb.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, sb.String())
+	}
+}
+
+func TestFormatOffset(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                            "+00:00",
+		-5 * time.Hour:               "-05:00",
+		5*time.Hour + 45*time.Minute: "+05:45",
+		-(time.Hour + time.Second):   "-01:00:01",
+	}
+	for d, expected := range tests {
+		if got := formatOffset(d); got != expected {
+			t.Fatalf("%v: expected %q, got %q", d, expected, got)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package timezones
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EquivalenceClasses groups the templates into classes that behave the same within [from, to),
+// i.e. templates for which Template.Diff reports no differences.
+// It is meant for deduplicating choices of zones, e.g. in pickers for future times, of the templates
+// loaded by LoadAll.
+// Each class holds the sorted names of its templates; the classes are sorted by their first name.
+func EquivalenceClasses(templates map[string]*Template, from, to time.Time) [][]string {
+	classes := make(map[string][]string)
+	for name, t := range templates {
+		key := behaviorKey(t, from, to)
+		classes[key] = append(classes[key], name)
+	}
+	result := make([][]string, 0, len(classes))
+	for _, names := range classes {
+		sort.Strings(names)
+		result = append(result, names)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i][0] < result[j][0]
+	})
+	return result
+}
+
+// behaviorKey returns a string that is the same for templates that behave the same within [from, to).
+func behaviorKey(t *Template, from, to time.Time) string {
+	var sb strings.Builder
+	for _, interval := range t.OffsetIntervals(from, to) {
+		fmt.Fprintf(&sb, "%d %q %d %t\n", interval.Start.Unix(), interval.Zone.Name,
+			offsetSeconds(interval.Zone), interval.Zone.IsDST)
+	}
+	return sb.String()
+}
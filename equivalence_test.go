@@ -0,0 +1,45 @@
+package timezones
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEquivalenceClasses(t *testing.T) {
+	cet := Zone{Name: "CET", Offset: time.Hour}
+	cest := Zone{Name: "CEST", Offset: 2 * time.Hour, IsDST: true}
+	extend := "CET-1CEST,M3.5.0,M10.5.0/3"
+	templates := map[string]*Template{
+		"Europe/Prague": {Zones: []Zone{cet}, Extend: extend},
+		// Differs only before the range.
+		"Europe/Berlin": {
+			Zones:   []Zone{{Name: "LMT", Offset: 53*time.Minute + 28*time.Second}, cet, cest},
+			Changes: []Change{{Start: time.Date(1893, time.March, 31, 23, 6, 32, 0, time.UTC), ZoneIndex: 1}},
+			Extend:  extend,
+		},
+		"Europe/Vienna":  {Extend: extend},
+		"Etc/GMT-1":      {Zones: []Zone{{Name: "+01", Offset: time.Hour}}},
+		"Africa/Lagos":   {Zones: []Zone{{Name: "WAT", Offset: time.Hour}}},
+		"Africa/Algiers": {Zones: []Zone{cet}},
+		"Africa/Tunis":   {Zones: []Zone{cet}},
+	}
+	expected := [][]string{
+		{"Africa/Algiers", "Africa/Tunis"},
+		{"Africa/Lagos"},
+		{"Etc/GMT-1"},
+		{"Europe/Berlin", "Europe/Prague", "Europe/Vienna"},
+	}
+	classes := EquivalenceClasses(templates, time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC))
+	if !reflect.DeepEqual(classes, expected) {
+		t.Fatalf("expected %v, got %v", expected, classes)
+	}
+
+	// Before 1893, Berlin uses LMT.
+	classes = EquivalenceClasses(templates, time.Date(1890, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC))
+	if len(classes) != 5 {
+		t.Fatalf("expected 5 classes, got %v", classes)
+	}
+}
@@ -0,0 +1,152 @@
+package timezones
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned when decoding TZif data.
+var (
+	// ErrInvalid is returned when TZif data is malformed.
+	ErrInvalid = errors.New("timezones: invalid tzdata")
+
+	// ErrUnsupportedVersion is returned when TZif data has a version this package does not understand.
+	// Encoder returns it when Encoder.Version is not a version it can write.
+	ErrUnsupportedVersion = errors.New("timezones: unsupported tzdata version")
+
+	// ErrUnsupportedIndicators is returned when TZif data contains standard/wall or UT/local indicators
+	// that this package can't represent.
+	ErrUnsupportedIndicators = errors.New("timezones: unsupported isstd/isut indicator values")
+
+	// ErrLimitExceeded is returned when TZif data exceeds the limits configured in Decoder.
+	ErrLimitExceeded = errors.New("timezones: tzdata exceeds decoder limits")
+)
+
+// Errors returned when encoding templates.
+// The errors are usually wrapped with details about the problem, use errors.Is to check for them.
+// Problems with a particular zone or change are reported as *FieldError.
+var (
+	// ErrNoZones is returned when the template has neither Zones nor Extend.
+	ErrNoZones = errors.New("timezones: either zones or extend string need to be present")
+
+	// ErrTooManyChanges is returned when the template has more changes than TZif can hold.
+	ErrTooManyChanges = errors.New("timezones: too many changes")
+
+	// ErrUnorderedChanges is returned when Changes are not in strictly ascending order of Start.
+	ErrUnorderedChanges = errors.New("timezones: zone changes must be in strictly ascending order")
+
+	// ErrDuplicateChange is returned when two consecutive Changes start at the same second.
+	// TZif stores whole seconds, so changes that differ only in the sub-second part are duplicates too.
+	ErrDuplicateChange = errors.New("timezones: duplicate zone change")
+
+	// ErrStartOutOfRange is returned when Change.Start can't be represented as a 64-bit TZif time.
+	ErrStartOutOfRange = errors.New("timezones: change start out of range")
+
+	// ErrZoneIndex is returned when Change.ZoneIndex does not refer to an existing zone.
+	ErrZoneIndex = errors.New("timezones: zone index out of range")
+
+	// ErrInvalidOffset is returned when Zone.Offset can't be represented in TZif.
+	ErrInvalidOffset = errors.New("timezones: invalid offset")
+
+	// ErrInvalidDesignation is returned when Zone.Name can't be used as a time zone designation.
+	ErrInvalidDesignation = errors.New("timezones: invalid designation")
+
+	// ErrDesignationsTooLong is returned when zone names don't fit into the space TZif provides for them.
+	ErrDesignationsTooLong = errors.New("timezones: time zone designations don't fit into limit")
+
+	// ErrInvalidLeapSecond is returned when Template.LeapSeconds can't be stored in TZif.
+	ErrInvalidLeapSecond = errors.New("timezones: invalid leap second")
+
+	// ErrDesignationRewritten is reported to Encoder.Warn for each zone name that
+	// Encoder.AbbreviateDesignations replaced with a numeric designation.
+	ErrDesignationRewritten = errors.New("timezones: time zone designation rewritten")
+
+	// ErrInvalidExtend is returned when Extend is not a valid TZ string or can't be stored in TZif footer.
+	ErrInvalidExtend = errors.New("timezones: invalid extend string")
+
+	// ErrNoStandardZone is reported to Encoder.Warn when all zones have IsDST set.
+	// That is almost always a bug in the data and some consumers, including Go's selection of
+	// the zone in effect before the first change in TZif data, behave oddly in that case.
+	ErrNoStandardZone = errors.New("timezones: no standard time zone")
+
+	// ErrDiscontinuity is reported when the zone of the last change disagrees with Extend at the time of
+	// the last change.
+	// It is only returned as an error if Encoder.StrictContinuity is set, otherwise it is a warning.
+	ErrDiscontinuity = errors.New("timezones: last change disagrees with extend string")
+
+	// ErrFirstZoneMismatch is reported to Encoder.Warn when Go's time package uses a different zone
+	// before the first change than the one the data specifies, see Encoder.StrictLocalTimeTypes.
+	ErrFirstZoneMismatch = errors.New("timezones: Go uses a different zone before the first change")
+
+	// ErrMissingUntil is returned by PeriodsTemplate when a period other than the last one has no end.
+	ErrMissingUntil = errors.New("timezones: period has no end")
+)
+
+// ErrSelfCheck is returned when Encoder.SelfCheck is enabled and the encoded data does not decode
+// to the original template.
+var ErrSelfCheck = errors.New("timezones: self-check of encoded data failed")
+
+// ErrRoundTrip is returned by VerifyRoundTrip when the re-encoded data behaves differently than the original.
+var ErrRoundTrip = errors.New("timezones: tzdata does not round-trip")
+
+// ErrTooManyZones is returned when a template has more zones than the package supports,
+// both when encoding and decoding.
+var ErrTooManyZones = errors.New("timezones: too many zones")
+
+// ErrMemoryLimit is returned by LoadAll when the loaded templates exceed LoadOptions.MemoryLimit.
+var ErrMemoryLimit = errors.New("timezones: memory limit exceeded")
+
+// ErrNotCanonical is returned by ValidateStrict when a template is valid but not in the canonical form.
+var ErrNotCanonical = errors.New("timezones: template is not canonical")
+
+// ErrInvalidZoneTab is returned by ParseZoneTab when the zone table is malformed.
+var ErrInvalidZoneTab = errors.New("timezones: invalid zone table")
+
+// Errors returned by ApplyRenames.
+var (
+	// ErrUnknownZone is returned when a renamed zone is not present.
+	ErrUnknownZone = errors.New("timezones: unknown zone")
+
+	// ErrNameConflict is returned when a zone is renamed to a name that is already used.
+	ErrNameConflict = errors.New("timezones: zone name already used")
+)
+
+// ErrMergeGap is returned by Merge when no source has data for some interval.
+var ErrMergeGap = errors.New("timezones: no source covers interval")
+
+// Errors returned when resolving local wall-clock times, see ResolveLocal.
+var (
+	// ErrAmbiguousTime is returned when a local time occurs more than once, usually when clocks are set back.
+	ErrAmbiguousTime = errors.New("timezones: ambiguous local time")
+
+	// ErrNonexistentTime is returned when a local time is skipped, usually when clocks are set forward.
+	ErrNonexistentTime = errors.New("timezones: nonexistent local time")
+)
+
+// FieldError describes a problem with a single element of a Template field.
+type FieldError struct {
+	// Field is the name of the Template field, "Zones", "Changes" or "LeapSeconds",
+	// or "Periods" for the periods passed to PeriodsTemplate.
+	Field string
+
+	// Index of the offending element in Field.
+	Index int
+
+	// Err is one of the errors of this package describing the kind of the problem, e.g. ErrZoneIndex.
+	Err error
+
+	// Detail describes the problem.
+	Detail string
+}
+
+func (e *FieldError) Error() string {
+	msg := fmt.Sprintf("%v in %s[%d]", e.Err, e.Field, e.Index)
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
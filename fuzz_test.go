@@ -0,0 +1,51 @@
+//go:build go1.18
+
+package timezones
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func FuzzLoadTZData(f *testing.F) {
+	for _, template := range []Template{
+		benchTemplate(),
+		{Zones: []Zone{{Name: "MyFixed", Offset: 2*time.Hour + 23*time.Minute}}},
+		{Extend: "<MyExt>-02:23:00<MyExtDST>-03:23:00,M1.2.3/10:00:00,M2.3.4/10:00:00"},
+	} {
+		tzdata, err := TZData(template)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(tzdata)
+	}
+	raw := rawTZif{
+		version: 0,
+		times:   []int64{100},
+		types:   []byte{1},
+		ltt: []rawLocalTimeType{
+			{utoff: 0, isdst: 0, idx: 0},
+			{utoff: 3600, isdst: 1, idx: 4},
+		},
+		chars: "UTC\x00CET\x00",
+	}
+	f.Add(raw.bytes())
+
+	f.Fuzz(func(t *testing.T, tzdata []byte) {
+		template, err := LoadTZData(tzdata)
+		if err != nil {
+			return
+		}
+		for i, c := range template.Changes {
+			if c.ZoneIndex < 0 || c.ZoneIndex >= len(template.Zones) {
+				t.Fatalf("change %d refers to zone %d of %d", i, c.ZoneIndex, len(template.Zones))
+			}
+		}
+		// Whatever the encoder accepts must decode to the same template.
+		e := Encoder{SelfCheck: true}
+		if _, err := e.Encode(*template); errors.Is(err, ErrSelfCheck) {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package timezones
+
+import "time"
+
+// ZoneLine is a line of a Zone entry in zic source, the optional metadata of Template.History.
+type ZoneLine struct {
+	// Rules is the RULES column: the name of the rule the zone followed, "-" for standard time only,
+	// or the amount of daylight saving time, e.g. "1:00".
+	Rules string
+
+	// Format is the FORMAT column, e.g. "CE%sT".
+	Format string
+
+	// Until is the UNTIL column converted to an instant, zero for the last line.
+	// zic interprets UNTIL in local time by default, so it needs to be converted with the offset
+	// in effect on the line.
+	Until time.Time
+}
+
+// HistoryPeriod is a period of the history of a zone with the same standard time offset,
+// see Template.History.
+type HistoryPeriod struct {
+	// Start and End delimit the period [Start, End).
+	// Start is zero for the first period and End is zero for the last one.
+	Start, End time.Time
+
+	// StandardOffset is the offset of standard time during the period.
+	StandardOffset Offset
+
+	// Designations are the distinct zone names used during the period, in the order of first use.
+	Designations []string
+
+	// DST reports whether daylight saving time was observed during the period.
+	DST bool
+
+	// Changes is the number of changes of the zone during the period, not counting the one that starts it.
+	Changes int
+
+	// Rule is the rule the zone followed during the period: Rules of the zone line if lines are given,
+	// otherwise Extend for the last period and empty for the others.
+	Rule string
+
+	// Format is Format of the zone line, if lines are given.
+	Format string
+}
+
+// History summarizes the history of the zone as periods with the same standard time offset, like the
+// history tables of time zone websites.
+// A period also ends at Until of each of lines, if given, so that each period follows a single rule.
+// The last period includes the zones of Extend.
+//
+// The standard time offset of DST zones is derived from the savings reported by Template.Savings.
+func (t *Template) History(lines []ZoneLine) []HistoryPeriod {
+	savings := make(map[int]time.Duration)
+	for _, s := range t.Savings() {
+		savings[s.ZoneIndex] = s.Savings
+	}
+	zl := newZoneLookup(t)
+	standardOffset := func(zoneIndex int, zone Zone) Offset {
+		if zone.IsDST {
+			return DurationOffset(zone.Offset - savings[zoneIndex])
+		}
+		return OffsetOf(zone)
+	}
+	line := 0
+	// lineAt returns the index of the zone line in effect at instant, or -1 if there are no lines.
+	// The instants must not decrease between calls.
+	lineAt := func(instant time.Time) int {
+		for line < len(lines)-1 && !lines[line].Until.IsZero() && !instant.Before(lines[line].Until) {
+			line++
+		}
+		if len(lines) == 0 {
+			return -1
+		}
+		return line
+	}
+
+	var periods []HistoryPeriod
+	currentLine := -2
+	add := func(start time.Time, zoneIndex int, zone Zone) {
+		std := standardOffset(zoneIndex, zone)
+		l := lineAt(start)
+		n := len(periods)
+		if n == 0 || periods[n-1].StandardOffset != std || l != currentLine {
+			if n > 0 {
+				periods[n-1].End = start
+			}
+			p := HistoryPeriod{Start: start, StandardOffset: std}
+			if l >= 0 {
+				p.Rule, p.Format = lines[l].Rules, lines[l].Format
+			}
+			periods = append(periods, p)
+			currentLine = l
+		} else {
+			periods[n-1].Changes++
+		}
+		addZone(&periods[len(periods)-1], zone)
+	}
+
+	add(time.Time{}, t.FirstZoneIndex, zl.zone(t.FirstZoneIndex))
+	for _, c := range t.Changes {
+		add(c.Start, c.ZoneIndex, zl.zone(c.ZoneIndex))
+	}
+	if t.Extend == "" {
+		return periods
+	}
+	rule, err := cachedTZRule(t.Extend)
+	if err != nil {
+		return periods
+	}
+	if len(t.Zones) <= 1 {
+		// Extend applies since the beginning of time, see Template.Extend.
+		periods = []HistoryPeriod{{StandardOffset: OffsetOf(rule.std)}}
+		if len(lines) > 0 {
+			periods[0].Rule, periods[0].Format = lines[0].Rules, lines[0].Format
+		}
+	}
+	last := &periods[len(periods)-1]
+	if len(lines) == 0 {
+		last.Rule = t.Extend
+	}
+	addZone(last, rule.std)
+	if rule.hasDST {
+		addZone(last, rule.dst)
+	}
+	return periods
+}
+
+// addZone records that zone was used during the period.
+func addZone(p *HistoryPeriod, zone Zone) {
+	p.DST = p.DST || zone.IsDST
+	for _, name := range p.Designations {
+		if name == zone.Name {
+			return
+		}
+	}
+	p.Designations = append(p.Designations, zone.Name)
+}
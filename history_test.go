@@ -0,0 +1,100 @@
+package timezones
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func historyTemplate() Template {
+	date := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	return Template{
+		Zones: []Zone{
+			{Name: "LMT", Offset: time.Hour + 8*time.Minute},
+			{Name: "CET", Offset: time.Hour},
+			{Name: "CEST", Offset: 2 * time.Hour, IsDST: true},
+		},
+		Changes: []Change{
+			{Start: date(1891, time.September, 30, 22), ZoneIndex: 1},
+			{Start: date(1916, time.April, 30, 22), ZoneIndex: 2},
+			{Start: date(1916, time.September, 30, 23), ZoneIndex: 1},
+			{Start: date(1979, time.April, 1, 1), ZoneIndex: 2},
+			{Start: date(1979, time.September, 30, 1), ZoneIndex: 1},
+		},
+		Extend: "CET-1CEST,M3.5.0,M10.5.0/3",
+	}
+}
+
+func TestTemplate_History(t *testing.T) {
+	template := historyTemplate()
+	expected := []HistoryPeriod{
+		{
+			End:            template.Changes[0].Start,
+			StandardOffset: 3600 + 8*60,
+			Designations:   []string{"LMT"},
+		},
+		{
+			Start:          template.Changes[0].Start,
+			StandardOffset: 3600,
+			Designations:   []string{"CET", "CEST"},
+			DST:            true,
+			Changes:        4,
+			Rule:           "CET-1CEST,M3.5.0,M10.5.0/3",
+		},
+	}
+	if history := template.History(nil); !reflect.DeepEqual(history, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, history)
+	}
+
+	lines := []ZoneLine{
+		{Rules: "-", Format: "LMT", Until: template.Changes[0].Start},
+		{Rules: "C-Eur", Format: "CE%sT", Until: time.Date(1979, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{Rules: "EU", Format: "CE%sT"},
+	}
+	expected = []HistoryPeriod{
+		{
+			End:            template.Changes[0].Start,
+			StandardOffset: 3600 + 8*60,
+			Designations:   []string{"LMT"},
+			Rule:           "-",
+			Format:         "LMT",
+		},
+		{
+			Start:          template.Changes[0].Start,
+			End:            template.Changes[3].Start,
+			StandardOffset: 3600,
+			Designations:   []string{"CET", "CEST"},
+			DST:            true,
+			Changes:        2,
+			Rule:           "C-Eur",
+			Format:         "CE%sT",
+		},
+		{
+			Start:          template.Changes[3].Start,
+			StandardOffset: 3600,
+			Designations:   []string{"CEST", "CET"},
+			DST:            true,
+			Changes:        1,
+			Rule:           "EU",
+			Format:         "CE%sT",
+		},
+	}
+	if history := template.History(lines); !reflect.DeepEqual(history, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, history)
+	}
+}
+
+func TestTemplate_History_ExtendOnly(t *testing.T) {
+	template := Template{Extend: "EST5EDT,M3.2.0,M11.1.0"}
+	expected := []HistoryPeriod{{
+		StandardOffset: -5 * 3600,
+		Designations:   []string{"EST", "EDT"},
+		DST:            true,
+		Rule:           "EST5EDT,M3.2.0,M11.1.0",
+	}}
+	if history := template.History(nil); !reflect.DeepEqual(history, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, history)
+	}
+}
@@ -0,0 +1,108 @@
+package timezones
+
+import (
+	"sort"
+	"time"
+)
+
+// Index is a prebuilt lookup structure for the zones of a template, see Template.Index.
+// It is safe for concurrent use.
+type Index struct {
+	// changes are Template.Changes in the compact form, with invalid zone indices replaced by noZone.
+	changes []CompactChange
+	// first is the index of the zone in effect before the first change.
+	first uint8
+	// zoneList is a copy of Template.Zones.
+	zoneList []Zone
+	// rule is the parsed Extend, valid only if hasRule is true.
+	rule    tzRule
+	hasRule bool
+}
+
+// noZone marks invalid zone indices in Index, which look up to an empty zone like in zoneLookup.
+const noZone = 255
+
+// Index builds an index for looking up the zone in effect at many instants, e.g. in hot paths.
+// Lookups in the index compare plain Unix times instead of time.Time values and don't parse Extend again.
+// The index is a snapshot: later modifications of the template don't affect it.
+func (t *Template) Index() *Index {
+	x := &Index{
+		changes:  make([]CompactChange, len(t.Changes)),
+		first:    indexZone(t.FirstZoneIndex, len(t.Zones)),
+		zoneList: append([]Zone(nil), t.Zones...),
+	}
+	for i, c := range t.Changes {
+		x.changes[i] = CompactChange{Start: c.Start.Unix(), ZoneIndex: indexZone(c.ZoneIndex, len(t.Zones))}
+	}
+	if t.Extend != "" {
+		rule, err := cachedTZRule(t.Extend)
+		// Like Go, ignore an invalid extend string.
+		if err == nil {
+			x.rule = rule
+			x.hasRule = true
+		}
+	}
+	return x
+}
+
+func indexZone(i, n int) uint8 {
+	if i < 0 || i >= n || i >= noZone {
+		return noZone
+	}
+	return uint8(i)
+}
+
+// ZoneAt returns the zone in effect at instant, see Template.ZoneAt.
+func (x *Index) ZoneAt(instant time.Time) Zone {
+	return x.Lookup(instant.Unix())
+}
+
+// Lookup returns the zone in effect at sec, in Unix time.
+func (x *Index) Lookup(sec int64) Zone {
+	zone, _, _ := x.lookup(sec)
+	return zone
+}
+
+// lookup returns the zone in effect at sec along with the interval [start, end) during which the zone
+// is known to be in effect, like zoneLookup.lookup.
+func (x *Index) lookup(sec int64) (zone Zone, start, end int64) {
+	changes := x.changes
+	if len(changes) == 0 {
+		if x.hasRule {
+			return x.rule.lookup(sec)
+		}
+		return x.zone(x.first), alpha, omega
+	}
+	// i is the index of the first change after sec.
+	i := sort.Search(len(changes), func(i int) bool {
+		return changes[i].Start > sec
+	})
+	if i == 0 {
+		return x.zone(x.first), alpha, changes[0].Start
+	}
+	start = changes[i-1].Start
+	if i < len(changes) {
+		return x.zone(changes[i-1].ZoneIndex), start, changes[i].Start
+	}
+	if !x.hasRule {
+		return x.zone(changes[i-1].ZoneIndex), start, omega
+	}
+	zone, ruleStart, end := x.rule.lookup(sec)
+	if ruleStart < start {
+		ruleStart = start
+	}
+	return zone, ruleStart, end
+}
+
+// Changes returns an iterator over the changes of the index in the compact form.
+// Zone indices that are out of range in the template are 255.
+func (x *Index) Changes() *ChangeIterator {
+	return NewChangeIterator(x.changes)
+}
+
+func (x *Index) zone(i uint8) Zone {
+	if int(i) >= len(x.zoneList) {
+		return Zone{}
+	}
+	return x.zoneList[i]
+}
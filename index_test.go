@@ -0,0 +1,80 @@
+package timezones
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTemplate_Index(t *testing.T) {
+	templates := []Template{
+		benchTemplate(),
+		{Zones: []Zone{{Name: "CET", Offset: time.Hour}}, Extend: "CET-1CEST,M3.5.0,M10.5.0/3"},
+		{Zones: []Zone{{Name: "UTC"}}},
+		{
+			Zones:          []Zone{{Name: "CEST", Offset: 2 * time.Hour, IsDST: true}, {Name: "CET", Offset: time.Hour}},
+			FirstZoneIndex: 1,
+			Changes:        []Change{{Start: time.Date(2020, time.March, 29, 1, 0, 0, 0, time.UTC), ZoneIndex: 0}},
+			Extend:         "CET-1CEST,M3.5.0,M10.5.0/3",
+		},
+	}
+	for i := range templates {
+		template := &templates[i]
+		index := template.Index()
+		for sec := time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC).Unix(); sec < 4102444800; sec += 86400*7 + 3607 {
+			at := time.Unix(sec, 0)
+			if got, expected := index.ZoneAt(at), template.ZoneAt(at); got != expected {
+				t.Fatalf("template %d at %v: expected %+v, got %+v", i, at, expected, got)
+			}
+		}
+	}
+}
+
+func TestTemplate_Index_Snapshot(t *testing.T) {
+	template := Template{Zones: []Zone{{Name: "UTC"}}}
+	index := template.Index()
+	template.Zones[0].Name = "XXX"
+	if zone := index.Lookup(0); zone.Name != "UTC" {
+		t.Fatalf("expected UTC, got %+v", zone)
+	}
+}
+
+func BenchmarkIndex_ZoneAt(b *testing.B) {
+	template := benchTemplate()
+	index := template.Index()
+	at := template.Changes[len(template.Changes)/2].Start.Add(time.Hour)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		index.ZoneAt(at)
+	}
+}
+
+func BenchmarkTemplate_ZoneAt(b *testing.B) {
+	template := benchTemplate()
+	at := template.Changes[len(template.Changes)/2].Start.Add(time.Hour)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		template.ZoneAt(at)
+	}
+}
+
+func TestIndex_Changes(t *testing.T) {
+	template := benchTemplate()
+	template.Changes = append(template.Changes, Change{Start: time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC), ZoneIndex: 7})
+	it := template.Index().Changes()
+	n := 0
+	for it.Next() {
+		c := it.Compact()
+		expected := template.Changes[it.Index()]
+		zoneIndex := uint8(expected.ZoneIndex)
+		if expected.ZoneIndex >= len(template.Zones) {
+			zoneIndex = noZone
+		}
+		if c.Start != expected.Start.Unix() || c.ZoneIndex != zoneIndex {
+			t.Fatalf("change %d: expected %+v, got %+v", it.Index(), expected, c)
+		}
+		n++
+	}
+	if n != len(template.Changes) {
+		t.Fatalf("expected %d changes, got %d", len(template.Changes), n)
+	}
+}
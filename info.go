@@ -0,0 +1,77 @@
+package timezones
+
+import "fmt"
+
+// Info summarizes TZif data without decoding it, see QuickInfo.
+type Info struct {
+	// Version of the TZif data, 1, 2 or 3.
+	Version int
+
+	// Counts from the header of the data block that would be decoded by LoadTZData.
+	// For version 1 data, that is the V1 header, for later versions the V2+ header.
+	IsUTCount       int
+	IsStdCount      int
+	LeapCount       int
+	TransitionCount int
+	TypeCount       int
+	CharCount       int
+
+	// HasFooter reports whether the data contains a footer.
+	// Version 1 data never contains a footer.
+	HasFooter bool
+
+	// Extend is the TZ string from the footer.
+	// It is empty if there is no footer or the footer is empty.
+	Extend string
+}
+
+// QuickInfo reads the headers and the footer of TZif data.
+// Unlike LoadTZData, it does not decode transitions nor local time types, so it is suitable for
+// scanning a large number of files.
+// QuickInfo only checks that the headers are consistent with the length of the data.
+func QuickInfo(tzdata []byte) (Info, error) {
+	h, rest, err := parseHeaders(tzdata)
+	if err != nil {
+		return Info{}, err
+	}
+	info := Info{
+		Version:         h.version,
+		IsUTCount:       int(h.isutcnt),
+		IsStdCount:      int(h.isstdcnt),
+		LeapCount:       int(h.leapcnt),
+		TransitionCount: int(h.timecnt),
+		TypeCount:       int(h.typecnt),
+		CharCount:       int(h.charcnt),
+	}
+	if h.version > 1 {
+		info.Extend, info.HasFooter, _ = splitFooter(rest[h.dataSize():])
+	}
+	return info, nil
+}
+
+// SuffixDesignations returns the indices of local time types in the decoded data block whose designation
+// is a suffix of another designation, i.e. whose designation index points into the middle of a stored
+// designation instead of its start.
+// The indices refer to the local time type records as stored in the data, not to Template.Zones.
+//
+// RFC 8536 allows such sharing, but some validators don't, see Decoder.StrictDesignationIndices.
+func SuffixDesignations(tzdata []byte) ([]int, error) {
+	h, rest, err := parseHeaders(tzdata)
+	if err != nil {
+		return nil, err
+	}
+	rest = rest[int(h.timecnt)*(h.tsize+1):]
+	ltt, rest := rest[:h.typecnt*6], rest[h.typecnt*6:]
+	chars := string(rest[:h.charcnt])
+	var suffixes []int
+	for i := 0; i < int(h.typecnt); i++ {
+		idx := int(ltt[i*6+5])
+		if idx >= len(chars) {
+			return nil, fmt.Errorf("%w: designation index %d of local time type %d out of range", ErrInvalid, idx, i)
+		}
+		if !designationStart(chars, idx) {
+			suffixes = append(suffixes, i)
+		}
+	}
+	return suffixes, nil
+}
@@ -0,0 +1,140 @@
+package timezones
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQuickInfo(t *testing.T) {
+	tzdata, err := TZData(benchTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := QuickInfo(tzdata)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Info{
+		Version:         3,
+		IsUTCount:       100,
+		IsStdCount:      100,
+		LeapCount:       0,
+		TransitionCount: 100,
+		TypeCount:       3,
+		CharCount:       8,
+		HasFooter:       true,
+		Extend:          "",
+	}
+	if info != expected {
+		t.Fatalf("expected %+v, got %+v", expected, info)
+	}
+}
+
+func TestQuickInfo_Extend(t *testing.T) {
+	extend := "<MyExt>-02:23:00<MyExtDST>-03:23:00,M1.2.3/10:00:00,M2.3.4/10:00:00"
+	tzdata, err := TZData(Template{
+		Zones:  []Zone{{Name: "MyExt", Offset: 2*time.Hour + 23*time.Minute}},
+		Extend: extend,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := QuickInfo(tzdata)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !info.HasFooter || info.Extend != extend {
+		t.Fatalf("unexpected footer in %+v", info)
+	}
+}
+
+func TestQuickInfo_Trailing(t *testing.T) {
+	e := Encoder{KeepTrailing: true}
+	tzdata, err := e.Encode(Template{
+		Zones:    []Zone{{Name: "CET", Offset: time.Hour}},
+		Extend:   "CET-1",
+		Trailing: []byte("extension\n"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := QuickInfo(tzdata)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !info.HasFooter || info.Extend != "CET-1" {
+		t.Fatalf("unexpected footer in %+v", info)
+	}
+	template, err := LoadTZData(tzdata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if template.Extend != info.Extend {
+		t.Fatalf("expected Extend %q like LoadTZData, got %q", template.Extend, info.Extend)
+	}
+}
+
+func TestQuickInfo_Invalid(t *testing.T) {
+	tzdata, err := TZData(benchTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs := [][]byte{
+		nil,
+		[]byte("TZif"),
+		[]byte("XXXX3abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyz"),
+		tzdata[:len(tzdata)-200],
+	}
+	for _, input := range inputs {
+		if _, err := QuickInfo(input); err == nil {
+			t.Fatalf("expected error for %q", input)
+		}
+	}
+}
+
+func TestSuffixDesignations(t *testing.T) {
+	template := Template{
+		Zones: []Zone{
+			{Name: "CET", Offset: time.Hour},
+			{Name: "CEST", Offset: 2 * time.Hour, IsDST: true},
+			{Name: "EST", Offset: -5 * time.Hour},
+		},
+		Changes: []Change{
+			{Start: time.Unix(1000, 0), ZoneIndex: 1},
+			{Start: time.Unix(2000, 0), ZoneIndex: 2},
+		},
+	}
+	tzdata, err := TZData(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	suffixes, err := SuffixDesignations(tzdata)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Local time type 0 is the zone in effect before the first change, so EST is type 3.
+	if !reflect.DeepEqual(suffixes, []int{3}) {
+		t.Fatalf("unexpected suffixes %v", suffixes)
+	}
+
+	if _, err := LoadTZData(tzdata); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := Decoder{StrictDesignationIndices: true}
+	if _, err := d.Decode(tzdata); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid, got %v", err)
+	}
+
+	tzdata, err = TZData(benchTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suffixes, err := SuffixDesignations(tzdata); err != nil || len(suffixes) != 0 {
+		t.Fatalf("expected no suffixes, got %v, %v", suffixes, err)
+	}
+	if _, err := d.Decode(tzdata); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package timezones
+
+import "time"
+
+// Interval is a time interval during which a single zone is in effect, see Template.OffsetIntervals.
+type Interval struct {
+	// Start and End delimit the interval [Start, End).
+	Start, End time.Time
+
+	// Zone is in effect during the interval.
+	Zone Zone
+}
+
+// OffsetIntervals returns contiguous intervals covering [from, to), each with the zone in effect,
+// in increasing order.
+// Adjacent intervals have different zones; the first starts at from and the last ends at to.
+// It returns nil if to is not after from.
+func (t *Template) OffsetIntervals(from, to time.Time) []Interval {
+	zl := newZoneLookup(t)
+	var intervals []Interval
+	end := to.Unix()
+	for sec := from.Unix(); sec < end; {
+		zone, _, next := zl.lookup(sec)
+		if next > end {
+			next = end
+		}
+		if n := len(intervals); n > 0 && sameZone(intervals[n-1].Zone, zone) {
+			intervals[n-1].End = time.Unix(next, 0).UTC()
+		} else {
+			intervals = append(intervals, Interval{Start: time.Unix(sec, 0).UTC(), End: time.Unix(next, 0).UTC(), Zone: zone})
+		}
+		sec = next
+	}
+	return intervals
+}
+
+// OffsetPoint is the offset from UTC in effect at an instant, see Template.SampleOffsets.
+type OffsetPoint struct {
+	At     time.Time
+	Offset time.Duration
+}
+
+// SampleOffsets returns the offsets in effect at instants from from (inclusive) to to (exclusive)
+// spaced by step, along with the offsets at each change of the offset within the range, in increasing order.
+// It is meant for charting the offset history of a zone: connecting the points with steps gives the exact
+// offset at all times. If step is not positive, only the changes and from are sampled.
+func (t *Template) SampleOffsets(from, to time.Time, step time.Duration) []OffsetPoint {
+	var points []OffsetPoint
+	add := func(at time.Time, offset time.Duration) {
+		if n := len(points); n > 0 && !points[n-1].At.Before(at) {
+			return
+		}
+		points = append(points, OffsetPoint{At: at, Offset: offset})
+	}
+	next := from
+	for i, interval := range t.OffsetIntervals(from, to) {
+		offset := interval.Zone.Offset.Round(time.Second)
+		if i == 0 || offset != points[len(points)-1].Offset {
+			add(interval.Start, offset)
+		}
+		for step > 0 && next.Before(interval.End) {
+			add(next, offset)
+			next = next.Add(step)
+		}
+	}
+	return points
+}
+
+// Filter returns the intervals within [from, to) during which the zone in effect satisfies keep,
+// in increasing order, e.g. to find when the offset was exactly +02:00.
+// Each interval has a single zone, so intervals with different zones that both satisfy keep
+// may be adjacent.
+func (t *Template) Filter(from, to time.Time, keep func(Zone) bool) []Interval {
+	var intervals []Interval
+	for _, interval := range t.OffsetIntervals(from, to) {
+		if keep(interval.Zone) {
+			intervals = append(intervals, interval)
+		}
+	}
+	return intervals
+}
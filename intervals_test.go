@@ -0,0 +1,92 @@
+package timezones
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTemplate_OffsetIntervals(t *testing.T) {
+	cet := Zone{Name: "CET", Offset: time.Hour}
+	cest := Zone{Name: "CEST", Offset: 2 * time.Hour, IsDST: true}
+	template := Template{Zones: []Zone{cet}, Extend: "CET-1CEST,M3.5.0,M10.5.0/3"}
+	utc := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	expected := []Interval{
+		{Start: utc(2021, time.July, 1, 0), End: utc(2021, time.October, 31, 1), Zone: cest},
+		// Lookups are split at the year boundary, the intervals are not.
+		{Start: utc(2021, time.October, 31, 1), End: utc(2022, time.March, 27, 1), Zone: cet},
+		{Start: utc(2022, time.March, 27, 1), End: utc(2022, time.May, 1, 0), Zone: cest},
+	}
+	if intervals := template.OffsetIntervals(utc(2021, time.July, 1, 0), utc(2022, time.May, 1, 0)); !reflect.DeepEqual(intervals, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, intervals)
+	}
+
+	if intervals := template.OffsetIntervals(utc(2022, time.May, 1, 0), utc(2022, time.May, 1, 0)); intervals != nil {
+		t.Fatalf("expected no intervals, got %+v", intervals)
+	}
+
+	fixed := Template{Zones: []Zone{cet}}
+	expected = []Interval{{Start: utc(1900, time.January, 1, 0), End: utc(2100, time.January, 1, 0), Zone: cet}}
+	if intervals := fixed.OffsetIntervals(utc(1900, time.January, 1, 0), utc(2100, time.January, 1, 0)); !reflect.DeepEqual(intervals, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, intervals)
+	}
+}
+
+func TestTemplate_SampleOffsets(t *testing.T) {
+	template := Template{Extend: "CET-1CEST,M3.5.0,M10.5.0/3"}
+	utc := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2022, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	expected := []OffsetPoint{
+		{At: utc(time.March, 26, 0), Offset: time.Hour},
+		{At: utc(time.March, 27, 0), Offset: time.Hour},
+		{At: utc(time.March, 27, 1), Offset: 2 * time.Hour},
+		{At: utc(time.March, 28, 0), Offset: 2 * time.Hour},
+	}
+	points := template.SampleOffsets(utc(time.March, 26, 0), utc(time.March, 29, 0), 24*time.Hour)
+	if !reflect.DeepEqual(points, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, points)
+	}
+
+	// Only changes.
+	expected = []OffsetPoint{
+		{At: utc(time.January, 1, 0), Offset: time.Hour},
+		{At: utc(time.March, 27, 1), Offset: 2 * time.Hour},
+		{At: utc(time.October, 30, 1), Offset: time.Hour},
+	}
+	points = template.SampleOffsets(utc(time.January, 1, 0), time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC), 0)
+	if !reflect.DeepEqual(points, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, points)
+	}
+
+	// A change of the designation only is not a change of the offset.
+	renamed := Template{
+		Zones:   []Zone{{Name: "AAA", Offset: time.Hour}, {Name: "BBB", Offset: time.Hour}},
+		Changes: []Change{{Start: utc(time.June, 1, 0), ZoneIndex: 1}},
+	}
+	points = renamed.SampleOffsets(utc(time.January, 1, 0), time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC), 0)
+	if len(points) != 1 {
+		t.Fatalf("expected a single point, got %+v", points)
+	}
+}
+
+func TestTemplate_Filter(t *testing.T) {
+	cet := Zone{Name: "CET", Offset: time.Hour}
+	cest := Zone{Name: "CEST", Offset: 2 * time.Hour, IsDST: true}
+	template := Template{Zones: []Zone{cet}, Extend: "CET-1CEST,M3.5.0,M10.5.0/3"}
+	utc := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	expected := []Interval{
+		{Start: utc(2021, time.March, 28, 1), End: utc(2021, time.October, 31, 1), Zone: cest},
+		{Start: utc(2022, time.March, 27, 1), End: utc(2022, time.May, 1, 0), Zone: cest},
+	}
+	intervals := template.Filter(utc(2021, time.January, 1, 0), utc(2022, time.May, 1, 0), func(z Zone) bool {
+		return z.Offset == 2*time.Hour
+	})
+	if !reflect.DeepEqual(intervals, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, intervals)
+	}
+}
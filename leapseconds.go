@@ -0,0 +1,98 @@
+package timezones
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// LeapSecond is a leap second record of TZif data, see Template.LeapSeconds.
+type LeapSecond struct {
+	// Occurrence is the time at which the leap second occurs, in the time scale of the data:
+	// for the right/ zones of the tz database, seconds since the Unix epoch including the earlier
+	// leap seconds, e.g. 1972-06-30T23:59:60Z is stored as 78796800.
+	Occurrence time.Time
+
+	// Correction is the total number of leap seconds to apply after Occurrence, e.g. 1 for the first
+	// positive leap second.
+	Correction int
+}
+
+// minLeapSecondGap is the minimum number of seconds between two leap seconds allowed by RFC 8536,
+// 28 days minus one second.
+const minLeapSecondGap = 2419199
+
+// validateLeapSeconds checks that leap seconds can be stored in TZif data as RFC 8536 requires:
+// the first occurs at a nonnegative time and has a correction of 1 or -1, each of the next
+// occurs at least minLeapSecondGap seconds after the previous and changes the correction by one.
+func validateLeapSeconds(leapSeconds []LeapSecond) error {
+	for i, ls := range leapSeconds {
+		if !fitsUnix(ls.Occurrence) || ls.Occurrence.Unix() < 0 {
+			return &FieldError{Field: "LeapSeconds", Index: i, Err: ErrInvalidLeapSecond,
+				Detail: fmt.Sprintf("occurrence %s is out of range", formatTime(ls.Occurrence))}
+		}
+		previous := 0
+		if i > 0 {
+			prev := leapSeconds[i-1]
+			if ls.Occurrence.Unix()-prev.Occurrence.Unix() < minLeapSecondGap {
+				return &FieldError{Field: "LeapSeconds", Index: i, Err: ErrInvalidLeapSecond,
+					Detail: fmt.Sprintf("occurrence %s is less than 28 days after %s",
+						formatTime(ls.Occurrence), formatTime(prev.Occurrence))}
+			}
+			previous = prev.Correction
+		}
+		if diff := ls.Correction - previous; diff != 1 && diff != -1 {
+			return &FieldError{Field: "LeapSeconds", Index: i, Err: ErrInvalidLeapSecond,
+				Detail: fmt.Sprintf("correction %d does not differ from the previous %d by one", ls.Correction, previous)}
+		}
+	}
+	return nil
+}
+
+// checkLeapSecondsV1 checks that the occurrences of leap seconds fit into version 1 data.
+func checkLeapSecondsV1(leapSeconds []LeapSecond) error {
+	for i, ls := range leapSeconds {
+		if ls.Occurrence.Unix() > math.MaxInt32 {
+			return &FieldError{Field: "LeapSeconds", Index: i, Err: ErrInvalidLeapSecond,
+				Detail: fmt.Sprintf("%s does not fit into version 1 data", formatTime(ls.Occurrence))}
+		}
+	}
+	return nil
+}
+
+// putLeapSecondRecord writes a leap second record with occurrence of tsize bytes to buf
+// and returns the rest of buf.
+func putLeapSecondRecord(buf []byte, tsize int, ls LeapSecond) []byte {
+	if tsize == 4 {
+		binary.BigEndian.PutUint32(buf, uint32(ls.Occurrence.Unix()))
+	} else {
+		binary.BigEndian.PutUint64(buf, uint64(ls.Occurrence.Unix()))
+	}
+	binary.BigEndian.PutUint32(buf[tsize:], uint32(int32(ls.Correction)))
+	return buf[tsize+4:]
+}
+
+// parseLeapSeconds parses leap second records with occurrences of tsize bytes.
+// It returns nil if there are no records.
+func parseLeapSeconds(records []byte, tsize int) []LeapSecond {
+	n := len(records) / (tsize + 4)
+	if n == 0 {
+		return nil
+	}
+	leapSeconds := make([]LeapSecond, n)
+	for i := range leapSeconds {
+		var sec int64
+		if tsize == 4 {
+			sec = int64(int32(binary.BigEndian.Uint32(records)))
+		} else {
+			sec = int64(binary.BigEndian.Uint64(records))
+		}
+		leapSeconds[i] = LeapSecond{
+			Occurrence: time.Unix(sec, 0),
+			Correction: int(int32(binary.BigEndian.Uint32(records[tsize:]))),
+		}
+		records = records[tsize+4:]
+	}
+	return leapSeconds
+}
@@ -0,0 +1,95 @@
+package timezones
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// rightLeapSeconds are the first leap seconds of the right/ zones of the tz database.
+var rightLeapSeconds = []LeapSecond{
+	{Occurrence: time.Unix(78796800, 0), Correction: 1},
+	{Occurrence: time.Unix(94694401, 0), Correction: 2},
+	{Occurrence: time.Unix(126230402, 0), Correction: 3},
+}
+
+func TestEncoder_LeapSeconds(t *testing.T) {
+	template := Template{Zones: []Zone{{Name: "UTC"}}, Extend: "UTC0", LeapSeconds: rightLeapSeconds}
+	e := Encoder{SelfCheck: true}
+	data, err := e.Encode(template)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if size, err := e.Size(template); err != nil || size != len(data) {
+		t.Fatalf("Size returned %d, %v, encoded %d bytes", size, err, len(data))
+	}
+	info, err := QuickInfo(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.LeapCount != len(rightLeapSeconds) {
+		t.Fatalf("expected %d leap seconds, got %d", len(rightLeapSeconds), info.LeapCount)
+	}
+	record := make([]byte, 12)
+	binary.BigEndian.PutUint64(record, 78796800)
+	binary.BigEndian.PutUint32(record[8:], 1)
+	if !bytes.Contains(data, record) {
+		t.Fatalf("leap second record %x not found in %x", record, data)
+	}
+	if _, err := time.LoadLocationFromTZData("", data); err != nil {
+		t.Fatalf("Go rejected the data: %v", err)
+	}
+	decoded, err := LoadTZData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.LeapSeconds, rightLeapSeconds) {
+		t.Fatalf("expected %v, got %v", rightLeapSeconds, decoded.LeapSeconds)
+	}
+
+	v1 := Encoder{Version: 1, SelfCheck: true}
+	if _, err := v1.Encode(Template{Zones: []Zone{{Name: "UTC"}}, LeapSeconds: rightLeapSeconds}); err != nil {
+		t.Fatalf("unexpected error for version 1: %v", err)
+	}
+	late := []LeapSecond{{Occurrence: time.Date(2040, time.January, 1, 0, 0, 0, 0, time.UTC), Correction: 1}}
+	if _, err := v1.Encode(Template{Zones: []Zone{{Name: "UTC"}}, LeapSeconds: late}); !errors.Is(err, ErrInvalidLeapSecond) {
+		t.Fatalf("expected ErrInvalidLeapSecond for version 1, got %v", err)
+	}
+
+	streamed := streamTemplate(streamThreshold + 1)
+	streamed.LeapSeconds = rightLeapSeconds
+	expected, err := TZData(streamed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteTZData(&buf, streamed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Fatal("written data differs from TZData")
+	}
+}
+
+func TestEncoder_LeapSeconds_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		leapSeconds []LeapSecond
+		index       int
+	}{
+		{"negative", []LeapSecond{{Occurrence: time.Unix(-1, 0), Correction: 1}}, 0},
+		{"first correction", []LeapSecond{{Occurrence: time.Unix(78796800, 0), Correction: 2}}, 0},
+		{"correction step", []LeapSecond{rightLeapSeconds[0], {Occurrence: time.Unix(94694401, 0), Correction: 1}}, 1},
+		{"too close", []LeapSecond{rightLeapSeconds[0], {Occurrence: time.Unix(78796800+86400, 0), Correction: 2}}, 1},
+	} {
+		_, err := TZData(Template{Zones: []Zone{{Name: "UTC"}}, LeapSeconds: tc.leapSeconds})
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Err != ErrInvalidLeapSecond || fieldErr.Field != "LeapSeconds" ||
+			fieldErr.Index != tc.index {
+			t.Fatalf("%s: expected ErrInvalidLeapSecond in LeapSeconds[%d], got %v", tc.name, tc.index, err)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package timezones
+
+import (
+	"fmt"
+	"math"
+)
+
+// Limits of TZif data that the encoder enforces, see CheckFits.
+const (
+	// MaxZones is the maximum number of zones a template can have.
+	// There are max 255 possible local time type records in a TZif file.
+	// We reserve zone 0 for the first zone, which must be unused by transitions
+	// because Go's time package does not follow the RFC 8536 exactly and chooses nonzero record in
+	// some cases if zone 0 is used in transitions, see time.Location.lookupFirstZone.
+	MaxZones = 254
+
+	// MaxDesignationBytes is the maximum length of the time zone designations of TZif data, including
+	// the NUL terminating each of them, as the designation index of local time types is a single byte.
+	// Distinct zone names and names that are not suffixes of others count towards the limit, see DesignationPacker.
+	MaxDesignationBytes = math.MaxUint8
+
+	// MaxChanges is the maximum number of changes a template can have, as the count of transitions
+	// in TZif is a 32-bit number.
+	MaxChanges = math.MaxUint32
+)
+
+// CheckFits checks that the template is within the limits of TZif data: that it has at most MaxZones zones
+// and MaxChanges changes and that its designations fit into MaxDesignationBytes, as packed by TZData.
+// It returns an error wrapping ErrTooManyZones, ErrTooManyChanges or ErrDesignationsTooLong otherwise.
+//
+// Unlike TZData, CheckFits does not validate the rest of the template, so that the limits can be checked
+// early, e.g. while collecting data for the template.
+func CheckFits(template Template) error {
+	var e Encoder
+	return e.CheckFits(template)
+}
+
+// CheckFits checks that the template is within the limits of TZif data when encoded with the encoder,
+// see CheckFits.
+// The designations are packed with Designations and abbreviated with AbbreviateDesignations if set,
+// without reporting warnings.
+func (e *Encoder) CheckFits(template Template) error {
+	if len(template.Zones) > MaxZones {
+		return withTemplateName(&template, fmt.Errorf("%w: %d zones, max is %d", ErrTooManyZones,
+			len(template.Zones), MaxZones))
+	}
+	if nchanges := int64(len(template.Changes)); nchanges > MaxChanges {
+		return withTemplateName(&template, fmt.Errorf("%w: %d changes, max is %d", ErrTooManyChanges,
+			nchanges, int64(MaxChanges)))
+	}
+	prepared, _ := e.prepare(&template)
+	if _, _, err := e.layoutDesignations(prepared.Zones); err != nil {
+		return withTemplateName(prepared, err)
+	}
+	return nil
+}
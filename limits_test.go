@@ -0,0 +1,63 @@
+package timezones
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCheckFits(t *testing.T) {
+	if err := CheckFits(benchTemplate()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	manyZones := Template{Name: "Custom/Many", Zones: make([]Zone, MaxZones+1)}
+	if err := CheckFits(manyZones); !errors.Is(err, ErrTooManyZones) || !strings.HasPrefix(err.Error(), "Custom/Many: ") {
+		t.Fatalf("expected ErrTooManyZones, got %v", err)
+	}
+
+	// 100 distinct names of 4 bytes need 500 bytes.
+	longNames := Template{Zones: make([]Zone, 100)}
+	for i := range longNames.Zones {
+		longNames.Zones[i].Name = fmt.Sprintf("Z%03d", i)
+	}
+	if err := CheckFits(longNames); !errors.Is(err, ErrDesignationsTooLong) {
+		t.Fatalf("expected ErrDesignationsTooLong, got %v", err)
+	}
+	if _, err := TZData(longNames); !errors.Is(err, ErrDesignationsTooLong) {
+		t.Fatalf("expected TZData to agree, got %v", err)
+	}
+	var warnings []error
+	e := Encoder{AbbreviateDesignations: true, Warn: func(err error) { warnings = append(warnings, err) }}
+	if err := e.CheckFits(longNames); err != nil {
+		t.Fatalf("unexpected error with AbbreviateDesignations: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings %v", warnings)
+	}
+
+	// The limits are checked even if the template is otherwise invalid.
+	invalid := Template{Zones: []Zone{{Name: "A"}}, Changes: []Change{{ZoneIndex: 5}}}
+	if err := CheckFits(invalid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCheckFits_Designations(t *testing.T) {
+	// Exactly MaxDesignationBytes: the synthetic first zone shares the designation of zone 0.
+	template := Template{Zones: make([]Zone, 51)}
+	for i := range template.Zones {
+		template.Zones[i].Name = fmt.Sprintf("Z%03d", i)
+	}
+	if err := CheckFits(template); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := TZData(template); err != nil {
+		t.Fatalf("expected TZData to agree, got %v", err)
+	}
+	template.Zones = append(template.Zones, Zone{Name: "Z051"})
+	if err := CheckFits(template); !errors.Is(err, ErrDesignationsTooLong) {
+		t.Fatalf("expected ErrDesignationsTooLong, got %v", err)
+	}
+}
@@ -0,0 +1,23 @@
+package timezones
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// LMTTemplate returns a template of local mean time at the given longitude, in degrees east of Greenwich,
+// named name.
+// Its single zone LMT is offset from UTC by 4 minutes per degree, rounded to whole seconds,
+// the same way tzdata describes the time before standard time was adopted.
+// It returns ErrInvalidOffset if longitude is not between -180 and 180.
+func LMTTemplate(longitude float64, name string) (Template, error) {
+	if !(longitude >= -180 && longitude <= 180) {
+		return Template{}, fmt.Errorf("%w: longitude %v is not between -180 and 180", ErrInvalidOffset, longitude)
+	}
+	offset := time.Duration(math.Round(longitude*240)) * time.Second
+	return Template{
+		Name:  name,
+		Zones: []Zone{{Name: "LMT", Offset: offset}},
+	}, nil
+}
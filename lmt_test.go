@@ -0,0 +1,41 @@
+package timezones
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLMTTemplate(t *testing.T) {
+	for _, tc := range []struct {
+		longitude float64
+		expected  time.Duration
+	}{
+		{0, 0},
+		// Prague, 14°26'E, tzdata uses 0:57:44.
+		{14 + 26.0/60, 57*time.Minute + 44*time.Second},
+		{-74.0060, -(4*time.Hour + 56*time.Minute + 1*time.Second)},
+		{180, 12 * time.Hour},
+		{-180, -12 * time.Hour},
+	} {
+		template, err := LMTTemplate(tc.longitude, "Test/LMT")
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", tc.longitude, err)
+		}
+		if len(template.Zones) != 1 || template.Zones[0].Name != "LMT" || template.Zones[0].Offset != tc.expected {
+			t.Fatalf("%v: expected LMT %v, got %+v", tc.longitude, tc.expected, template.Zones)
+		}
+		if template.Name != "Test/LMT" {
+			t.Fatalf("%v: expected name Test/LMT, got %q", tc.longitude, template.Name)
+		}
+		if _, err := NewLocation(template); err != nil {
+			t.Fatalf("%v: unexpected error: %v", tc.longitude, err)
+		}
+	}
+	for _, longitude := range []float64{-180.5, 181, math.NaN(), math.Inf(1)} {
+		if _, err := LMTTemplate(longitude, ""); !errors.Is(err, ErrInvalidOffset) {
+			t.Fatalf("%v: expected ErrInvalidOffset, got %v", longitude, err)
+		}
+	}
+}
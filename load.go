@@ -0,0 +1,189 @@
+package timezones
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// LoadOptions configures LoadAll.
+type LoadOptions struct {
+	// Parallelism is the maximum number of files parsed concurrently.
+	// If zero or negative, runtime.GOMAXPROCS(0) is used.
+	Parallelism int
+
+	// MemoryLimit caps the approximate memory used by the loaded templates, in bytes.
+	// When loading another file would exceed the limit, LoadAll stops loading files and returns
+	// the templates loaded so far along with ErrMemoryLimit.
+	// The memory used temporarily while reading and parsing the files is not counted.
+	// If zero or negative, there is no limit.
+	MemoryLimit int64
+
+	// Decoder configures how the files are decoded, each with its own copy.
+	// If nil, GoCompatible.Decoder() is used, which decodes files of system zoneinfo directories
+	// even though zic writes their indicators as 0.
+	Decoder *Decoder
+}
+
+// LoadAll loads all TZif files from fsys.
+//
+// The returned map is keyed by the slash-separated path of the file within fsys, e.g. "Europe/Bratislava",
+// which is also the Name of the template.
+// Files that don't start with the TZif magic are skipped, so that a zoneinfo directory
+// along with its zone.tab, tzdata.zi and similar files can be loaded directly.
+// Symbolic links to directories are not followed.
+// The metadata sidecar of each file is loaded into Template.Metadata, see MetadataSuffix.
+// A *zip.Reader can be used as fsys to load a zipped tree like Go's lib/time/zoneinfo.zip.
+//
+// Files are parsed concurrently, see LoadOptions.Parallelism.
+// If any file fails to load, LoadAll returns an error naming that file.
+// If LoadOptions.MemoryLimit is exceeded, LoadAll returns a partial result along with an error wrapping ErrMemoryLimit.
+func LoadAll(fsys fs.FS, options LoadOptions) (map[string]*Template, error) {
+	var paths []string
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			// Symbolic links to directories, such as the posix link to the root of system
+			// zoneinfo directories, would only load the same files again.
+			if fi, err := fs.Stat(fsys, path); err == nil && fi.IsDir() {
+				return nil
+			}
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	parallelism := options.Parallelism
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+	if parallelism > len(paths) {
+		parallelism = len(paths)
+	}
+
+	templates := make([]*Template, len(paths))
+	errs := make([]error, len(paths))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var memoryUsed int64
+	var memoryExceeded int32
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if atomic.LoadInt32(&memoryExceeded) != 0 {
+					continue
+				}
+				templates[i], errs[i] = loadFile(fsys, paths[i], options.Decoder)
+				if templates[i] == nil || options.MemoryLimit <= 0 {
+					continue
+				}
+				size := templateSize(templates[i])
+				if atomic.AddInt64(&memoryUsed, size) > options.MemoryLimit {
+					atomic.AddInt64(&memoryUsed, -size)
+					templates[i] = nil
+					atomic.StoreInt32(&memoryExceeded, 1)
+				}
+			}
+		}()
+	}
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	result := make(map[string]*Template, len(paths))
+	for i := range paths {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("%s: %w", paths[i], errs[i])
+		}
+		if templates[i] != nil {
+			result[paths[i]] = templates[i]
+		}
+	}
+	if memoryExceeded != 0 {
+		return result, fmt.Errorf("loaded %d templates using approx. %d bytes: %w", len(result), memoryUsed, ErrMemoryLimit)
+	}
+	return result, nil
+}
+
+// LoadFile loads the TZif file name from fsys and names the template name.
+// Like LoadAll with the default options, it decodes the file with GoCompatible.Decoder().
+// Its metadata sidecar, if any, is loaded into Template.Metadata.
+// Like LoadAll, it works with any fs.FS: a directory opened by os.DirFS, a *zip.Reader,
+// data embedded with embed.FS or test fixtures in fstest.MapFS.
+func LoadFile(fsys fs.FS, name string) (*Template, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	d := GoCompatible.Decoder()
+	template, err := d.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	template.Name = name
+	if template.Metadata, err = loadMetadata(fsys, name); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// templateSize returns the approximate memory used by the template.
+func templateSize(t *Template) int64 {
+	size := int64(unsafe.Sizeof(*t)) + int64(len(t.Name)) + int64(len(t.Extend)) +
+		int64(cap(t.Zones))*int64(unsafe.Sizeof(Zone{})) +
+		int64(cap(t.Changes))*int64(unsafe.Sizeof(Change{})) +
+		int64(cap(t.LeapSeconds))*int64(unsafe.Sizeof(LeapSecond{}))
+	for i := range t.Zones {
+		size += int64(len(t.Zones[i].Name))
+	}
+	if m := t.Metadata; m != nil {
+		size += int64(unsafe.Sizeof(*m)) + int64(len(m.Source)) + int64(len(m.ReviewStatus)) +
+			int64(cap(m.Comments))*int64(unsafe.Sizeof(""))
+		for _, c := range m.Comments {
+			size += int64(len(c))
+		}
+	}
+	return size
+}
+
+// loadFile loads a single TZif file with a copy of decoder, or GoCompatible.Decoder() if decoder is nil.
+// It returns nil template and nil error if the file is not a TZif file.
+func loadFile(fsys fs.FS, path string, decoder *Decoder) (*Template, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte("TZif")) {
+		return nil, nil
+	}
+	// A fresh Decoder for each file, as the decoded template uses the memory of the Decoder.
+	d := GoCompatible.Decoder()
+	if decoder != nil {
+		d = *decoder
+	}
+	template, err := d.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	template.Name = path
+	if template.Metadata, err = loadMetadata(fsys, path); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
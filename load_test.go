@@ -0,0 +1,258 @@
+package timezones
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func testFS(t testing.TB) fstest.MapFS {
+	fixed, err := TZData(Template{
+		Zones: []Zone{{Name: "MyFixed", Offset: 2*time.Hour + 23*time.Minute}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := TZData(benchTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fstest.MapFS{
+		"Etc/MyFixed":  {Data: fixed},
+		"Custom/Bench": {Data: changes},
+		"zone.tab":     {Data: []byte("# not a TZif file\n")},
+	}
+}
+
+func TestLoadAll(t *testing.T) {
+	fsys := testFS(t)
+	for _, parallelism := range []int{0, 1, 3} {
+		templates, err := LoadAll(fsys, LoadOptions{Parallelism: parallelism})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(templates) != 2 {
+			t.Fatalf("expected 2 templates, got %d", len(templates))
+		}
+		for _, name := range []string{"Etc/MyFixed", "Custom/Bench"} {
+			expected, err := LoadTZDataNamed(name, fsys[name].Data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(templates[name], expected) {
+				t.Fatalf("unexpected template for %s: %+v", name, templates[name])
+			}
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	fsys := testFS(t)
+	template, err := LoadFile(fsys, "Etc/MyFixed")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected, err := LoadTZDataNamed("Etc/MyFixed", fsys["Etc/MyFixed"].Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(template, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, template)
+	}
+	if _, err := LoadFile(fsys, "zone.tab"); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid, got %v", err)
+	}
+	if _, err := LoadFile(fsys, "Etc/Missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected fs.ErrNotExist, got %v", err)
+	}
+}
+
+func TestLoadAll_Zip(t *testing.T) {
+	fsys := testFS(t)
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, file := range fsys {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(file.Data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	templates, err := LoadAll(zr, LoadOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(templates) != 2 || templates["Etc/MyFixed"] == nil || templates["Custom/Bench"] == nil {
+		t.Fatalf("unexpected templates: %v", templates)
+	}
+}
+
+func TestLoadAll_Error(t *testing.T) {
+	fsys := testFS(t)
+	fsys["Broken/Zone"] = &fstest.MapFile{Data: []byte("TZif2")}
+	_, err := LoadAll(fsys, LoadOptions{})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid, got %v", err)
+	}
+}
+
+func TestLoadAll_Indicators(t *testing.T) {
+	fsys := testFS(t)
+	// Like zic, which writes 0 indicators for most zones.
+	wall := append([]byte(nil), fsys["Custom/Bench"].Data...)
+	footer := bytes.LastIndexByte(wall[:len(wall)-1], '\n')
+	fill(wall[footer-2*len(benchTemplate().Changes):footer], 0)
+	if _, err := LoadTZData(wall); !errors.Is(err, ErrUnsupportedIndicators) {
+		t.Fatalf("expected ErrUnsupportedIndicators, got %v", err)
+	}
+	fsys["Custom/Wall"] = &fstest.MapFile{Data: wall}
+	templates, err := LoadAll(fsys, LoadOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loaded := *templates["Custom/Wall"]
+	loaded.Name = "Custom/Bench"
+	if !reflect.DeepEqual(&loaded, templates["Custom/Bench"]) {
+		t.Fatalf("unexpected template: %+v", templates["Custom/Wall"])
+	}
+	if template, err := LoadFile(fsys, "Custom/Wall"); err != nil || !reflect.DeepEqual(template, templates["Custom/Wall"]) {
+		t.Fatalf("unexpected LoadFile result: %+v, %v", template, err)
+	}
+	if _, err := LoadAll(fsys, LoadOptions{Decoder: &Decoder{}}); !errors.Is(err, ErrUnsupportedIndicators) {
+		t.Fatalf("expected ErrUnsupportedIndicators, got %v", err)
+	}
+}
+
+func TestLoadAll_DirSymlink(t *testing.T) {
+	dir := t.TempDir()
+	data, err := TZData(benchTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "Custom"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Custom", "Bench"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Like the posix link of system zoneinfo directories.
+	if err := os.Symlink(".", filepath.Join(dir, "posix")); err != nil {
+		t.Skipf("symbolic links not supported: %v", err)
+	}
+	templates, err := LoadAll(os.DirFS(dir), LoadOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(templates) != 1 || templates["Custom/Bench"] == nil {
+		t.Fatalf("unexpected templates: %v", templates)
+	}
+}
+
+func TestLoadAll_SystemZoneinfo(t *testing.T) {
+	const dir = "/usr/share/zoneinfo"
+	if _, err := os.Stat(filepath.Join(dir, "Europe", "Berlin")); err != nil {
+		t.Skipf("system zoneinfo not available: %v", err)
+	}
+	templates, err := LoadAll(os.DirFS(dir), LoadOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if templates["Europe/Berlin"] == nil {
+		t.Fatalf("Europe/Berlin not loaded from %d templates", len(templates))
+	}
+}
+
+func TestLoadAll_GoZoneinfo(t *testing.T) {
+	zr, err := zip.OpenReader(filepath.Join(runtime.GOROOT(), "lib", "time", "zoneinfo.zip"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			t.Skip("zoneinfo.zip not available")
+		}
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	templates, err := LoadAll(zr, LoadOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if templates["Europe/Bratislava"] == nil {
+		t.Fatal("expected Europe/Bratislava to be loaded")
+	}
+}
+
+var benchLoadAll map[string]*Template
+
+func BenchmarkLoadAll(b *testing.B) {
+	zr, err := zip.OpenReader(filepath.Join(runtime.GOROOT(), "lib", "time", "zoneinfo.zip"))
+	if err != nil {
+		b.Skip(err)
+	}
+	defer zr.Close()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		templates, err := LoadAll(zr, LoadOptions{})
+		if err != nil {
+			b.Fatal(err)
+		}
+		benchLoadAll = templates
+	}
+}
+
+func TestLoadAll_MemoryLimit(t *testing.T) {
+	fsys := testFS(t)
+	all, err := LoadAll(fsys, LoadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixedSize := templateSize(all["Etc/MyFixed"])
+	benchSize := templateSize(all["Custom/Bench"])
+	if fixedSize >= benchSize {
+		t.Fatalf("expected fixed template to be smaller, got %d and %d", fixedSize, benchSize)
+	}
+
+	templates, err := LoadAll(fsys, LoadOptions{MemoryLimit: fixedSize + benchSize})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(templates) != 2 {
+		t.Fatalf("expected 2 templates, got %d", len(templates))
+	}
+
+	templates, err = LoadAll(fsys, LoadOptions{Parallelism: 1, MemoryLimit: benchSize + fixedSize - 1})
+	if !errors.Is(err, ErrMemoryLimit) {
+		t.Fatalf("expected ErrMemoryLimit, got %v", err)
+	}
+	// Files are loaded in lexical order, so Custom/Bench is loaded first.
+	if len(templates) != 1 || templates["Custom/Bench"] == nil {
+		t.Fatalf("expected partial result with Custom/Bench, got %v", templates)
+	}
+
+	templates, err = LoadAll(fsys, LoadOptions{MemoryLimit: 1})
+	if !errors.Is(err, ErrMemoryLimit) {
+		t.Fatalf("expected ErrMemoryLimit, got %v", err)
+	}
+	if len(templates) != 0 {
+		t.Fatalf("expected empty result, got %v", templates)
+	}
+}
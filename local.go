@@ -0,0 +1,250 @@
+package timezones
+
+import (
+	"fmt"
+	"time"
+)
+
+// ResolvePolicy tells ResolveLocal how to handle local times that occur more than once or not at all.
+type ResolvePolicy int
+
+const (
+	// ResolveEarliest uses the earlier of the instants of an ambiguous local time.
+	// A skipped local time is interpreted with the offset in effect after the gap,
+	// which gives an instant before the gap, like adjusting the time back by the length of the gap.
+	ResolveEarliest ResolvePolicy = iota
+
+	// ResolveLatest uses the later of the instants of an ambiguous local time.
+	// A skipped local time is interpreted with the offset in effect before the gap,
+	// which gives an instant after the gap, like adjusting the time forward by the length of the gap.
+	ResolveLatest
+
+	// ResolveStrict returns ErrAmbiguousTime for ambiguous local times and ErrNonexistentTime for
+	// skipped local times.
+	ResolveStrict
+
+	// ResolveShiftForward uses the earlier of the instants of an ambiguous local time, and the instant
+	// the gap ends for a skipped local time, i.e. the first local time after the gap.
+	ResolveShiftForward
+)
+
+// ResolveLocal returns the instant when the local wall-clock time in the zone described by the template
+// is year-month-day hour:min:sec, handling the local times around changes according to policy.
+// Values outside the usual ranges are normalized the same way as by time.Date.
+//
+// Unlike time.Date, which silently picks one of the instants, ResolveLocal lets the caller decide what
+// happens when clocks are set back and a local time occurs twice, or set forward and a local time does not
+// occur at all.
+// The template is interpreted the same way as by NewLocation; it returns the same error as Validate if
+// the template is not valid.
+// The result is in UTC.
+func ResolveLocal(year int, month time.Month, day, hour, min, sec int, template Template, policy ResolvePolicy) (time.Time, error) {
+	if err := Validate(template); err != nil {
+		return time.Time{}, err
+	}
+	wall := time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+	r := newZoneLookup(&template).resolveLocal(wall.Unix())
+	u, err := r.pick(wall, policy)
+	if err != nil {
+		return time.Time{}, withTemplateName(&template, err)
+	}
+	return time.Unix(u, 0).UTC(), nil
+}
+
+// IsAmbiguous reports whether the local wall-clock time year-month-day hour:min:sec occurs more than once
+// in the zone described by the template, usually because clocks are set back.
+// If so, earlier and later are the first and the last instant at which it occurs, in UTC.
+// Values outside the usual ranges are normalized the same way as by time.Date.
+// It returns the same error as Validate if the template is not valid.
+func IsAmbiguous(year int, month time.Month, day, hour, min, sec int, template Template) (earlier, later time.Time, ambiguous bool, err error) {
+	if err := Validate(template); err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+	wall := time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+	r := newZoneLookup(&template).resolveLocal(wall.Unix())
+	if len(r.instants) < 2 {
+		return time.Time{}, time.Time{}, false, nil
+	}
+	return time.Unix(r.instants[0], 0).UTC(), time.Unix(r.instants[len(r.instants)-1], 0).UTC(), true, nil
+}
+
+// IsSkipped reports whether the local wall-clock time year-month-day hour:min:sec does not occur
+// in the zone described by the template, usually because clocks are set forward.
+// If so, change is the instant of the change that skips it, in UTC.
+// Values outside the usual ranges are normalized the same way as by time.Date.
+// It returns the same error as Validate if the template is not valid.
+func IsSkipped(year int, month time.Month, day, hour, min, sec int, template Template) (change time.Time, skipped bool, err error) {
+	if err := Validate(template); err != nil {
+		return time.Time{}, false, err
+	}
+	wall := time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+	r := newZoneLookup(&template).resolveLocal(wall.Unix())
+	if len(r.instants) > 0 {
+		return time.Time{}, false, nil
+	}
+	return time.Unix(r.transition, 0).UTC(), true, nil
+}
+
+// localResolution describes the instants at which a local time occurs.
+type localResolution struct {
+	// instants are the Unix times at which the local time occurs, in increasing order.
+	instants []int64
+
+	// If instants is empty, the local time is in a gap created by the change at transition
+	// from zone before to zone after.
+	transition    int64
+	before, after Zone
+}
+
+// pick selects the Unix time of the local time wall according to policy, see ResolveLocal.
+func (r *localResolution) pick(wall time.Time, policy ResolvePolicy) (int64, error) {
+	switch {
+	case len(r.instants) == 1:
+		return r.instants[0], nil
+	case len(r.instants) > 1:
+		switch policy {
+		case ResolveLatest:
+			return r.instants[len(r.instants)-1], nil
+		case ResolveStrict:
+			return 0, fmt.Errorf("%w: %s occurs %d times, first at %s", ErrAmbiguousTime,
+				wall.Format("2006-01-02T15:04:05"), len(r.instants), formatTime(time.Unix(r.instants[0], 0)))
+		default:
+			return r.instants[0], nil
+		}
+	}
+	switch policy {
+	case ResolveEarliest:
+		return wall.Unix() - offsetSeconds(r.after), nil
+	case ResolveLatest:
+		return wall.Unix() - offsetSeconds(r.before), nil
+	case ResolveShiftForward:
+		return r.transition, nil
+	default:
+		return 0, fmt.Errorf("%w: %s is skipped by the change at %s", ErrNonexistentTime,
+			wall.Format("2006-01-02T15:04:05"), formatTime(time.Unix(r.transition, 0)))
+	}
+}
+
+// resolveLocal finds the instants at which the local time wall, in seconds since the Unix epoch
+// as if it was UTC, occurs.
+func (zl *zoneLookup) resolveLocal(wall int64) localResolution {
+	minOffset, maxOffset := zl.offsetRange()
+	var r localResolution
+	// A local time can only occur at instants between wall-maxOffset and wall-minOffset.
+	// A gap containing wall must be created by a change in the same interval.
+	var prev Zone
+	end := wall - minOffset + 1
+	for sec := wall - maxOffset; sec < end; {
+		zone, start, next := zl.lookup(sec)
+		offset := offsetSeconds(zone)
+		if sec != wall-maxOffset && start == sec {
+			if prevOffset := offsetSeconds(prev); prevOffset < offset && start+prevOffset <= wall && wall < start+offset {
+				r.transition, r.before, r.after = start, prev, zone
+			}
+		}
+		if u := wall - offset; start <= u && u < next {
+			r.instants = append(r.instants, u)
+		}
+		prev = zone
+		sec = next
+	}
+	return r
+}
+
+// offsetRange returns the minimum and maximum offset of the zones the template can use, in seconds.
+func (zl *zoneLookup) offsetRange() (min, max int64) {
+	zones := zl.t.Zones
+	if zl.hasRule {
+		zones = append(zones[:len(zones):len(zones)], zl.rule.std)
+		if zl.rule.hasDST {
+			zones = append(zones, zl.rule.dst)
+		}
+	}
+	for i, zone := range zones {
+		offset := offsetSeconds(zone)
+		if i == 0 || offset < min {
+			min = offset
+		}
+		if i == 0 || offset > max {
+			max = offset
+		}
+	}
+	return min, max
+}
+
+// offsetSeconds returns the offset of the zone in whole seconds, the same way as it is encoded in TZif.
+func offsetSeconds(zone Zone) int64 {
+	return int64(OffsetOf(zone))
+}
+
+// ToLocal returns the local wall-clock time of t in the zone described by the template along with a fold,
+// like the fold attribute of Python's datetime, see PEP 495.
+// The fold is 0 unless the local time occurs more than once and t is one of the later occurrences,
+// in which case it is 1. FromLocal converts the result back to t.
+// The result is in a fixed zone with the name and offset of the zone in effect at t.
+// It returns the same error as Validate if the template is not valid.
+func ToLocal(t time.Time, template Template) (local time.Time, fold int, err error) {
+	if err := Validate(template); err != nil {
+		return time.Time{}, 0, err
+	}
+	zl := newZoneLookup(&template)
+	zone, _, _ := zl.lookup(t.Unix())
+	offset := offsetSeconds(zone)
+	r := zl.resolveLocal(t.Unix() + offset)
+	if len(r.instants) > 1 && r.instants[0] != t.Unix() {
+		fold = 1
+	}
+	return t.In(time.FixedZone(zone.Name, int(offset))), fold, nil
+}
+
+// FromLocal returns the instant when the local wall-clock time in the zone described by the template is
+// the date and clock of local, disambiguated by fold, like Python's datetime does, see PEP 495.
+// The location of local is ignored.
+//
+// If the local time occurs more than once, fold 0 selects the earlier and fold 1 the later instant.
+// If the local time is skipped, fold 0 interprets it with the offset in effect before the gap and
+// fold 1 with the offset in effect after the gap, the same as ResolveLatest and ResolveEarliest, respectively.
+// The result is in UTC.
+// It returns the same error as Validate if the template is not valid.
+func FromLocal(local time.Time, fold int, template Template) (time.Time, error) {
+	if err := Validate(template); err != nil {
+		return time.Time{}, err
+	}
+	year, month, day := local.Date()
+	hour, min, sec := local.Clock()
+	wall := time.Date(year, month, day, hour, min, sec, 0, time.UTC).Unix()
+	r := newZoneLookup(&template).resolveLocal(wall)
+	var u int64
+	switch {
+	case len(r.instants) == 0 && fold == 0:
+		u = wall - offsetSeconds(r.before)
+	case len(r.instants) == 0:
+		u = wall - offsetSeconds(r.after)
+	case fold == 0:
+		u = r.instants[0]
+	default:
+		u = r.instants[len(r.instants)-1]
+	}
+	return time.Unix(u, int64(local.Nanosecond())).UTC(), nil
+}
+
+// MoveWallClock returns the instant at which the local wall-clock time in the zone described by to reads
+// the same as the local time of t in the zone described by from, for example to move a meeting at 09:00
+// in one office to 09:00 in another office.
+// Local times that occur more than once or not at all in to are handled according to policy,
+// see ResolveLocal.
+// The result is in UTC.
+// It returns the same error as Validate if any of the templates is not valid.
+func MoveWallClock(t time.Time, from, to Template, policy ResolvePolicy) (time.Time, error) {
+	local, _, err := ToLocal(t, from)
+	if err != nil {
+		return time.Time{}, err
+	}
+	year, month, day := local.Date()
+	hour, min, sec := local.Clock()
+	moved, err := ResolveLocal(year, month, day, hour, min, sec, to, policy)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return moved.Add(time.Duration(t.Nanosecond())), nil
+}
@@ -0,0 +1,192 @@
+package timezones
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResolveLocal(t *testing.T) {
+	template := Template{Name: "Europe/Test", Extend: "CET-1CEST,M3.5.0,M10.5.0/3"}
+	utc := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2022, month, day, hour, min, 0, 0, time.UTC)
+	}
+	for _, tc := range []struct {
+		name     string
+		month    time.Month
+		day      int
+		hour     int
+		policy   ResolvePolicy
+		expected time.Time
+		err      error
+	}{
+		{"winter", time.January, 10, 12, ResolveStrict, utc(time.January, 10, 11, 30), nil},
+		{"summer", time.July, 10, 12, ResolveStrict, utc(time.July, 10, 10, 30), nil},
+		{"gap earliest", time.March, 27, 2, ResolveEarliest, utc(time.March, 27, 0, 30), nil},
+		{"gap latest", time.March, 27, 2, ResolveLatest, utc(time.March, 27, 1, 30), nil},
+		{"gap strict", time.March, 27, 2, ResolveStrict, time.Time{}, ErrNonexistentTime},
+		{"gap shift forward", time.March, 27, 2, ResolveShiftForward, utc(time.March, 27, 1, 0), nil},
+		{"fold earliest", time.October, 30, 2, ResolveEarliest, utc(time.October, 30, 0, 30), nil},
+		{"fold latest", time.October, 30, 2, ResolveLatest, utc(time.October, 30, 1, 30), nil},
+		{"fold strict", time.October, 30, 2, ResolveStrict, time.Time{}, ErrAmbiguousTime},
+		{"fold shift forward", time.October, 30, 2, ResolveShiftForward, utc(time.October, 30, 0, 30), nil},
+	} {
+		got, err := ResolveLocal(2022, tc.month, tc.day, tc.hour, 30, 0, template, tc.policy)
+		if !errors.Is(err, tc.err) {
+			t.Fatalf("%s: expected error %v, got %v", tc.name, tc.err, err)
+		}
+		if !got.Equal(tc.expected) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+
+	_, err := ResolveLocal(2022, time.March, 27, 2, 30, 0, template, ResolveStrict)
+	expected := "Europe/Test: timezones: nonexistent local time: 2022-03-27T02:30:00 is skipped by the change at " +
+		"2022-03-27T01:00:00Z"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected %q, got %v", expected, err)
+	}
+
+	if _, err := ResolveLocal(2022, time.March, 27, 2, 30, 0, Template{}, ResolveStrict); !errors.Is(err, ErrNoZones) {
+		t.Fatalf("expected ErrNoZones, got %v", err)
+	}
+}
+
+func TestResolveLocal_RoundTrip(t *testing.T) {
+	template := benchTemplate()
+	loc, err := NewLocation(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every instant must be one of the instants its local time resolves to.
+	for u := time.Date(1979, time.December, 1, 0, 0, 0, 0, time.UTC); u.Year() < 1983; u = u.Add(17 * time.Minute) {
+		local := u.In(loc)
+		earliest, err := ResolveLocal(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(),
+			local.Second(), template, ResolveEarliest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		latest, err := ResolveLocal(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(),
+			local.Second(), template, ResolveLatest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !earliest.Equal(u) && !latest.Equal(u) {
+			t.Fatalf("%v: local time %v resolves to %v and %v", u, local, earliest, latest)
+		}
+	}
+}
+
+func TestIsAmbiguous(t *testing.T) {
+	template := Template{Extend: "CET-1CEST,M3.5.0,M10.5.0/3"}
+	earlier, later, ambiguous, err := IsAmbiguous(2022, time.October, 30, 2, 0, 0, template)
+	if err != nil || !ambiguous {
+		t.Fatalf("expected ambiguous, got %v %v", ambiguous, err)
+	}
+	if expected := time.Date(2022, time.October, 30, 0, 0, 0, 0, time.UTC); !earlier.Equal(expected) {
+		t.Fatalf("expected earlier %v, got %v", expected, earlier)
+	}
+	if expected := time.Date(2022, time.October, 30, 1, 0, 0, 0, time.UTC); !later.Equal(expected) {
+		t.Fatalf("expected later %v, got %v", expected, later)
+	}
+	for _, hour := range []int{1, 3} {
+		if _, _, ambiguous, err := IsAmbiguous(2022, time.October, 30, hour, 0, 0, template); err != nil || ambiguous {
+			t.Fatalf("%d:00: expected not ambiguous, got %v %v", hour, ambiguous, err)
+		}
+	}
+	// A skipped time is not ambiguous.
+	if _, _, ambiguous, err := IsAmbiguous(2022, time.March, 27, 2, 0, 0, template); err != nil || ambiguous {
+		t.Fatalf("expected not ambiguous, got %v %v", ambiguous, err)
+	}
+}
+
+func TestIsSkipped(t *testing.T) {
+	template := Template{Extend: "CET-1CEST,M3.5.0,M10.5.0/3"}
+	for _, min := range []int{0, 59} {
+		change, skipped, err := IsSkipped(2022, time.March, 27, 2, min, 0, template)
+		if err != nil || !skipped {
+			t.Fatalf("02:%02d: expected skipped, got %v %v", min, skipped, err)
+		}
+		if expected := time.Date(2022, time.March, 27, 1, 0, 0, 0, time.UTC); !change.Equal(expected) {
+			t.Fatalf("02:%02d: expected change at %v, got %v", min, expected, change)
+		}
+	}
+	for _, hour := range []int{1, 3} {
+		if _, skipped, err := IsSkipped(2022, time.March, 27, hour, 0, 0, template); err != nil || skipped {
+			t.Fatalf("%d:00: expected not skipped, got %v %v", hour, skipped, err)
+		}
+	}
+	if _, _, err := IsSkipped(2022, time.March, 27, 2, 0, 0, Template{}); !errors.Is(err, ErrNoZones) {
+		t.Fatalf("expected ErrNoZones, got %v", err)
+	}
+}
+
+func TestToLocal_FromLocal(t *testing.T) {
+	template := Template{Extend: "CET-1CEST,M3.5.0,M10.5.0/3"}
+	for _, tc := range []struct {
+		utc      time.Time
+		expected string
+		fold     int
+	}{
+		{time.Date(2022, time.October, 30, 0, 30, 0, 5, time.UTC), "2022-10-30T02:30:00.000000005+02:00 CEST", 0},
+		{time.Date(2022, time.October, 30, 1, 30, 0, 5, time.UTC), "2022-10-30T02:30:00.000000005+01:00 CET", 1},
+		{time.Date(2022, time.October, 30, 2, 30, 0, 0, time.UTC), "2022-10-30T03:30:00+01:00 CET", 0},
+		{time.Date(2022, time.March, 27, 1, 0, 0, 0, time.UTC), "2022-03-27T03:00:00+02:00 CEST", 0},
+	} {
+		local, fold, err := ToLocal(tc.utc, template)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", tc.utc, err)
+		}
+		if s := local.Format("2006-01-02T15:04:05.999999999Z07:00 MST"); s != tc.expected || fold != tc.fold {
+			t.Fatalf("%v: expected %s fold %d, got %s fold %d", tc.utc, tc.expected, tc.fold, s, fold)
+		}
+		// The location of local does not matter.
+		wall := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(),
+			local.Nanosecond(), time.UTC)
+		back, err := FromLocal(wall, fold, template)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", tc.utc, err)
+		}
+		if !back.Equal(tc.utc) {
+			t.Fatalf("%v: round trip returned %v", tc.utc, back)
+		}
+	}
+
+	// Skipped times, fold 0 uses the offset before the gap.
+	gap := time.Date(2022, time.March, 27, 2, 30, 0, 0, time.UTC)
+	for fold, expected := range []time.Time{
+		time.Date(2022, time.March, 27, 1, 30, 0, 0, time.UTC),
+		time.Date(2022, time.March, 27, 0, 30, 0, 0, time.UTC),
+	} {
+		got, err := FromLocal(gap, fold, template)
+		if err != nil || !got.Equal(expected) {
+			t.Fatalf("fold %d: expected %v, got %v %v", fold, expected, got, err)
+		}
+	}
+}
+
+func TestMoveWallClock(t *testing.T) {
+	prague := Template{Name: "Europe/Prague", Extend: "CET-1CEST,M3.5.0,M10.5.0/3"}
+	newYork := Template{Name: "America/New_York", Extend: "EST5EDT,M3.2.0,M11.1.0"}
+
+	// 09:00 in New York is 09:00 in Prague.
+	meeting := time.Date(2022, time.July, 1, 13, 0, 0, 0, time.UTC)
+	moved, err := MoveWallClock(meeting, newYork, prague, ResolveStrict)
+	if expected := time.Date(2022, time.July, 1, 7, 0, 0, 0, time.UTC); err != nil || !moved.Equal(expected) {
+		t.Fatalf("expected %v, got %v %v", expected, moved, err)
+	}
+
+	// 02:30 on 27 March 2022 is skipped in Prague.
+	meeting = time.Date(2022, time.March, 27, 6, 30, 0, 0, time.UTC)
+	if _, err := MoveWallClock(meeting, newYork, prague, ResolveStrict); !errors.Is(err, ErrNonexistentTime) {
+		t.Fatalf("expected ErrNonexistentTime, got %v", err)
+	}
+	moved, err = MoveWallClock(meeting, newYork, prague, ResolveShiftForward)
+	if expected := time.Date(2022, time.March, 27, 1, 0, 0, 0, time.UTC); err != nil || !moved.Equal(expected) {
+		t.Fatalf("expected %v, got %v %v", expected, moved, err)
+	}
+
+	if _, err := MoveWallClock(meeting, Template{}, prague, ResolveStrict); !errors.Is(err, ErrNoZones) {
+		t.Fatalf("expected ErrNoZones, got %v", err)
+	}
+}
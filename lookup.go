@@ -0,0 +1,73 @@
+package timezones
+
+import (
+	"sort"
+	"time"
+)
+
+// zoneLookup finds the zone of a template in effect at a given time,
+// interpreting the template the same way as Go's time package interprets the data built by TZData.
+type zoneLookup struct {
+	t *Template
+	// rule is the parsed Extend, valid only if hasRule is true.
+	rule    tzRule
+	hasRule bool
+}
+
+func newZoneLookup(t *Template) *zoneLookup {
+	zl := &zoneLookup{t: t}
+	if t.Extend != "" {
+		rule, err := cachedTZRule(t.Extend)
+		// Like Go, ignore an invalid extend string.
+		if err == nil {
+			zl.rule = rule
+			zl.hasRule = true
+		}
+	}
+	return zl
+}
+
+// lookup returns the zone in effect at sec (Unix time) along with the interval [start, end) during which
+// the zone is known to be in effect.
+// The interval may end before the zone actually changes, see tzRule.lookup.
+func (zl *zoneLookup) lookup(sec int64) (zone Zone, start, end int64) {
+	changes := zl.t.Changes
+	if len(changes) == 0 {
+		if zl.hasRule {
+			return zl.rule.lookup(sec)
+		}
+		return zl.zone(zl.t.FirstZoneIndex), alpha, omega
+	}
+	// i is the index of the first change after sec.
+	i := sort.Search(len(changes), func(i int) bool {
+		return changes[i].Start.Unix() > sec
+	})
+	if i == 0 {
+		return zl.zone(zl.t.FirstZoneIndex), alpha, changes[0].Start.Unix()
+	}
+	start = changes[i-1].Start.Unix()
+	if i < len(changes) {
+		return zl.zone(changes[i-1].ZoneIndex), start, changes[i].Start.Unix()
+	}
+	if !zl.hasRule {
+		return zl.zone(changes[i-1].ZoneIndex), start, omega
+	}
+	zone, ruleStart, end := zl.rule.lookup(sec)
+	if ruleStart < start {
+		ruleStart = start
+	}
+	return zone, ruleStart, end
+}
+
+// zone returns the zone at index i, or an empty zone if there is no such zone.
+func (zl *zoneLookup) zone(i int) Zone {
+	if i < 0 || i >= len(zl.t.Zones) {
+		return Zone{}
+	}
+	return zl.t.Zones[i]
+}
+
+// sameZone reports whether the zones are the same when encoded in TZif.
+func sameZone(a, b Zone) bool {
+	return a.Name == b.Name && a.Offset.Round(time.Second) == b.Offset.Round(time.Second) && a.IsDST == b.IsDST
+}
@@ -0,0 +1,67 @@
+package timezones
+
+import (
+	"archive/zip"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestZoneLookup(t *testing.T) {
+	template := benchTemplate()
+	loc, err := NewLocation(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zl := newZoneLookup(&template)
+	first := template.Changes[0].Start.Unix()
+	last := template.Changes[len(template.Changes)-1].Start.Unix()
+	for sec := first - 10*secondsPerDay; sec < last+10*secondsPerDay; sec += 3 * 3600 {
+		zone, start, end := zl.lookup(sec)
+		if sec < start || sec >= end {
+			t.Fatalf("%d not in [%d, %d)", sec, start, end)
+		}
+		name, offset := time.Unix(sec, 0).In(loc).Zone()
+		if zone.Name != name || zone.Offset != time.Duration(offset)*time.Second {
+			t.Fatalf("at %d expected %s %d, got %+v", sec, name, offset, zone)
+		}
+	}
+}
+
+func TestZoneLookup_GoZoneinfo(t *testing.T) {
+	zr, err := zip.OpenReader(filepath.Join(runtime.GOROOT(), "lib", "time", "zoneinfo.zip"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			t.Skip("zoneinfo.zip not available")
+		}
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	for _, name := range []string{"Europe/Prague", "America/New_York", "Australia/Sydney", "Asia/Tokyo"} {
+		data, err := fs.ReadFile(zr, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template, err := LoadTZData(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		loc, err := time.LoadLocationFromTZData(name, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zl := newZoneLookup(template)
+		start := time.Date(1850, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+		end := time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+		for sec := start; sec < end; sec += 17 * 3600 {
+			zone, _, _ := zl.lookup(sec)
+			zoneName, offset := time.Unix(sec, 0).In(loc).Zone()
+			if zone.Name != zoneName || zone.Offset != time.Duration(offset)*time.Second {
+				t.Fatalf("%s at %d: expected %s %d, got %+v", name, sec, zoneName, offset, zone)
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package timezones
+
+import (
+	"sort"
+	"time"
+)
+
+// Sample is an observation of a local time, e.g. a timestamp with an offset and a designation found in logs.
+type Sample struct {
+	// At is the observed instant.
+	At time.Time
+
+	// Offset is the observed offset from UTC.
+	Offset time.Duration
+
+	// Name is the observed designation, e.g. "CEST". If empty, any designation matches.
+	Name string
+}
+
+// MatchSamples returns the sorted names of the templates consistent with all the samples,
+// i.e. the templates that use a zone with the sample's offset, and designation if set, at the sample's instant.
+// It is meant for finding out in which of the zones loaded by LoadAll the samples were observed.
+// Offsets are compared in whole seconds.
+func MatchSamples(templates map[string]*Template, samples []Sample) []string {
+	var names []string
+	for name, t := range templates {
+		if matchesSamples(t, samples) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func matchesSamples(t *Template, samples []Sample) bool {
+	zl := newZoneLookup(t)
+	for _, s := range samples {
+		zone, _, _ := zl.lookup(s.At.Unix())
+		if zone.Offset.Round(time.Second) != s.Offset.Round(time.Second) || (s.Name != "" && zone.Name != s.Name) {
+			return false
+		}
+	}
+	return true
+}
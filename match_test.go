@@ -0,0 +1,34 @@
+package timezones
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMatchSamples(t *testing.T) {
+	templates := map[string]*Template{
+		"Europe/Prague":    {Extend: "CET-1CEST,M3.5.0,M10.5.0/3"},
+		"Europe/London":    {Extend: "GMT0BST,M3.5.0/1,M10.5.0"},
+		"Africa/Algiers":   {Zones: []Zone{{Name: "CET", Offset: time.Hour}}},
+		"Africa/Lagos":     {Zones: []Zone{{Name: "WAT", Offset: time.Hour}}},
+		"America/New_York": {Extend: "EST5EDT,M3.2.0,M11.1.0"},
+	}
+	winter := time.Date(2022, time.January, 10, 12, 0, 0, 0, time.UTC)
+	summer := time.Date(2022, time.July, 10, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name     string
+		samples  []Sample
+		expected []string
+	}{
+		{"offset", []Sample{{At: winter, Offset: time.Hour}}, []string{"Africa/Algiers", "Africa/Lagos", "Europe/Prague"}},
+		{"designation", []Sample{{At: winter, Offset: time.Hour, Name: "CET"}}, []string{"Africa/Algiers", "Europe/Prague"}},
+		{"two samples", []Sample{{At: winter, Offset: time.Hour}, {At: summer, Offset: 2 * time.Hour}}, []string{"Europe/Prague"}},
+		{"summer", []Sample{{At: summer, Offset: time.Hour}}, []string{"Africa/Algiers", "Africa/Lagos", "Europe/London"}},
+		{"none", []Sample{{At: winter, Offset: 3 * time.Hour}}, nil},
+	} {
+		if names := MatchSamples(templates, tc.samples); !reflect.DeepEqual(names, tc.expected) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.expected, names)
+		}
+	}
+}
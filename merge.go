@@ -0,0 +1,227 @@
+package timezones
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MergeSource is a template to merge with others, see Merge.
+type MergeSource struct {
+	// Name identifies the source in conflicts, e.g. "iana" or "override".
+	Name string
+
+	// Template of the source.
+	Template *Template
+
+	// From and To delimit the time range [From, To) the source has data for.
+	// Zero From means since the beginning of time and zero To means forever.
+	From, To time.Time
+}
+
+// Conflict is an interval during which merged sources disagree on the zone in effect, see Merge.
+type Conflict struct {
+	// Start and End delimit the interval [Start, End).
+	// Start is zero if the interval starts at the beginning of time, End is zero if the conflict
+	// continues past all changes and range boundaries of the sources.
+	Start, End time.Time
+
+	// Sources are the names of the sources with data for the interval, in the order of Merge arguments,
+	// and Zones are their zones in effect.
+	Sources []string
+	Zones   []Zone
+
+	// Winner is the index into Sources and Zones of the source whose zone the merged template uses.
+	Winner int
+}
+
+// String describes the conflict, e.g.
+// "2022-01-01T00:00:00Z to 2022-03-27T01:00:00Z: override MSK +03:00, iana CET +01:00, using override".
+func (c Conflict) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s to %s:", formatBound(c.Start, "beginning of time"), formatBound(c.End, "end of data"))
+	for i := range c.Sources {
+		fmt.Fprintf(&sb, " %s %s %s,", c.Sources[i], c.Zones[i].Name, DurationOffset(c.Zones[i].Offset))
+	}
+	fmt.Fprintf(&sb, " using %s", c.Sources[c.Winner])
+	return sb.String()
+}
+
+// MergeOptions configures Merge.
+type MergeOptions struct {
+	// Resolve chooses the source to use during a conflict and returns its index into Conflict.Sources.
+	// Nil means the first source, so that sources are given in the order of precedence.
+	Resolve func(c Conflict) int
+}
+
+// mergeHorizon is how long after the last change or range boundary of the sources Merge compares
+// the zones of Extend.
+const mergeHorizon = 2 * 366 * secondsPerDay
+
+// Merge merges sources for the same zone into a template named name, e.g. the history from the tz database
+// with a corporate override for some period.
+// Each interval is covered by the zone of the sources that have data for it. Where they disagree,
+// options.Resolve chooses the zone and the interval is reported as a Conflict, so that no disagreement
+// goes unnoticed. Adjacent intervals with the same disagreement are reported as one conflict.
+// Extend of the result is Extend of the source chosen for the end of time.
+//
+// Templates with changes can't use Extend before the first change, so the result uses the zones in effect
+// just before the first change or range boundary of the sources since the beginning of time.
+// Extend strings are only compared within two years after the last change or range boundary of the sources,
+// where they usually disagree if they differ at all.
+// If no source has changes or range boundaries, they are compared within two years after the Unix epoch
+// and the result is the template of the source chosen for the end of time, with Extend in effect at all times.
+//
+// It returns ErrNoZones if there are no sources and an error wrapping ErrMergeGap if no source has data
+// for some interval.
+func Merge(name string, sources []MergeSource, options MergeOptions) (Template, []Conflict, error) {
+	if len(sources) == 0 {
+		return Template{}, nil, ErrNoZones
+	}
+	lookups := make([]*zoneLookup, len(sources))
+	var cuts []int64
+	for i, s := range sources {
+		lookups[i] = newZoneLookup(s.Template)
+		if !s.From.IsZero() {
+			cuts = append(cuts, s.From.Unix())
+		}
+		if !s.To.IsZero() {
+			cuts = append(cuts, s.To.Unix())
+		}
+		for _, c := range s.Template.Changes {
+			cuts = append(cuts, c.Start.Unix())
+		}
+	}
+	// Without changes and range boundaries, Extend applies at all times and the cuts below
+	// only serve to compare the sources.
+	extendOnly := len(cuts) == 0
+	var lo, hi int64
+	for i, sec := range cuts {
+		if i == 0 || sec < lo {
+			lo = sec
+		}
+		if i == 0 || sec > hi {
+			hi = sec
+		}
+	}
+	horizon := hi + mergeHorizon
+	for _, zl := range lookups {
+		for sec := lo; ; {
+			_, _, end := zl.lookup(sec)
+			if end >= horizon || end <= sec {
+				break
+			}
+			cuts = append(cuts, end)
+			sec = end
+		}
+	}
+	sort.Slice(cuts, func(i, j int) bool { return cuts[i] < cuts[j] })
+	starts := []int64{alpha}
+	for _, sec := range cuts {
+		if sec != starts[len(starts)-1] {
+			starts = append(starts, sec)
+		}
+	}
+
+	result := Template{Name: name}
+	var conflicts []Conflict
+	zoneIndex := func(zone Zone) int {
+		for i := range result.Zones {
+			if sameZone(result.Zones[i], zone) {
+				return i
+			}
+		}
+		result.Zones = append(result.Zones, zone)
+		return len(result.Zones) - 1
+	}
+	for k, start := range starts {
+		end := int64(omega)
+		if k+1 < len(starts) {
+			end = starts[k+1]
+		}
+		c := Conflict{Start: unixOrZero(start), End: unixOrZero(end)}
+		var indices []int
+		for i, s := range sources {
+			if (s.From.IsZero() || s.From.Unix() <= start) && (s.To.IsZero() || end <= s.To.Unix()) {
+				// The zone before lo is the zone at lo-1, as all changes start at lo or later.
+				sec := start
+				if sec < lo {
+					sec = lo - 1
+				}
+				zone, _, _ := lookups[i].lookup(sec)
+				c.Sources = append(c.Sources, s.Name)
+				c.Zones = append(c.Zones, zone)
+				indices = append(indices, i)
+			}
+		}
+		if len(indices) == 0 {
+			return Template{}, nil, fmt.Errorf("%w: %s to %s", ErrMergeGap, formatBound(c.Start, "beginning of time"),
+				formatBound(c.End, "end of time"))
+		}
+		for _, zone := range c.Zones[1:] {
+			if !sameZone(zone, c.Zones[0]) {
+				if options.Resolve != nil {
+					c.Winner = options.Resolve(c)
+				}
+				if c.Winner < 0 || c.Winner >= len(c.Sources) {
+					return Template{}, nil, fmt.Errorf("timezones: Resolve returned %d for %d sources", c.Winner, len(c.Sources))
+				}
+				if n := len(conflicts); n > 0 && conflicts[n-1].End.Equal(c.Start) && sameConflict(conflicts[n-1], c) {
+					conflicts[n-1].End = c.End
+				} else {
+					conflicts = append(conflicts, c)
+				}
+				break
+			}
+		}
+
+		zi := zoneIndex(c.Zones[c.Winner])
+		switch {
+		case k == 0:
+			result.FirstZoneIndex = zi
+		case result.Changes == nil && zi != result.FirstZoneIndex,
+			result.Changes != nil && zi != result.Changes[len(result.Changes)-1].ZoneIndex:
+			result.Changes = append(result.Changes, Change{Start: time.Unix(start, 0).UTC(), ZoneIndex: zi})
+		}
+		if k == len(starts)-1 {
+			if extendOnly {
+				last := *sources[indices[c.Winner]].Template
+				last.Name = name
+				last.Zones = append([]Zone(nil), last.Zones...)
+				return last, conflicts, nil
+			}
+			result.Extend = sources[indices[c.Winner]].Template.Extend
+		}
+	}
+	return result, conflicts, nil
+}
+
+// unixOrZero converts sec to time, with alpha and omega as zero time.
+func unixOrZero(sec int64) time.Time {
+	if sec == alpha || sec == omega {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0).UTC()
+}
+
+// formatBound formats t for error messages, or returns zero if t is zero.
+func formatBound(t time.Time, zero string) string {
+	if t.IsZero() {
+		return zero
+	}
+	return formatTime(t)
+}
+
+// sameConflict reports whether the conflicts are between the same zones of the same sources with the same winner.
+func sameConflict(a, b Conflict) bool {
+	if a.Winner != b.Winner || len(a.Sources) != len(b.Sources) {
+		return false
+	}
+	for i := range a.Sources {
+		if a.Sources[i] != b.Sources[i] || !sameZone(a.Zones[i], b.Zones[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package timezones
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	iana := Template{
+		Zones:  []Zone{{Name: "CET", Offset: time.Hour}, {Name: "CEST", Offset: 2 * time.Hour, IsDST: true}},
+		Extend: "CET-1CEST,M3.5.0,M10.5.0/3",
+	}
+	override := Template{Zones: []Zone{{Name: "MSK", Offset: 3 * time.Hour}}}
+	from := time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	sources := []MergeSource{
+		{Name: "override", Template: &override, From: from, To: to},
+		{Name: "iana", Template: &iana},
+	}
+
+	merged, conflicts, err := Merge("Custom/Zone", sources, MergeOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// CET, CEST and CET again disagree with MSK.
+	if len(conflicts) != 3 {
+		t.Fatalf("expected 3 conflicts, got %v", conflicts)
+	}
+	if !conflicts[0].Start.Equal(from) || !conflicts[2].End.Equal(to) || !conflicts[0].End.Equal(conflicts[1].Start) {
+		t.Fatalf("unexpected conflict intervals %v", conflicts)
+	}
+	const expected = "2022-01-01T00:00:00Z to 2022-03-27T01:00:00Z: override MSK +03:00, iana CET +01:00, using override"
+	if s := conflicts[0].String(); s != expected {
+		t.Fatalf("expected %q, got %q", expected, s)
+	}
+	if merged.Name != "Custom/Zone" || merged.Extend != iana.Extend {
+		t.Fatalf("unexpected template %+v", merged)
+	}
+	if err := Validate(merged); err != nil {
+		t.Fatalf("merged template is not valid: %v", err)
+	}
+	for _, tc := range []struct {
+		at   time.Time
+		name string
+	}{
+		{time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC), "MSK"},
+		{time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC), "CET"},
+		{time.Date(2030, time.July, 1, 0, 0, 0, 0, time.UTC), "CEST"},
+		{time.Date(1900, time.July, 1, 0, 0, 0, 0, time.UTC), "CET"},
+	} {
+		if zone := merged.ZoneAt(tc.at); zone.Name != tc.name {
+			t.Errorf("%v: expected %s, got %+v", tc.at, tc.name, zone)
+		}
+	}
+
+	// Prefer the tz database, the conflicts are still reported.
+	merged, conflicts, err = Merge("Custom/Zone", sources, MergeOptions{Resolve: func(c Conflict) int {
+		for i, name := range c.Sources {
+			if name == "iana" {
+				return i
+			}
+		}
+		return 0
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(conflicts) != 3 || conflicts[1].Winner != 1 || conflicts[1].Zones[1].Name != "CEST" {
+		t.Fatalf("unexpected conflicts %v", conflicts)
+	}
+	if zone := merged.ZoneAt(time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)); zone.Name != "CEST" {
+		t.Fatalf("expected CEST, got %+v", zone)
+	}
+
+	// Agreeing sources have no conflicts.
+	if _, conflicts, err = Merge("Custom/Zone", []MergeSource{{Name: "a", Template: &iana}, {Name: "b", Template: &iana}},
+		MergeOptions{}); err != nil || len(conflicts) != 0 {
+		t.Fatalf("expected no conflicts, got %v, %v", conflicts, err)
+	}
+}
+
+func TestMerge_ExtendOnly(t *testing.T) {
+	cet := Template{
+		Zones:  []Zone{{Name: "CET", Offset: time.Hour}, {Name: "CEST", Offset: 2 * time.Hour, IsDST: true}},
+		Extend: "CET-1CEST,M3.5.0,M10.5.0/3",
+	}
+	copied := cet
+	merged, conflicts, err := Merge("Europe/Zone", []MergeSource{{Name: "a", Template: &cet}, {Name: "b", Template: &copied}},
+		MergeOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(conflicts) != 0 || len(merged.Changes) != 0 || merged.Extend != cet.Extend || merged.Name != "Europe/Zone" {
+		t.Fatalf("expected the Extend-only template, got %+v %v", merged, conflicts)
+	}
+	if zone := merged.ZoneAt(time.Date(1960, time.July, 1, 0, 0, 0, 0, time.UTC)); zone.Name != "CEST" {
+		t.Fatalf("expected CEST in 1960, got %+v", zone)
+	}
+
+	// Sources without DST disagree with the summer time of cet.
+	fixed := Template{Zones: []Zone{{Name: "CET", Offset: time.Hour}}, Extend: "CET-1"}
+	merged, conflicts, err = Merge("Europe/Zone", []MergeSource{{Name: "fixed", Template: &fixed}, {Name: "cet", Template: &cet}},
+		MergeOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(conflicts) == 0 || len(merged.Changes) != 0 || merged.Extend != "CET-1" {
+		t.Fatalf("expected conflicts and the fixed template, got %+v %v", merged, conflicts)
+	}
+}
+
+func TestMerge_Errors(t *testing.T) {
+	if _, _, err := Merge("", nil, MergeOptions{}); !errors.Is(err, ErrNoZones) {
+		t.Fatalf("expected ErrNoZones, got %v", err)
+	}
+	fixed := Template{Zones: []Zone{{Name: "MSK", Offset: 3 * time.Hour}}}
+	other := Template{Zones: []Zone{{Name: "CET", Offset: time.Hour}}}
+	sources := []MergeSource{{Name: "override", Template: &fixed, From: time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)}}
+	if _, _, err := Merge("", sources, MergeOptions{}); !errors.Is(err, ErrMergeGap) {
+		t.Fatalf("expected ErrMergeGap, got %v", err)
+	}
+	sources = append(sources, MergeSource{Name: "base", Template: &other})
+	if _, _, err := Merge("", sources, MergeOptions{Resolve: func(Conflict) int { return 5 }}); err == nil {
+		t.Fatal("expected error for invalid Resolve result")
+	}
+}
@@ -0,0 +1,61 @@
+package timezones
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// MetadataSuffix is appended to the path of a TZif file to get the path of its metadata sidecar,
+// e.g. "Europe/Bratislava.json".
+const MetadataSuffix = ".json"
+
+// Metadata describes a template with information the TZif format has no place for.
+// LoadAll and LoadFile read it from a JSON sidecar file next to the TZif file, see MetadataSuffix,
+// and WriteTree writes it there.
+type Metadata struct {
+	// Source describes where the data comes from, e.g. "tzdata 2024a" or a URL.
+	Source string `json:"source,omitempty"`
+
+	// Comments are free-form notes about the zone, e.g. the comments of its zic source.
+	Comments []string `json:"comments,omitempty"`
+
+	// ReviewStatus records whether the data was reviewed, e.g. "draft" or "approved".
+	ReviewStatus string `json:"review_status,omitempty"`
+}
+
+// loadMetadata reads the metadata sidecar of the TZif file path from fsys.
+// It returns nil metadata and nil error if there is no sidecar.
+func loadMetadata(fsys fs.FS, path string) (*Metadata, error) {
+	data, err := fs.ReadFile(fsys, path+MetadataSuffix)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var metadata Metadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("%s%s: %w", path, MetadataSuffix, err)
+	}
+	return &metadata, nil
+}
+
+// writeMetadata writes the metadata sidecar of the TZif file at path,
+// or removes a stale sidecar if metadata is nil.
+func writeMetadata(path string, metadata *Metadata) error {
+	sidecar := path + MetadataSuffix
+	if metadata == nil {
+		if err := os.Remove(sidecar); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	return replaceFile(sidecar, func() error { return os.WriteFile(sidecar, append(data, '\n'), 0o644) })
+}
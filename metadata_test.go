@@ -0,0 +1,87 @@
+package timezones
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMetadata_Tree(t *testing.T) {
+	bench := benchTemplate()
+	metadata := &Metadata{Source: "tzdata 2024a", Comments: []string{"Custom zone"}, ReviewStatus: "approved"}
+	bench.Metadata = metadata
+	templates := map[string]*Template{"Custom/Bench": &bench}
+	aliases := map[string]string{"Bench": "Custom/Bench"}
+	dir := t.TempDir()
+	if err := WriteTree(dir, templates, aliases, TreeOptions{Links: CopyAliases}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "Custom", "Bench.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expectedJSON = `{
+  "source": "tzdata 2024a",
+  "comments": [
+    "Custom zone"
+  ],
+  "review_status": "approved"
+}
+`
+	if string(data) != expectedJSON {
+		t.Fatalf("unexpected sidecar %s", data)
+	}
+
+	loaded, err := LoadAll(os.DirFS(dir), LoadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("expected 2 templates, got %d", len(loaded))
+	}
+	if !reflect.DeepEqual(loaded["Custom/Bench"].Metadata, metadata) {
+		t.Fatalf("unexpected metadata %+v", loaded["Custom/Bench"].Metadata)
+	}
+	if loaded["Bench"].Metadata != nil {
+		t.Fatalf("expected no metadata for alias, got %+v", loaded["Bench"].Metadata)
+	}
+
+	// Sidecars of templates without metadata are removed.
+	bench.Metadata = nil
+	if err := WriteTree(dir, templates, aliases, TreeOptions{Links: CopyAliases}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Custom", "Bench.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected sidecar to be removed, got %v", err)
+	}
+}
+
+func TestMetadata_Load(t *testing.T) {
+	fsys := testFS(t)
+	fsys["Etc/MyFixed.json"] = &fstest.MapFile{Data: []byte(`{"source": "manual", "review_status": "draft"}`)}
+	template, err := LoadFile(fsys, "Etc/MyFixed")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := &Metadata{Source: "manual", ReviewStatus: "draft"}
+	if !reflect.DeepEqual(template.Metadata, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, template.Metadata)
+	}
+	templates, err := LoadAll(fsys, LoadOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(templates) != 2 || !reflect.DeepEqual(templates["Etc/MyFixed"].Metadata, expected) {
+		t.Fatalf("unexpected templates %+v", templates)
+	}
+
+	fsys["Etc/MyFixed.json"] = &fstest.MapFile{Data: []byte(`{"source": `)}
+	if _, err := LoadFile(fsys, "Etc/MyFixed"); err == nil {
+		t.Fatal("expected error for invalid sidecar")
+	}
+	if _, err := LoadAll(fsys, LoadOptions{}); err == nil {
+		t.Fatal("expected error for invalid sidecar")
+	}
+}
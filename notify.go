@@ -0,0 +1,151 @@
+package timezones
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Transition is a change of the zone of a named template, see UpcomingTransitions.
+type Transition struct {
+	// Name of the template, the key in the map of templates.
+	Name string
+
+	// At is the instant of the change.
+	At time.Time
+
+	// Before is the zone in effect before At, After the zone in effect since At.
+	Before, After Zone
+}
+
+// NextTransition returns the first change of the zone after after, or false if the zone never changes again.
+// Extend is evaluated for times after the last change. The returned Name is empty.
+func (t *Template) NextTransition(after time.Time) (Transition, bool) {
+	zl := newZoneLookup(t)
+	// Extend repeats every year, so if there is no change within two years after the last change,
+	// there is none at all.
+	horizon := after.Unix()
+	if n := len(t.Changes); n > 0 && t.Changes[n-1].Start.Unix() > horizon {
+		horizon = t.Changes[n-1].Start.Unix()
+	}
+	horizon += 2 * 366 * secondsPerDay
+
+	prev, _, sec := zl.lookup(after.Unix())
+	for sec != omega && sec <= horizon {
+		zone, _, next := zl.lookup(sec)
+		if !sameZone(prev, zone) {
+			return Transition{At: time.Unix(sec, 0).UTC(), Before: prev, After: zone}, true
+		}
+		sec = next
+	}
+	return Transition{}, false
+}
+
+// UpcomingTransitions returns the changes of the zones of the templates after from and before to,
+// sorted by At and then by Name, e.g. to plan pausing jobs around daylight saving time changes.
+func UpcomingTransitions(templates map[string]*Template, from, to time.Time) []Transition {
+	var transitions []Transition
+	for name, t := range templates {
+		after := from
+		for {
+			tr, ok := t.NextTransition(after)
+			if !ok || !tr.At.Before(to) {
+				break
+			}
+			tr.Name = name
+			transitions = append(transitions, tr)
+			after = tr.At
+		}
+	}
+	sort.Slice(transitions, func(i, j int) bool {
+		if !transitions[i].At.Equal(transitions[j].At) {
+			return transitions[i].At.Before(transitions[j].At)
+		}
+		return transitions[i].Name < transitions[j].Name
+	})
+	return transitions
+}
+
+// Notifier calls Notify shortly before each change of the zones of Templates.
+// Use a Notify function that sends to a channel to receive the notifications elsewhere.
+type Notifier struct {
+	// Templates to watch, keyed by name, e.g. loaded by LoadAll.
+	// They must not be modified while Run is running.
+	Templates map[string]*Template
+
+	// Lead is how long before each change Notify is called. Zero calls it at the time of the change.
+	Lead time.Duration
+
+	// Notify is called with each change, in the order of UpcomingTransitions.
+	// Changes that are less than Lead away when Run starts are notified immediately.
+	Notify func(Transition)
+
+	// now and sleep replace the clock in tests.
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// Run notifies about changes until ctx is done, returning its error, or until no zone changes anymore,
+// returning nil.
+func (n *Notifier) Run(ctx context.Context) error {
+	now, sleep := n.now, n.sleep
+	if now == nil {
+		now = time.Now
+	}
+	if sleep == nil {
+		sleep = sleepContext
+	}
+	after := now()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		next, ok := n.next(after)
+		if !ok {
+			return nil
+		}
+		if err := sleep(ctx, next[0].At.Add(-n.Lead).Sub(now())); err != nil {
+			return err
+		}
+		for _, tr := range next {
+			n.Notify(tr)
+		}
+		after = next[0].At
+	}
+}
+
+// next returns the earliest changes after after, all at the same instant.
+func (n *Notifier) next(after time.Time) ([]Transition, bool) {
+	var next []Transition
+	for name, t := range n.Templates {
+		tr, ok := t.NextTransition(after)
+		if !ok {
+			continue
+		}
+		tr.Name = name
+		switch {
+		case len(next) == 0 || tr.At.Before(next[0].At):
+			next = append(next[:0], tr)
+		case tr.At.Equal(next[0].At):
+			next = append(next, tr)
+		}
+	}
+	sort.Slice(next, func(i, j int) bool {
+		return next[i].Name < next[j].Name
+	})
+	return next, len(next) > 0
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,95 @@
+package timezones
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestUpcomingTransitions(t *testing.T) {
+	cet := Zone{Name: "CET", Offset: time.Hour}
+	cest := Zone{Name: "CEST", Offset: 2 * time.Hour, IsDST: true}
+	est := Zone{Name: "EST", Offset: -5 * time.Hour}
+	edt := Zone{Name: "EDT", Offset: -4 * time.Hour, IsDST: true}
+	templates := map[string]*Template{
+		"Europe/Prague":    {Zones: []Zone{cet}, Extend: "CET-1CEST,M3.5.0,M10.5.0/3"},
+		"America/New_York": {Zones: []Zone{est}, Extend: "EST5EDT,M3.2.0,M11.1.0"},
+		"Etc/UTC":          {Zones: []Zone{{Name: "UTC"}}},
+	}
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)
+	expected := []Transition{
+		{Name: "America/New_York", At: time.Date(2024, time.March, 10, 7, 0, 0, 0, time.UTC), Before: est, After: edt},
+		{Name: "Europe/Prague", At: time.Date(2024, time.March, 31, 1, 0, 0, 0, time.UTC), Before: cet, After: cest},
+	}
+	if transitions := UpcomingTransitions(templates, from, to); !reflect.DeepEqual(transitions, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, transitions)
+	}
+}
+
+func TestTemplate_NextTransition(t *testing.T) {
+	template := Template{
+		Zones:   []Zone{{Name: "A"}, {Name: "B", Offset: time.Hour}},
+		Changes: []Change{{Start: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC), ZoneIndex: 1}},
+	}
+	tr, ok := template.NextTransition(time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC))
+	if !ok || !tr.At.Equal(template.Changes[0].Start) || tr.Before.Name != "A" || tr.After.Name != "B" {
+		t.Fatalf("unexpected transition %+v, %v", tr, ok)
+	}
+	if tr, ok := template.NextTransition(template.Changes[0].Start); ok {
+		t.Fatalf("expected no transition, got %+v", tr)
+	}
+}
+
+func TestNotifier_Run(t *testing.T) {
+	cet := Zone{Name: "CET", Offset: time.Hour}
+	clock := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	var notified []time.Time
+	var clocks []time.Time
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n := Notifier{
+		Templates: map[string]*Template{"Europe/Prague": {Zones: []Zone{cet}, Extend: "CET-1CEST,M3.5.0,M10.5.0/3"}},
+		Lead:      time.Hour,
+		Notify: func(tr Transition) {
+			notified = append(notified, tr.At)
+			clocks = append(clocks, clock)
+			if len(notified) == 3 {
+				cancel()
+			}
+		},
+		now: func() time.Time { return clock },
+		sleep: func(ctx context.Context, d time.Duration) error {
+			clock = clock.Add(d)
+			return nil
+		},
+	}
+	if err := n.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	expected := []time.Time{
+		time.Date(2024, time.March, 31, 1, 0, 0, 0, time.UTC),
+		time.Date(2024, time.October, 27, 1, 0, 0, 0, time.UTC),
+		time.Date(2025, time.March, 30, 1, 0, 0, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(notified, expected) {
+		t.Fatalf("expected %v, got %v", expected, notified)
+	}
+	for i := range clocks {
+		if !clocks[i].Equal(expected[i].Add(-time.Hour)) {
+			t.Fatalf("notification %d at %v, expected an hour before %v", i, clocks[i], expected[i])
+		}
+	}
+}
+
+func TestNotifier_Run_NoMoreChanges(t *testing.T) {
+	n := Notifier{
+		Templates: map[string]*Template{"Etc/UTC": {Zones: []Zone{{Name: "UTC"}}}},
+		Notify:    func(Transition) { t.Fatal("unexpected notification") },
+	}
+	if err := n.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,127 @@
+package timezones
+
+import (
+	"fmt"
+	"time"
+)
+
+// Offset is an offset from UTC in seconds, positive east of UTC, the precision TZif stores offsets with.
+type Offset int32
+
+// OffsetOf returns the offset of the zone, rounded to seconds.
+func OffsetOf(zone Zone) Offset {
+	return DurationOffset(zone.Offset)
+}
+
+// DurationOffset converts the duration to an offset, rounded to seconds.
+func DurationOffset(d time.Duration) Offset {
+	return Offset(d.Round(time.Second) / time.Second)
+}
+
+// Duration returns the offset as a time.Duration, e.g. for Zone.Offset.
+func (o Offset) Duration() time.Duration {
+	return time.Duration(o) * time.Second
+}
+
+// String formats the offset as ±hh:mm, or ±hh:mm:ss if it is not a whole number of minutes, e.g. "+05:30".
+func (o Offset) String() string {
+	sign := '+'
+	secs := int64(o)
+	if secs < 0 {
+		sign = '-'
+		secs = -secs
+	}
+	h, m, s := secs/3600, secs/60%60, secs%60
+	if s != 0 {
+		return fmt.Sprintf("%c%02d:%02d:%02d", sign, h, m, s)
+	}
+	return fmt.Sprintf("%c%02d:%02d", sign, h, m)
+}
+
+// ISO8601 formats the offset for ISO 8601 and RFC 3339 timestamps: "Z" for UTC, String otherwise.
+// ISO 8601 has no seconds in offsets; offsets that are not a whole number of minutes include them
+// anyway, as RFC 9557 allows.
+func (o Offset) ISO8601() string {
+	if o == 0 {
+		return "Z"
+	}
+	return o.String()
+}
+
+// Numeric formats the offset as a numeric time zone designation like zic's %z: ±hh, ±hhmm or ±hhmmss,
+// whichever is the shortest that represents the offset exactly, e.g. "-03" or "+0530".
+func (o Offset) Numeric() string {
+	sign := '+'
+	secs := int64(o)
+	if secs < 0 {
+		sign = '-'
+		secs = -secs
+	}
+	h, m, s := secs/3600, secs/60%60, secs%60
+	switch {
+	case s != 0:
+		return fmt.Sprintf("%c%02d%02d%02d", sign, h, m, s)
+	case m != 0:
+		return fmt.Sprintf("%c%02d%02d", sign, h, m)
+	default:
+		return fmt.Sprintf("%c%02d", sign, h)
+	}
+}
+
+// ParseOffset parses an offset in the form ±hh, ±hhmm, ±hh:mm, ±hhmmss or ±hh:mm:ss, e.g. "+05:30",
+// or "Z" for UTC.
+// Hours must be at most 24 and minutes and seconds at most 59, like in TZ strings.
+// It returns an error wrapping ErrInvalidOffset if s is not a valid offset.
+func ParseOffset(s string) (Offset, error) {
+	invalid := func() (Offset, error) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidOffset, s)
+	}
+	if s == "Z" || s == "z" {
+		return 0, nil
+	}
+	if len(s) < 3 || s[0] != '+' && s[0] != '-' {
+		return invalid()
+	}
+	rest := s[1:]
+	var parts []string
+	if len(rest) > 2 && rest[2] == ':' {
+		// Extended format, ±hh:mm or ±hh:mm:ss.
+		for len(rest) > 2 && rest[2] == ':' {
+			parts = append(parts, rest[:2])
+			rest = rest[3:]
+		}
+		parts = append(parts, rest)
+	} else {
+		// Basic format, ±hh, ±hhmm or ±hhmmss.
+		for len(rest) > 0 {
+			if len(rest) < 2 {
+				return invalid()
+			}
+			parts = append(parts, rest[:2])
+			rest = rest[2:]
+		}
+	}
+	if len(parts) > 3 {
+		return invalid()
+	}
+	limits := []int{24, 59, 59}
+	secs := 0
+	for i := 0; i < 3; i++ {
+		n := 0
+		if i < len(parts) {
+			p := parts[i]
+			if len(p) != 2 || p[0] < '0' || p[0] > '9' || p[1] < '0' || p[1] > '9' {
+				return invalid()
+			}
+			n = int(p[0]-'0')*10 + int(p[1]-'0')
+			if n > limits[i] {
+				return invalid()
+			}
+		}
+		secs = secs*60 + n
+	}
+	if s[0] == '-' {
+		secs = -secs
+	}
+	return Offset(secs), nil
+}
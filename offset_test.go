@@ -0,0 +1,77 @@
+package timezones
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseOffset(t *testing.T) {
+	for _, tc := range []struct {
+		s        string
+		expected Offset
+	}{
+		{"Z", 0},
+		{"+00:00", 0},
+		{"+05", 5 * 3600},
+		{"+0530", 5*3600 + 30*60},
+		{"+05:30", 5*3600 + 30*60},
+		{"-09:30", -(9*3600 + 30*60)},
+		{"+00:57:44", 57*60 + 44},
+		{"-005744", -(57*60 + 44)},
+		{"+24:00", 24 * 3600},
+	} {
+		o, err := ParseOffset(tc.s)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.s, err)
+		}
+		if o != tc.expected {
+			t.Fatalf("%q: expected %d, got %d", tc.s, tc.expected, o)
+		}
+	}
+	for _, s := range []string{"", "05:30", "+5", "+05:3", "+0530:00", "+25", "+05:60", "+05:30:60", "+05:30:00:00", "+aa", "+05:-1"} {
+		if _, err := ParseOffset(s); !errors.Is(err, ErrInvalidOffset) {
+			t.Fatalf("%q: expected ErrInvalidOffset, got %v", s, err)
+		}
+	}
+}
+
+func TestOffset_String(t *testing.T) {
+	for _, tc := range []struct {
+		offset   Offset
+		expected string
+		iso      string
+		numeric  string
+	}{
+		{0, "+00:00", "Z", "+00"},
+		{5*3600 + 30*60, "+05:30", "+05:30", "+0530"},
+		{-3 * 3600, "-03:00", "-03:00", "-03"},
+		{-(57*60 + 44), "-00:57:44", "-00:57:44", "-005744"},
+	} {
+		if s := tc.offset.String(); s != tc.expected {
+			t.Fatalf("%d: expected %s, got %s", tc.offset, tc.expected, s)
+		}
+		if s := tc.offset.ISO8601(); s != tc.iso {
+			t.Fatalf("%d: expected %s, got %s", tc.offset, tc.iso, s)
+		}
+		if s := tc.offset.Numeric(); s != tc.numeric {
+			t.Fatalf("%d: expected %s, got %s", tc.offset, tc.numeric, s)
+		}
+		if parsed, err := ParseOffset(tc.offset.Numeric()); err != nil || parsed != tc.offset {
+			t.Fatalf("%d: numeric round trip returned %d, %v", tc.offset, parsed, err)
+		}
+		if parsed, err := ParseOffset(tc.offset.String()); err != nil || parsed != tc.offset {
+			t.Fatalf("%d: round trip returned %d, %v", tc.offset, parsed, err)
+		}
+	}
+}
+
+func TestOffsetOf(t *testing.T) {
+	zone := Zone{Name: "LMT", Offset: 57*time.Minute + 44*time.Second + 400*time.Millisecond}
+	if o := OffsetOf(zone); o != 57*60+44 {
+		t.Fatalf("expected 3464, got %d", o)
+	}
+	if d := OffsetOf(zone).Duration(); d != 57*time.Minute+44*time.Second {
+		t.Fatalf("expected 57m44s, got %v", d)
+	}
+}
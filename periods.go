@@ -0,0 +1,60 @@
+package timezones
+
+import (
+	"fmt"
+	"time"
+)
+
+// Period is a zone in effect until a given instant.
+// Sources like zic Zone lines and iCalendar VTIMEZONE describe the zones by the end of each period,
+// while Template.Changes describe them by the start, see PeriodsTemplate and Template.Periods.
+type Period struct {
+	// ZoneIndex is the index of the zone in effect during the period.
+	ZoneIndex int
+
+	// Until is the instant at which the period ends and the next one starts.
+	// It is zero for the last period.
+	Until time.Time
+}
+
+// PeriodsTemplate returns a template named name with the given zones and extend string in which the zones
+// are in effect in the order of periods.
+// The first period is in effect since the beginning of time and the last period until the end of time,
+// or until Extend takes over, so the Until of the last period is ignored.
+//
+// It returns a *FieldError wrapping ErrMissingUntil if any period but the last has a zero Until.
+// The template is not validated otherwise, use Validate to check it.
+func PeriodsTemplate(name string, zones []Zone, periods []Period, extend string) (Template, error) {
+	template := Template{Name: name, Zones: zones, Extend: extend}
+	if len(periods) == 0 {
+		return template, nil
+	}
+	template.FirstZoneIndex = periods[0].ZoneIndex
+	template.Changes = make([]Change, 0, len(periods)-1)
+	for i := 1; i < len(periods); i++ {
+		until := periods[i-1].Until
+		if until.IsZero() {
+			return Template{}, &FieldError{
+				Field:  "Periods",
+				Index:  i - 1,
+				Err:    ErrMissingUntil,
+				Detail: fmt.Sprintf("zone %d is followed by zone %d", periods[i-1].ZoneIndex, periods[i].ZoneIndex),
+			}
+		}
+		template.Changes = append(template.Changes, Change{Start: until, ZoneIndex: periods[i].ZoneIndex})
+	}
+	return template, nil
+}
+
+// Periods returns the zones of the template by the end of the periods they are in effect,
+// the inverse of PeriodsTemplate.
+// The Until of the last period is zero.
+func (t *Template) Periods() []Period {
+	periods := make([]Period, 0, len(t.Changes)+1)
+	zoneIndex := t.FirstZoneIndex
+	for _, c := range t.Changes {
+		periods = append(periods, Period{ZoneIndex: zoneIndex, Until: c.Start})
+		zoneIndex = c.ZoneIndex
+	}
+	return append(periods, Period{ZoneIndex: zoneIndex})
+}
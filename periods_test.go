@@ -0,0 +1,56 @@
+package timezones
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPeriodsTemplate(t *testing.T) {
+	cet := Zone{Name: "CET", Offset: time.Hour}
+	cest := Zone{Name: "CEST", Offset: 2 * time.Hour, IsDST: true}
+	lmt := Zone{Name: "LMT", Offset: 57*time.Minute + 44*time.Second}
+	zones := []Zone{cet, cest, lmt}
+	periods := []Period{
+		{ZoneIndex: 2, Until: time.Date(1891, time.October, 1, 0, 0, 0, 0, time.UTC)},
+		{ZoneIndex: 0, Until: time.Date(1916, time.April, 30, 22, 0, 0, 0, time.UTC)},
+		{ZoneIndex: 1, Until: time.Date(1916, time.September, 30, 23, 0, 0, 0, time.UTC)},
+		{ZoneIndex: 0},
+	}
+	template, err := PeriodsTemplate("Europe/Prague", zones, periods, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Template{
+		Name:           "Europe/Prague",
+		Zones:          zones,
+		FirstZoneIndex: 2,
+		Changes: []Change{
+			{Start: time.Date(1891, time.October, 1, 0, 0, 0, 0, time.UTC), ZoneIndex: 0},
+			{Start: time.Date(1916, time.April, 30, 22, 0, 0, 0, time.UTC), ZoneIndex: 1},
+			{Start: time.Date(1916, time.September, 30, 23, 0, 0, 0, time.UTC), ZoneIndex: 0},
+		},
+	}
+	if !reflect.DeepEqual(template, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, template)
+	}
+	if err := Validate(template); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if back := template.Periods(); !reflect.DeepEqual(back, periods) {
+		t.Fatalf("expected %+v, got %+v", periods, back)
+	}
+
+	template, err = PeriodsTemplate("", []Zone{cet}, []Period{{ZoneIndex: 0}}, "")
+	if err != nil || len(template.Changes) != 0 || template.FirstZoneIndex != 0 {
+		t.Fatalf("expected a template without changes, got %+v %v", template, err)
+	}
+
+	periods[1].Until = time.Time{}
+	_, err = PeriodsTemplate("", zones, periods, "")
+	var fieldErr *FieldError
+	if !errors.Is(err, ErrMissingUntil) || !errors.As(err, &fieldErr) || fieldErr.Field != "Periods" || fieldErr.Index != 1 {
+		t.Fatalf("expected ErrMissingUntil in Periods[1], got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package timezones
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// PickerGroup is a group of zones of a region in a time zone picker, see Picker.
+type PickerGroup struct {
+	// Region is the first component of the zone names, e.g. "Europe".
+	Region string `json:"region"`
+
+	// Zones of the region, sorted by offset and label.
+	Zones []PickerZone `json:"zones"`
+}
+
+// PickerZone is a zone in a time zone picker.
+type PickerZone struct {
+	// Name of the zone, e.g. "Europe/Bratislava".
+	Name string `json:"name"`
+
+	// Label describes the zone to users, e.g. "(UTC+02:00) Bratislava".
+	Label string `json:"label"`
+
+	// Offset of the zone at the reference instant.
+	Offset Offset `json:"offset"`
+}
+
+// Picker returns templates, e.g. loaded by LoadAll, as the grouped payload of the classic time zone picker.
+// Zones are grouped by region, the part of the name before the first slash, and labeled with their offset
+// at instant and the city, the last component of the name with underscores replaced by spaces.
+// Groups are sorted by region and the zones within a group by offset, then by label.
+//
+// Names without a region, such as "UTC" or "EST5EDT", are not included.
+func Picker(templates map[string]*Template, instant time.Time) []PickerGroup {
+	groups := make(map[string]*PickerGroup)
+	for name, t := range templates {
+		slash := strings.IndexByte(name, '/')
+		if slash < 0 {
+			continue
+		}
+		region := name[:slash]
+		group := groups[region]
+		if group == nil {
+			group = &PickerGroup{Region: region}
+			groups[region] = group
+		}
+		offset := OffsetOf(t.ZoneAt(instant))
+		city := strings.ReplaceAll(name[strings.LastIndexByte(name, '/')+1:], "_", " ")
+		group.Zones = append(group.Zones, PickerZone{
+			Name:   name,
+			Label:  "(UTC" + offset.String() + ") " + city,
+			Offset: offset,
+		})
+	}
+
+	result := make([]PickerGroup, 0, len(groups))
+	for _, group := range groups {
+		zones := group.Zones
+		sort.Slice(zones, func(i, j int) bool {
+			if zones[i].Offset != zones[j].Offset {
+				return zones[i].Offset < zones[j].Offset
+			}
+			if zones[i].Label != zones[j].Label {
+				return zones[i].Label < zones[j].Label
+			}
+			return zones[i].Name < zones[j].Name
+		})
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Region < result[j].Region
+	})
+	return result
+}
@@ -0,0 +1,67 @@
+package timezones
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPicker(t *testing.T) {
+	cet := Template{
+		Zones:  []Zone{{Name: "CET", Offset: time.Hour}, {Name: "CEST", Offset: 2 * time.Hour, IsDST: true}},
+		Extend: "CET-1CEST,M3.5.0,M10.5.0/3",
+	}
+	london := Template{
+		Zones:  []Zone{{Name: "GMT"}, {Name: "BST", Offset: time.Hour, IsDST: true}},
+		Extend: "GMT0BST,M3.5.0/1,M10.5.0",
+	}
+	ny := Template{
+		Zones:  []Zone{{Name: "EST", Offset: -5 * time.Hour}, {Name: "EDT", Offset: -4 * time.Hour, IsDST: true}},
+		Extend: "EST5EDT,M3.2.0,M11.1.0",
+	}
+	kolkata := Template{Zones: []Zone{{Name: "IST", Offset: 5*time.Hour + 30*time.Minute}}, Extend: "IST-5:30"}
+	templates := map[string]*Template{
+		"Europe/Bratislava":    &cet,
+		"Europe/Amsterdam":     &cet,
+		"Europe/London":        &london,
+		"America/New_York":     &ny,
+		"America/Indiana/Knox": &ny,
+		"Asia/Kolkata":         &kolkata,
+		"UTC":                  {Zones: []Zone{{Name: "UTC"}}},
+	}
+
+	summer := time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)
+	expected := []PickerGroup{
+		{Region: "America", Zones: []PickerZone{
+			{Name: "America/Indiana/Knox", Label: "(UTC-04:00) Knox", Offset: -4 * 3600},
+			{Name: "America/New_York", Label: "(UTC-04:00) New York", Offset: -4 * 3600},
+		}},
+		{Region: "Asia", Zones: []PickerZone{
+			{Name: "Asia/Kolkata", Label: "(UTC+05:30) Kolkata", Offset: 5*3600 + 30*60},
+		}},
+		{Region: "Europe", Zones: []PickerZone{
+			{Name: "Europe/London", Label: "(UTC+01:00) London", Offset: 3600},
+			{Name: "Europe/Amsterdam", Label: "(UTC+02:00) Amsterdam", Offset: 7200},
+			{Name: "Europe/Bratislava", Label: "(UTC+02:00) Bratislava", Offset: 7200},
+		}},
+	}
+	groups := Picker(templates, summer)
+	if !reflect.DeepEqual(groups, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, groups)
+	}
+
+	winter := Picker(templates, time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	if label := winter[2].Zones[0].Label; label != "(UTC+00:00) London" {
+		t.Fatalf("unexpected label %q", label)
+	}
+
+	data, err := json.Marshal(groups[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expectedJSON = `{"region":"Asia","zones":[{"name":"Asia/Kolkata","label":"(UTC+05:30) Kolkata","offset":19800}]}`
+	if string(data) != expectedJSON {
+		t.Fatalf("unexpected JSON %s", data)
+	}
+}
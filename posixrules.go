@@ -0,0 +1,129 @@
+package timezones
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PosixRules returns TZif data for a "posixrules" file, which older tzcode and glibc use for the DST rules
+// of TZ strings that don't specify any, e.g. "EST5EDT", see ApplyPosixRules.
+// The data contains the transitions of rule, a TZ string with DST, in the years from up to, but not
+// including, to, and rule in the footer.
+// The data has no standard/wall and UT/local indicators, so readers take the transitions as wall clock
+// times and shift them to the offsets of the TZ string being completed, like America/New_York, the usual
+// posixrules file.
+func PosixRules(rule string, from, to int) ([]byte, error) {
+	r, err := parseTZRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	if !r.hasDST {
+		return nil, fmt.Errorf("%w: %q: posixrules need daylight saving time rules", ErrInvalidExtend, rule)
+	}
+	template := Template{Zones: []Zone{r.std, r.dst}, Extend: rule}
+	for year := from; year < to; year++ {
+		start, end := r.transitions(year)
+		if end < start {
+			template.Changes = append(template.Changes,
+				Change{Start: time.Unix(end, 0).UTC(), ZoneIndex: 0},
+				Change{Start: time.Unix(start, 0).UTC(), ZoneIndex: 1})
+		} else {
+			template.Changes = append(template.Changes,
+				Change{Start: time.Unix(start, 0).UTC(), ZoneIndex: 1},
+				Change{Start: time.Unix(end, 0).UTC(), ZoneIndex: 0})
+		}
+	}
+	if len(template.Changes) > 0 && template.Changes[0].ZoneIndex == 0 {
+		template.FirstZoneIndex = 1
+	}
+	e := Encoder{Indicators: NoIndicators}
+	return e.Encode(template)
+}
+
+// ApplyPosixRules returns a template for extend, a TZ string with DST but without DST rules,
+// e.g. "EST5EDT", using the transitions of the posixrules TZif data the way older tzcode and glibc do.
+// Each transition is shifted by the difference between the offsets of extend and the offsets of posixrules,
+// unless its indicators say it is in universal time; transitions in standard time are shifted by
+// the difference of standard time offsets.
+// The template has no Extend, so the zone of the last transition applies after it.
+//
+// Go's time package does not use posixrules; it applies the default US rules of tzcode to such TZ strings.
+func ApplyPosixRules(extend string, posixrules []byte) (Template, error) {
+	if strings.ContainsAny(extend, ",;") {
+		return Template{}, fmt.Errorf("%w: %q already has daylight saving time rules", ErrInvalidExtend, extend)
+	}
+	r, err := parseTZRule(extend)
+	if err != nil {
+		return Template{}, err
+	}
+	if !r.hasDST {
+		return Template{}, fmt.Errorf("%w: %q has no daylight saving time", ErrInvalidExtend, extend)
+	}
+
+	h, rest, err := parseHeaders(posixrules)
+	if err != nil {
+		return Template{}, err
+	}
+	times, rest := rest[:int(h.timecnt)*h.tsize], rest[int(h.timecnt)*h.tsize:]
+	types, rest := rest[:h.timecnt], rest[h.timecnt:]
+	ltt, rest := rest[:h.typecnt*6], rest[h.typecnt*6:]
+	rest = rest[int(h.charcnt)+int(h.leapcnt)*(h.tsize+4):]
+	isstd, isut := rest[:h.isstdcnt], rest[h.isstdcnt:h.isstdcnt+h.isutcnt]
+	offset := func(typ int) int64 {
+		return int64(int32(uint32(ltt[typ*6])<<24 | uint32(ltt[typ*6+1])<<16 | uint32(ltt[typ*6+2])<<8 | uint32(ltt[typ*6+3])))
+	}
+	isDST := func(typ int) bool {
+		return ltt[typ*6+4] != 0
+	}
+
+	// Offsets are in seconds east of UTC, unlike in tzcode.
+	stdOffset, dstOffset := offsetSeconds(r.std), offsetSeconds(r.dst)
+	var theirStd, theirDST int64
+	foundStd, foundDST := false, false
+	for typ := 0; typ < int(h.typecnt); typ++ {
+		if isDST(typ) && !foundDST {
+			theirDST, foundDST = offset(typ), true
+		}
+		if !isDST(typ) && !foundStd {
+			theirStd, foundStd = offset(typ), true
+		}
+	}
+
+	template := Template{Zones: []Zone{r.std, r.dst}}
+	dst := false
+	for i := 0; i < int(h.timecnt); i++ {
+		typ := int(types[i])
+		if typ >= int(h.typecnt) {
+			return Template{}, ErrInvalid
+		}
+		var sec int64
+		if h.tsize == 4 {
+			sec = int64(int32(uint32(times[i*4])<<24 | uint32(times[i*4+1])<<16 | uint32(times[i*4+2])<<8 | uint32(times[i*4+3])))
+		} else {
+			for _, b := range times[i*8 : i*8+8] {
+				sec = sec<<8 | int64(b)
+			}
+		}
+		switch {
+		case typ < len(isut) && isut[typ] != 0:
+			// Universal time, no adjustment.
+		case dst && !(typ < len(isstd) && isstd[typ] != 0):
+			sec -= dstOffset - theirDST
+		default:
+			sec -= stdOffset - theirStd
+		}
+		if isDST(typ) {
+			theirDST = offset(typ)
+		} else {
+			theirStd = offset(typ)
+		}
+		dst = isDST(typ)
+		zoneIndex := 0
+		if dst {
+			zoneIndex = 1
+		}
+		template.Changes = append(template.Changes, Change{Start: time.Unix(sec, 0).UTC(), ZoneIndex: zoneIndex})
+	}
+	return template, nil
+}
@@ -0,0 +1,77 @@
+package timezones
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPosixRules(t *testing.T) {
+	data, err := PosixRules("EST5EDT,M3.2.0,M11.1.0", 2020, 2023)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := QuickInfo(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.TransitionCount != 6 || info.IsStdCount != 0 || info.IsUTCount != 0 {
+		t.Fatalf("unexpected info %+v", info)
+	}
+	if _, err := time.LoadLocationFromTZData("posixrules", data); err != nil {
+		t.Fatal(err)
+	}
+
+	template, err := ApplyPosixRules("CST6CDT", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(template.Changes) != 6 {
+		t.Fatalf("expected 6 changes, got %d", len(template.Changes))
+	}
+	// The transitions happen at 2:00 local time in Chicago.
+	if start := template.Changes[0].Start; !start.Equal(time.Date(2020, time.March, 8, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected start of DST %v", start)
+	}
+	expected := Template{Zones: []Zone{{Name: "CST", Offset: -6 * time.Hour}}, Extend: "CST6CDT,M3.2.0,M11.1.0"}
+	from := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, time.December, 31, 0, 0, 0, 0, time.UTC)
+	if diffs := expected.Diff(&template, from, to); len(diffs) != 0 {
+		t.Fatalf("unexpected differences %+v", diffs)
+	}
+}
+
+func TestPosixRules_SouthernHemisphere(t *testing.T) {
+	data, err := PosixRules("AEST-10AEDT,M10.1.0,M4.1.0/3", 2020, 2022)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template, err := LoadTZData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Template{Extend: "AEST-10AEDT,M10.1.0,M4.1.0/3"}
+	from := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if diffs := expected.Diff(template, from, to); len(diffs) != 0 {
+		t.Fatalf("unexpected differences %+v", diffs)
+	}
+}
+
+func TestPosixRules_Errors(t *testing.T) {
+	if _, err := PosixRules("UTC0", 2020, 2021); !errors.Is(err, ErrInvalidExtend) {
+		t.Fatalf("expected ErrInvalidExtend, got %v", err)
+	}
+	data, err := PosixRules("EST5EDT", 2020, 2021)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, extend := range []string{"CST6CDT,M3.2.0,M11.1.0", "CST6", "not a rule"} {
+		if _, err := ApplyPosixRules(extend, data); !errors.Is(err, ErrInvalidExtend) {
+			t.Fatalf("%q: expected ErrInvalidExtend, got %v", extend, err)
+		}
+	}
+	if _, err := ApplyPosixRules("CST6CDT", []byte("garbage")); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid, got %v", err)
+	}
+}
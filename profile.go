@@ -0,0 +1,62 @@
+package timezones
+
+import "fmt"
+
+// Profile is a named set of validation options for Encoder and Decoder.
+type Profile int
+
+const (
+	// GoCompatible accepts what Go's time package accepts and produces data that Go reads the same way.
+	// Its Encoder is the zero Encoder used by TZData. Its Decoder ignores standard/wall and UT/local
+	// indicators like Go does, so it decodes the files of system zoneinfo directories, which the zero
+	// Decoder and LoadTZData reject with ErrUnsupportedIndicators.
+	GoCompatible Profile = iota
+
+	// Strict rejects data and templates that are valid but questionable, for example designations
+	// that don't conform to POSIX or a last change that disagrees with Extend.
+	// Data produced by TZData may share designations and thus be rejected by a Strict Decoder.
+	Strict
+
+	// Lenient accepts as much as can be represented, for example by rounding sub-second offsets,
+	// decoding data without local time types or ignoring indicators like GoCompatible.
+	Lenient
+)
+
+// Encoder returns an Encoder configured according to the profile.
+// The result can be further customized, for example by setting Warn.
+func (p Profile) Encoder() Encoder {
+	switch p {
+	case Strict:
+		return Encoder{StrictDesignations: true, StrictContinuity: true}
+	case Lenient:
+		return Encoder{RoundOffsets: true}
+	default:
+		return Encoder{}
+	}
+}
+
+// Decoder returns a Decoder configured according to the profile.
+// The result can be further customized, for example by setting limits.
+func (p Profile) Decoder() Decoder {
+	switch p {
+	case Strict:
+		return Decoder{StrictDesignationIndices: true}
+	case Lenient:
+		return Decoder{AllowFooterOnly: true, IgnoreIndicators: true}
+	default:
+		return Decoder{IgnoreIndicators: true}
+	}
+}
+
+func (p Profile) String() string {
+	switch p {
+	case GoCompatible:
+		return "GoCompatible"
+	case Strict:
+		return "Strict"
+	case Lenient:
+		return "Lenient"
+	default:
+		return fmt.Sprintf("Profile(%d)", int(p))
+	}
+}
@@ -0,0 +1,70 @@
+package timezones
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestProfile_Encoder(t *testing.T) {
+	template := Template{
+		Zones: []Zone{{Name: "x", Offset: time.Hour + time.Millisecond}},
+	}
+	goCompatible, lenient := GoCompatible.Encoder(), Lenient.Encoder()
+	if _, err := goCompatible.Encode(template); !errors.Is(err, ErrInvalidOffset) {
+		t.Fatalf("expected ErrInvalidOffset, got %v", err)
+	}
+	if _, err := lenient.Encode(template); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	template.Zones[0].Offset = time.Hour
+	if _, err := goCompatible.Encode(template); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e := Strict.Encoder()
+	if _, err := e.Encode(template); !errors.Is(err, ErrInvalidDesignation) {
+		t.Fatalf("expected ErrInvalidDesignation, got %v", err)
+	}
+}
+
+func TestProfile_Decoder(t *testing.T) {
+	footerOnly := rawTZif{version: '2', footer: "\nUTC0\n"}
+	for _, p := range []Profile{GoCompatible, Strict} {
+		d := p.Decoder()
+		if _, err := d.Decode(footerOnly.bytes()); !errors.Is(err, ErrInvalid) {
+			t.Fatalf("%v: expected ErrInvalid, got %v", p, err)
+		}
+	}
+	d := Lenient.Decoder()
+	if _, err := d.Decode(footerOnly.bytes()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// zic writes 0 indicators for most zones.
+	wall := rawTZif{
+		version: '2',
+		ltt:     []rawLocalTimeType{{3600, 0, 0}},
+		chars:   "CET\x00",
+		isstd:   []byte{0},
+		isut:    []byte{0},
+		footer:  "\nCET-1\n",
+	}
+	for _, p := range []Profile{GoCompatible, Lenient} {
+		d := p.Decoder()
+		if _, err := d.Decode(wall.bytes()); err != nil {
+			t.Fatalf("%v: unexpected error: %v", p, err)
+		}
+	}
+	d = Strict.Decoder()
+	if _, err := d.Decode(wall.bytes()); !errors.Is(err, ErrUnsupportedIndicators) {
+		t.Fatalf("expected ErrUnsupportedIndicators, got %v", err)
+	}
+}
+
+func TestProfile_String(t *testing.T) {
+	for p, s := range map[Profile]string{GoCompatible: "GoCompatible", Strict: "Strict", Lenient: "Lenient", 7: "Profile(7)"} {
+		if p.String() != s {
+			t.Fatalf("expected %q, got %q", s, p.String())
+		}
+	}
+}
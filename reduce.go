@@ -0,0 +1,139 @@
+package timezones
+
+import "time"
+
+// ReduceOptions configure Template.Reduce. The zero value reduces nothing.
+type ReduceOptions struct {
+	// Before drops the changes before it; the zone in effect at Before then applies since the beginning of time.
+	// Zero keeps all changes.
+	Before time.Time
+
+	// Tolerance drops changes to a zone whose offset differs by at most Tolerance from the offset of the zone
+	// in effect before the change, as long as both are standard time or both are DST, e.g. the sub-minute
+	// differences between local mean time and the first standard time of some zones.
+	// The designation of the dropped zone is lost.
+	// Zero keeps all changes.
+	Tolerance time.Duration
+}
+
+// ReduceReport describes the error a lossy reduction introduced, see Template.Reduce.
+type ReduceReport struct {
+	// DroppedChanges is the number of changes removed.
+	DroppedChanges int
+
+	// MaxError is the largest absolute difference of the offset of the reduced template from the original.
+	MaxError time.Duration
+
+	// Until is the end of the last interval in which the reduced template uses a different zone than
+	// the original, or zero if they behave the same.
+	Until time.Time
+}
+
+// Reduce returns a copy of the template with fewer changes, trading accuracy of old data for size,
+// along with a report of the introduced error.
+// Zones that are no longer used are removed. Extend is kept.
+//
+// Use Template.Diff to find the exact intervals in which the result differs from the template.
+func (t *Template) Reduce(options ReduceOptions) (Template, ReduceReport) {
+	var report ReduceReport
+	zone := func(i int) Zone {
+		if i < 0 || i >= len(t.Zones) {
+			return Zone{}
+		}
+		return t.Zones[i]
+	}
+	record := func(original, reduced int, until time.Time) {
+		if original == reduced {
+			return
+		}
+		a, b := zone(original), zone(reduced)
+		diff := a.Offset.Round(time.Second) - b.Offset.Round(time.Second)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > report.MaxError {
+			report.MaxError = diff
+		}
+		if !sameZone(a, b) && until.After(report.Until) {
+			report.Until = until
+		}
+	}
+
+	changes := t.Changes
+	first := t.FirstZoneIndex
+	if !options.Before.IsZero() {
+		n := 0
+		for n < len(changes) && changes[n].Start.Before(options.Before) {
+			n++
+		}
+		if n > 0 {
+			newFirst := changes[n-1].ZoneIndex
+			record(first, newFirst, changes[0].Start)
+			for i := 0; i < n-1; i++ {
+				record(changes[i].ZoneIndex, newFirst, changes[i+1].Start)
+			}
+			first = newFirst
+			changes = changes[n:]
+		}
+	}
+
+	kept := make([]Change, 0, len(changes))
+	current := first
+	for i, c := range changes {
+		a, b := zone(current), zone(c.ZoneIndex)
+		diff := b.Offset - a.Offset
+		if options.Tolerance > 0 && a.IsDST == b.IsDST && diff <= options.Tolerance && -diff <= options.Tolerance {
+			until := time.Time{}
+			if i+1 < len(changes) {
+				until = changes[i+1].Start
+			}
+			record(c.ZoneIndex, current, until)
+			continue
+		}
+		kept = append(kept, c)
+		current = c.ZoneIndex
+	}
+	report.DroppedChanges = len(t.Changes) - len(kept)
+
+	result := *t
+	result.FirstZoneIndex = first
+	result.Changes = kept
+	result.pruneZones()
+	return result, report
+}
+
+// pruneZones removes zones not used by FirstZoneIndex nor Changes, remapping the indices.
+// Zones and Changes are copied before modification.
+func (t *Template) pruneZones() {
+	used := make([]bool, len(t.Zones))
+	mark := func(i int) {
+		if i >= 0 && i < len(used) {
+			used[i] = true
+		}
+	}
+	mark(t.FirstZoneIndex)
+	for _, c := range t.Changes {
+		mark(c.ZoneIndex)
+	}
+	remap := make([]int, len(t.Zones))
+	zones := make([]Zone, 0, len(t.Zones))
+	for i, z := range t.Zones {
+		remap[i] = len(zones)
+		if used[i] {
+			zones = append(zones, z)
+		}
+	}
+	index := func(i int) int {
+		if i >= 0 && i < len(remap) {
+			return remap[i]
+		}
+		return i
+	}
+	changes := make([]Change, len(t.Changes))
+	for i, c := range t.Changes {
+		changes[i] = Change{Start: c.Start, ZoneIndex: index(c.ZoneIndex)}
+	}
+	t.FirstZoneIndex = index(t.FirstZoneIndex)
+	t.Zones = zones
+	t.Changes = changes
+}
@@ -0,0 +1,82 @@
+package timezones
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func reduceTestTemplate() Template {
+	utc := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	return Template{
+		Zones: []Zone{
+			{Name: "LMT", Offset: 57*time.Minute + 44*time.Second},
+			{Name: "PMT", Offset: 57*time.Minute + 44*time.Second},
+			{Name: "CET", Offset: time.Hour},
+			{Name: "CEST", Offset: 2 * time.Hour, IsDST: true},
+		},
+		Changes: []Change{
+			{Start: utc(1850, time.January, 1), ZoneIndex: 1},
+			{Start: utc(1891, time.October, 1), ZoneIndex: 2},
+			{Start: utc(1940, time.April, 1), ZoneIndex: 3},
+			{Start: utc(1942, time.November, 2), ZoneIndex: 2},
+			{Start: utc(1979, time.April, 1), ZoneIndex: 3},
+			{Start: utc(1979, time.September, 30), ZoneIndex: 2},
+		},
+		Extend: "CET-1CEST,M3.5.0,M10.5.0/3",
+	}
+}
+
+func TestTemplate_Reduce_Tolerance(t *testing.T) {
+	template := reduceTestTemplate()
+	reduced, report := template.Reduce(ReduceOptions{Tolerance: time.Minute})
+	expectedReport := ReduceReport{DroppedChanges: 1, Until: template.Changes[1].Start}
+	if report != expectedReport {
+		t.Fatalf("expected %+v, got %+v", expectedReport, report)
+	}
+	if len(reduced.Zones) != 3 || reduced.Zones[0].Name != "LMT" || reduced.Zones[1].Name != "CET" {
+		t.Fatalf("unexpected zones %+v", reduced.Zones)
+	}
+	diffs := template.Diff(&reduced, time.Date(1800, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC))
+	if len(diffs) != 1 || diffs[0].Zone.Name != "PMT" || diffs[0].Other.Name != "LMT" {
+		t.Fatalf("unexpected differences %+v", diffs)
+	}
+	if err := Validate(reduced); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTemplate_Reduce_Before(t *testing.T) {
+	template := reduceTestTemplate()
+	reduced, report := template.Reduce(ReduceOptions{Before: time.Date(1945, time.January, 1, 0, 0, 0, 0, time.UTC)})
+	expectedReport := ReduceReport{DroppedChanges: 4, MaxError: time.Hour, Until: template.Changes[3].Start}
+	if report != expectedReport {
+		t.Fatalf("expected %+v, got %+v", expectedReport, report)
+	}
+	expected := Template{
+		Zones:   template.Zones[2:],
+		Changes: []Change{{Start: template.Changes[4].Start, ZoneIndex: 1}, {Start: template.Changes[5].Start, ZoneIndex: 0}},
+		Extend:  template.Extend,
+	}
+	if !reflect.DeepEqual(reduced, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, reduced)
+	}
+	// The original is not modified.
+	if !reflect.DeepEqual(template, reduceTestTemplate()) {
+		t.Fatal("template modified")
+	}
+}
+
+func TestTemplate_Reduce_Nothing(t *testing.T) {
+	template := reduceTestTemplate()
+	reduced, report := template.Reduce(ReduceOptions{})
+	if report != (ReduceReport{}) {
+		t.Fatalf("expected an empty report, got %+v", report)
+	}
+	if !reflect.DeepEqual(reduced, template) {
+		t.Fatalf("expected %+v, got %+v", template, reduced)
+	}
+}
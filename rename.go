@@ -0,0 +1,61 @@
+package timezones
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ApplyRenames renames zones in templates, e.g. loaded by LoadAll, according to renames, which maps old
+// names to new ones, e.g. "Europe/Kiev" to "Europe/Kyiv".
+// The renamed templates are copies with Name set to the new name; templates is not modified.
+//
+// The old names are kept in the result as aliases referring to the renamed templates, unless they are
+// the new names of other zones, so that data referencing them keeps resolving, like the backward links
+// of the tz database.
+// The returned aliases map each old name to the new one.
+//
+// It returns ErrUnknownZone if an old name is not in templates and ErrNameConflict if a new name is
+// already used by a template that is not renamed itself or is the target of another rename.
+func ApplyRenames(templates map[string]*Template, renames map[string]string) (result map[string]*Template, aliases map[string]string, err error) {
+	olds := make([]string, 0, len(renames))
+	for old := range renames {
+		olds = append(olds, old)
+	}
+	// Sort, so that the reported error does not depend on the map order.
+	sort.Strings(olds)
+	targets := make(map[string]string, len(renames))
+	for _, old := range olds {
+		name := renames[old]
+		if templates[old] == nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrUnknownZone, old)
+		}
+		if other, ok := targets[name]; ok {
+			return nil, nil, fmt.Errorf("%w: %s and %s renamed to %s", ErrNameConflict, other, old, name)
+		}
+		if _, renamed := renames[name]; templates[name] != nil && !renamed {
+			return nil, nil, fmt.Errorf("%w: %s renamed to %s", ErrNameConflict, old, name)
+		}
+		targets[name] = old
+	}
+
+	result = make(map[string]*Template, len(templates)+len(renames))
+	for name, t := range templates {
+		if _, renamed := renames[name]; !renamed {
+			result[name] = t
+		}
+	}
+	aliases = make(map[string]string, len(renames))
+	for _, old := range olds {
+		name := renames[old]
+		renamed := *templates[old]
+		renamed.Name = name
+		result[name] = &renamed
+		aliases[old] = name
+	}
+	for old, name := range aliases {
+		if result[old] == nil {
+			result[old] = result[name]
+		}
+	}
+	return result, aliases, nil
+}
@@ -0,0 +1,59 @@
+package timezones
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestApplyRenames(t *testing.T) {
+	kiev := &Template{Name: "Europe/Kiev", Zones: []Zone{{Name: "EET", Offset: 2 * time.Hour}}}
+	prague := &Template{Name: "Europe/Prague", Zones: []Zone{{Name: "CET", Offset: time.Hour}}}
+	templates := map[string]*Template{"Europe/Kiev": kiev, "Europe/Prague": prague}
+
+	result, aliases, err := ApplyRenames(templates, map[string]string{"Europe/Kiev": "Europe/Kyiv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := map[string]string{"Europe/Kiev": "Europe/Kyiv"}; !reflect.DeepEqual(aliases, expected) {
+		t.Fatalf("expected aliases %v, got %v", expected, aliases)
+	}
+	if len(result) != 3 || result["Europe/Prague"] != prague {
+		t.Fatalf("unexpected result %v", result)
+	}
+	kyiv := result["Europe/Kyiv"]
+	if kyiv == nil || kyiv.Name != "Europe/Kyiv" || !reflect.DeepEqual(kyiv.Zones, kiev.Zones) {
+		t.Fatalf("unexpected renamed template %+v", kyiv)
+	}
+	if result["Europe/Kiev"] != kyiv {
+		t.Fatal("expected the old name to alias the renamed template")
+	}
+	if kiev.Name != "Europe/Kiev" || templates["Europe/Kiev"] != kiev || len(templates) != 2 {
+		t.Fatal("templates modified")
+	}
+}
+
+func TestApplyRenames_Errors(t *testing.T) {
+	templates := map[string]*Template{
+		"A": {Name: "A"},
+		"B": {Name: "B"},
+	}
+	if _, _, err := ApplyRenames(templates, map[string]string{"C": "D"}); !errors.Is(err, ErrUnknownZone) {
+		t.Fatalf("expected ErrUnknownZone, got %v", err)
+	}
+	if _, _, err := ApplyRenames(templates, map[string]string{"A": "B"}); !errors.Is(err, ErrNameConflict) {
+		t.Fatalf("expected ErrNameConflict, got %v", err)
+	}
+	if _, _, err := ApplyRenames(templates, map[string]string{"A": "C", "B": "C"}); !errors.Is(err, ErrNameConflict) {
+		t.Fatalf("expected ErrNameConflict, got %v", err)
+	}
+	// Swapping names is fine.
+	result, _, err := ApplyRenames(templates, map[string]string{"A": "B", "B": "A"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result["A"].Name != "A" || result["B"].Name != "B" || result["A"] == templates["B"] {
+		t.Fatalf("unexpected result %v", result)
+	}
+}
@@ -0,0 +1,490 @@
+package timezones
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// tzRule is a parsed TZ string as used in Template.Extend, see RFC 8536, section 3.3.
+// It interprets the string the same way as Go's time package does.
+type tzRule struct {
+	std Zone
+	dst Zone
+	// hasDST reports whether the rule has daylight saving time.
+	// If false, std applies at all times and dst, start and end are unused.
+	hasDST bool
+	// start is when dst starts, in local standard time.
+	start ruleDate
+	// end is when dst ends, in local daylight saving time.
+	end ruleDate
+}
+
+type ruleKind int
+
+const (
+	// ruleJulian is Jn, day n in range 1-365 ignoring February 29.
+	ruleJulian ruleKind = iota
+	// ruleDOY is n, zero-based day of year in range 0-365 counting February 29.
+	ruleDOY
+	// ruleMonthWeekDay is Mm.w.d, day d of week w of month m.
+	ruleMonthWeekDay
+)
+
+// ruleDate is a date and time of a transition in a TZ string.
+type ruleDate struct {
+	kind ruleKind
+	day  int
+	week int
+	mon  int
+	// time of day in seconds, can be negative or exceed 24 hours.
+	time int
+}
+
+// maxCachedRules bounds the number of TZ strings cachedTZRule remembers.
+// The tz database has a few hundred distinct ones.
+const maxCachedRules = 1024
+
+// parsedRule is the result of parsing a TZ string.
+type parsedRule struct {
+	rule tzRule
+	err  error
+}
+
+// ruleCache holds parsed TZ strings keyed by the string, see cachedTZRule.
+var ruleCache = struct {
+	sync.RWMutex
+	rules map[string]parsedRule
+}{rules: make(map[string]parsedRule)}
+
+// cachedTZRule is like parseTZRule, but remembers the result, so that queries of templates don't parse
+// their Extend again on every call.
+// The cache is keyed by the TZ string rather than stored in templates, so templates remain plain values
+// that can be copied and compared while other goroutines query them.
+func cachedTZRule(s string) (tzRule, error) {
+	ruleCache.RLock()
+	p, ok := ruleCache.rules[s]
+	ruleCache.RUnlock()
+	if ok {
+		return p.rule, p.err
+	}
+	rule, err := parseTZRule(s)
+	ruleCache.Lock()
+	if len(ruleCache.rules) >= maxCachedRules {
+		// Start over rather than keep track of usage, arbitrary strings from untrusted input
+		// should not grow the cache without bounds.
+		ruleCache.rules = make(map[string]parsedRule)
+	}
+	ruleCache.rules[s] = parsedRule{rule: rule, err: err}
+	ruleCache.Unlock()
+	return rule, err
+}
+
+// version3Feature describes the first part of the rule that needs TZif version 3, RFC 8536, section 3.3.1.
+// It returns an empty string if the rule can be stored in version 2 data.
+func (r *tzRule) version3Feature() string {
+	if !r.hasDST {
+		return ""
+	}
+	if r.allYearDST() {
+		return "daylight saving time all year"
+	}
+	for _, d := range []struct {
+		name string
+		date ruleDate
+	}{{"start", r.start}, {"end", r.end}} {
+		if d.date.time < 0 || d.date.time > 24*3600 {
+			return fmt.Sprintf("daylight saving time %s hour %d is outside 0 to 24", d.name, d.date.time/3600)
+		}
+	}
+	return ""
+}
+
+// allYearDST reports whether the rule is interpreted as daylight saving time all year, which RFC 8536 defines
+// as DST starting on January 1 at 00:00 and ending on December 31 at 24:00 plus the difference between
+// daylight saving and standard time.
+func (r *tzRule) allYearDST() bool {
+	startsJanuary1 := r.start.time == 0 &&
+		(r.start.kind == ruleJulian && r.start.day == 1 || r.start.kind == ruleDOY && r.start.day == 0)
+	shift := int((r.dst.Offset - r.std.Offset) / time.Second)
+	endsDecember31 := r.end.kind == ruleJulian && r.end.day == 365 && r.end.time == 24*3600+shift
+	return r.hasDST && startsJanuary1 && endsDecember31
+}
+
+// parseTZRule parses a TZ string.
+func parseTZRule(s string) (tzRule, error) {
+	var r tzRule
+	invalid := func(reason string) (tzRule, error) {
+		return tzRule{}, fmt.Errorf("%w: %q: %s", ErrInvalidExtend, s, reason)
+	}
+	stdName, rest, ok := parseRuleName(s)
+	if !ok {
+		return invalid("invalid standard time designation")
+	}
+	stdOffset, rest, ok := parseRuleOffset(rest)
+	if !ok {
+		return invalid("invalid standard time offset")
+	}
+	// POSIX offsets are positive west of UTC, ours are positive east of UTC.
+	r.std = Zone{Name: stdName, Offset: time.Duration(-stdOffset) * time.Second}
+	if rest == "" || rest[0] == ',' {
+		// No daylight saving time, Go ignores the rest of the string in this case.
+		return r, nil
+	}
+
+	dstName, rest, ok := parseRuleName(rest)
+	if !ok {
+		return invalid("invalid daylight saving time designation")
+	}
+	r.hasDST = true
+	r.dst = Zone{Name: dstName, IsDST: true}
+	if rest == "" || rest[0] == ',' {
+		r.dst.Offset = r.std.Offset + time.Hour
+	} else {
+		var dstOffset int
+		dstOffset, rest, ok = parseRuleOffset(rest)
+		if !ok {
+			return invalid("invalid daylight saving time offset")
+		}
+		r.dst.Offset = time.Duration(-dstOffset) * time.Second
+	}
+
+	if rest == "" {
+		// Default DST rules per tzcode.
+		rest = ",M3.2.0,M11.1.0"
+	}
+	// POSIX does not mention ';' here, but tzcode accepts it.
+	if rest[0] != ',' && rest[0] != ';' {
+		return invalid("expected start of daylight saving time rule")
+	}
+	r.start, rest, ok = parseRuleDate(rest[1:])
+	if !ok || rest == "" || rest[0] != ',' {
+		return invalid("invalid daylight saving time start rule")
+	}
+	r.end, rest, ok = parseRuleDate(rest[1:])
+	if !ok || rest != "" {
+		return invalid("invalid daylight saving time end rule")
+	}
+	return r, nil
+}
+
+// parseRuleName parses a designation at the start of s.
+func parseRuleName(s string) (string, string, bool) {
+	if len(s) == 0 {
+		return "", "", false
+	}
+	if s[0] == '<' {
+		for i := 1; i < len(s); i++ {
+			if s[i] == '>' {
+				return s[1:i], s[i+1:], true
+			}
+		}
+		return "", "", false
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case '0' <= c && c <= '9', c == ',', c == '-', c == '+':
+			if i < 3 {
+				return "", "", false
+			}
+			return s[:i], s[i:], true
+		}
+	}
+	if len(s) < 3 {
+		return "", "", false
+	}
+	return s, "", true
+}
+
+// parseRuleOffset parses [+-]hh[:mm[:ss]] at the start of s and returns it in seconds.
+func parseRuleOffset(s string) (int, string, bool) {
+	if len(s) == 0 {
+		return 0, "", false
+	}
+	neg := false
+	if s[0] == '+' {
+		s = s[1:]
+	} else if s[0] == '-' {
+		s = s[1:]
+		neg = true
+	}
+
+	// The tzdata code permits values up to 24 * 7 here, although POSIX does not.
+	var hours int
+	hours, s, ok := parseRuleNum(s, 0, 24*7)
+	if !ok {
+		return 0, "", false
+	}
+	off := hours * 60 * 60
+	if len(s) == 0 || s[0] != ':' {
+		if neg {
+			off = -off
+		}
+		return off, s, true
+	}
+
+	var mins int
+	mins, s, ok = parseRuleNum(s[1:], 0, 59)
+	if !ok {
+		return 0, "", false
+	}
+	off += mins * 60
+	if len(s) == 0 || s[0] != ':' {
+		if neg {
+			off = -off
+		}
+		return off, s, true
+	}
+
+	var secs int
+	secs, s, ok = parseRuleNum(s[1:], 0, 59)
+	if !ok {
+		return 0, "", false
+	}
+	off += secs
+
+	if neg {
+		off = -off
+	}
+	return off, s, true
+}
+
+// parseRuleDate parses a rule date and optional time at the start of s.
+func parseRuleDate(s string) (ruleDate, string, bool) {
+	var r ruleDate
+	if len(s) < 2 {
+		return ruleDate{}, "", false
+	}
+	var ok bool
+	switch {
+	case s[0] == 'J':
+		var jday int
+		jday, s, ok = parseRuleNum(s[1:], 1, 365)
+		if !ok {
+			return ruleDate{}, "", false
+		}
+		r.kind = ruleJulian
+		r.day = jday
+	case s[0] == 'M':
+		var mon int
+		mon, s, ok = parseRuleNum(s[1:], 1, 12)
+		if !ok || len(s) == 0 || s[0] != '.' {
+			return ruleDate{}, "", false
+		}
+		var week int
+		week, s, ok = parseRuleNum(s[1:], 1, 5)
+		if !ok || len(s) == 0 || s[0] != '.' {
+			return ruleDate{}, "", false
+		}
+		var day int
+		day, s, ok = parseRuleNum(s[1:], 0, 6)
+		if !ok {
+			return ruleDate{}, "", false
+		}
+		r.kind = ruleMonthWeekDay
+		r.day = day
+		r.week = week
+		r.mon = mon
+	case '0' <= s[0] && s[0] <= '9':
+		var day int
+		day, s, ok = parseRuleNum(s, 0, 365)
+		if !ok {
+			return ruleDate{}, "", false
+		}
+		r.kind = ruleDOY
+		r.day = day
+	default:
+		return ruleDate{}, "", false
+	}
+
+	if len(s) == 0 || s[0] != '/' {
+		r.time = 2 * 60 * 60 // 2am is the default
+		return r, s, true
+	}
+
+	offset, s, ok := parseRuleOffset(s[1:])
+	if !ok {
+		return ruleDate{}, "", false
+	}
+	r.time = offset
+	return r, s, true
+}
+
+// parseRuleNum parses a number in range min to max at the start of s.
+func parseRuleNum(s string, min, max int) (int, string, bool) {
+	if len(s) == 0 {
+		return 0, "", false
+	}
+	num := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			if i == 0 || num < min {
+				return 0, "", false
+			}
+			return num, s[i:], true
+		}
+		num = num*10 + int(c) - '0'
+		if num > max {
+			return 0, "", false
+		}
+	}
+	if num < min {
+		return 0, "", false
+	}
+	return num, "", true
+}
+
+// String formats the rule in the canonical form used by zic: designations are quoted only if needed,
+// offsets and times omit zero minutes and seconds, and the default daylight saving time offset
+// and transition time of 02:00 are omitted.
+func (r *tzRule) String() string {
+	b := appendRuleName(nil, r.std.Name)
+	b = appendRuleOffset(b, -int(r.std.Offset/time.Second))
+	if !r.hasDST {
+		return string(b)
+	}
+	b = appendRuleName(b, r.dst.Name)
+	if r.dst.Offset != r.std.Offset+time.Hour {
+		b = appendRuleOffset(b, -int(r.dst.Offset/time.Second))
+	}
+	b = r.start.appendTo(append(b, ','))
+	b = r.end.appendTo(append(b, ','))
+	return string(b)
+}
+
+// appendRuleName appends the designation, quoted in angle brackets unless it consists of letters only.
+func appendRuleName(b []byte, name string) []byte {
+	for i := 0; i < len(name); i++ {
+		if c := name[i]; !('A' <= c && c <= 'Z' || 'a' <= c && c <= 'z') {
+			b = append(b, '<')
+			b = append(b, name...)
+			return append(b, '>')
+		}
+	}
+	return append(b, name...)
+}
+
+// appendRuleOffset appends the offset in seconds as [-]h[:mm[:ss]].
+func appendRuleOffset(b []byte, offset int) []byte {
+	if offset < 0 {
+		b = append(b, '-')
+		offset = -offset
+	}
+	b = strconv.AppendInt(b, int64(offset/3600), 10)
+	mins, secs := offset/60%60, offset%60
+	if mins != 0 || secs != 0 {
+		b = append(b, fmt.Sprintf(":%02d", mins)...)
+	}
+	if secs != 0 {
+		b = append(b, fmt.Sprintf(":%02d", secs)...)
+	}
+	return b
+}
+
+// appendTo appends the rule date, followed by the time unless it is the default 02:00.
+func (d *ruleDate) appendTo(b []byte) []byte {
+	switch d.kind {
+	case ruleJulian:
+		b = append(b, 'J')
+		b = strconv.AppendInt(b, int64(d.day), 10)
+	case ruleDOY:
+		b = strconv.AppendInt(b, int64(d.day), 10)
+	case ruleMonthWeekDay:
+		b = append(b, fmt.Sprintf("M%d.%d.%d", d.mon, d.week, d.day)...)
+	}
+	if d.time != 2*60*60 {
+		b = appendRuleOffset(append(b, '/'), d.time)
+	}
+	return b
+}
+
+const secondsPerDay = 24 * 60 * 60
+
+// lookup returns the zone in effect at sec (Unix time) along with the interval [start, end) during which
+// the zone is known to be in effect.
+// Like in Go's time package, the interval is accurate close to transitions, but otherwise may end at
+// the year boundary even though the zone does not change there.
+func (r *tzRule) lookup(sec int64) (zone Zone, start, end int64) {
+	if !r.hasDST {
+		return r.std, alpha, omega
+	}
+	utc := time.Unix(sec, 0).UTC()
+	year := utc.Year()
+	// This mirrors how Go computes the seconds since the start of the year.
+	// Go uses a truncated remainder, which for times before 1970 evaluates the rule as if
+	// the time was one day earlier. We do the same so that we agree with Go.
+	ysec := int64(utc.YearDay()-1)*secondsPerDay + sec%secondsPerDay
+	ystart := sec - ysec
+
+	std, dst := r.std, r.dst
+	startSec := r.start.secondsInYear(year) - int64(r.std.Offset/time.Second)
+	endSec := r.end.secondsInYear(year) - int64(r.dst.Offset/time.Second)
+	if endSec < startSec {
+		// Southern hemisphere, DST spans the year boundary.
+		startSec, endSec = endSec, startSec
+		std, dst = dst, std
+	}
+	switch {
+	case ysec < startSec:
+		return std, ystart, startSec + ystart
+	case ysec >= endSec:
+		// Go uses 365 days as the length of the year here, which is too short for leap years.
+		return std, endSec + ystart, ystart + int64(daysInYear(year))*secondsPerDay
+	default:
+		return dst, startSec + ystart, endSec + ystart
+	}
+}
+
+// transitions returns the Unix times when DST starts and ends in the given year.
+func (r *tzRule) transitions(year int) (start, end int64) {
+	yearStart := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+	start = yearStart + r.start.secondsInYear(year) - int64(r.std.Offset/time.Second)
+	end = yearStart + r.end.secondsInYear(year) - int64(r.dst.Offset/time.Second)
+	return start, end
+}
+
+// secondsInYear returns the local time of the rule date in seconds since the start of the year.
+func (d *ruleDate) secondsInYear(year int) int64 {
+	var day int
+	switch d.kind {
+	case ruleJulian:
+		day = d.day - 1
+		if isLeap(year) && d.day >= 60 {
+			day++
+		}
+	case ruleDOY:
+		day = d.day
+	case ruleMonthWeekDay:
+		first := time.Date(year, time.Month(d.mon), 1, 0, 0, 0, 0, time.UTC)
+		// Day of month (zero based) of the first d.day weekday in the month.
+		dom := (d.day - int(first.Weekday()) + 7) % 7
+		daysInMonth := time.Date(year, time.Month(d.mon)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		for i := 1; i < d.week; i++ {
+			if dom+7 >= daysInMonth {
+				break
+			}
+			dom += 7
+		}
+		day = first.YearDay() - 1 + dom
+	}
+	return int64(day)*secondsPerDay + int64(d.time)
+}
+
+func daysInYear(year int) int {
+	if isLeap(year) {
+		return 366
+	}
+	return 365
+}
+
+func isLeap(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+const (
+	// alpha and omega are the beginning and the end of time, the same as in Go's time package.
+	alpha = -1 << 63
+	omega = 1<<63 - 1
+)
@@ -0,0 +1,243 @@
+package timezones
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testRules = []string{
+	"UTC0",
+	"<+0530>-5:30",
+	"EST5EDT",
+	"EST5EDT,M3.2.0,M11.1.0",
+	"CET-1CEST,M3.5.0,M10.5.0/3",
+	"<MyExt>-02:23:00<MyExtDST>-03:23:00,M1.2.3/10:00:00,M2.3.4/10:00:00",
+	"AEST-10AEDT,M10.1.0,M4.1.0/3",
+	"NZST-12NZDT,M9.5.0,M4.1.0/3",
+	"<-03>3<-02>,M3.5.0/-2,M10.5.0/-1",
+	"IST-2IDT,M3.4.4/26,M10.5.0",
+	"WET0WEST,J60/1,J300/2",
+	"WET0WEST,59/1,299/2",
+	"EST5EDT4;M3.2.0,M11.1.0",
+	"<+1030>-10:30<+11>-11,M10.1.0,M4.1.0",
+	"EST5,M3.2.0,M11.1.0",
+}
+
+func TestParseTZRule_MatchesGo(t *testing.T) {
+	for _, extend := range testRules {
+		t.Run(extend, func(t *testing.T) {
+			rule, err := parseTZRule(extend)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			loc, err := NewLocation(Template{Name: "Test", Extend: extend})
+			if err != nil {
+				t.Fatal(err)
+			}
+			start := time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+			end := time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+			for sec := start; sec < end; sec += 3637 * 29 {
+				checkRuleAt(t, &rule, loc, sec)
+			}
+			// Check around the transitions in a few years.
+			for year := 2020; year < 2025; year++ {
+				tStart, tEnd := rule.transitions(year)
+				for _, tr := range []int64{tStart, tEnd} {
+					for _, sec := range []int64{tr - 1, tr, tr + 1} {
+						checkRuleAt(t, &rule, loc, sec)
+					}
+				}
+			}
+		})
+	}
+}
+
+func checkRuleAt(t *testing.T, rule *tzRule, loc *time.Location, sec int64) {
+	t.Helper()
+	zone, start, end := rule.lookup(sec)
+	ti := time.Unix(sec, 0).In(loc)
+	name, offset := ti.Zone()
+	if zone.Name != name || zone.Offset != time.Duration(offset)*time.Second || zone.IsDST != ti.IsDST() {
+		t.Fatalf("at %v: expected %s %d %t, got %+v", time.Unix(sec, 0).UTC(), name, offset, ti.IsDST(), zone)
+	}
+	// Go computes wrong intervals before 1970, so only check them after that.
+	if sec >= 0 && (sec < start || sec >= end) {
+		t.Fatalf("at %v: interval [%d, %d) does not contain %d", time.Unix(sec, 0).UTC(), start, end, sec)
+	}
+}
+
+func TestParseTZRule(t *testing.T) {
+	rule, err := parseTZRule("CET-1CEST,M3.5.0,M10.5.0/3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := tzRule{
+		std:    Zone{Name: "CET", Offset: time.Hour},
+		dst:    Zone{Name: "CEST", Offset: 2 * time.Hour, IsDST: true},
+		hasDST: true,
+		start:  ruleDate{kind: ruleMonthWeekDay, day: 0, week: 5, mon: 3, time: 2 * 60 * 60},
+		end:    ruleDate{kind: ruleMonthWeekDay, day: 0, week: 5, mon: 10, time: 3 * 60 * 60},
+	}
+	if rule != expected {
+		t.Fatalf("expected %+v, got %+v", expected, rule)
+	}
+	start, end := rule.transitions(2022)
+	if expectedStart := time.Date(2022, time.March, 27, 1, 0, 0, 0, time.UTC).Unix(); start != expectedStart {
+		t.Fatalf("expected start %v, got %v", time.Unix(expectedStart, 0).UTC(), time.Unix(start, 0).UTC())
+	}
+	if expectedEnd := time.Date(2022, time.October, 30, 1, 0, 0, 0, time.UTC).Unix(); end != expectedEnd {
+		t.Fatalf("expected end %v, got %v", time.Unix(expectedEnd, 0).UTC(), time.Unix(end, 0).UTC())
+	}
+}
+
+func TestParseTZRule_Invalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"AB0",
+		"<UTC0",
+		"UTC",
+		"UTC+",
+		"UTC169",
+		"UTC0:60",
+		"EST5EDT,",
+		"EST5EDT,M3.2.0",
+		"EST5EDT,M13.2.0,M11.1.0",
+		"EST5EDT,M3.6.0,M11.1.0",
+		"EST5EDT,M3.2.7,M11.1.0",
+		"EST5EDT,J0,J365",
+		"EST5EDT,366,0",
+		"EST5EDT,M3.2.0,M11.1.0,",
+		"EST5EDT,M3.2.0/x,M11.1.0",
+		"EST5EDT:M3.2.0,M11.1.0",
+	}
+	for _, extend := range invalid {
+		_, err := parseTZRule(extend)
+		if !errors.Is(err, ErrInvalidExtend) {
+			t.Fatalf("expected ErrInvalidExtend for %q, got %v", extend, err)
+		}
+	}
+}
+
+func TestTZRule_AllYearDST(t *testing.T) {
+	// Not compared with Go in TestParseTZRule_MatchesGo, because Go caches the zone for the current year
+	// and the cached result differs from the uncached one in the first hours of the year.
+	rule, err := parseTZRule("EST5EDT,0/0,J365/25")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, ti := range []time.Time{
+		time.Date(2022, time.January, 1, 6, 0, 0, 0, time.UTC),
+		time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, time.December, 31, 23, 0, 0, 0, time.UTC),
+	} {
+		zone, _, _ := rule.lookup(ti.Unix())
+		if zone.Name != "EDT" || !zone.IsDST {
+			t.Fatalf("expected EDT at %v, got %+v", ti, zone)
+		}
+	}
+}
+
+func TestTZRule_String(t *testing.T) {
+	for _, tc := range []struct{ rule, expected string }{
+		{"UTC0", "UTC0"},
+		{"UTC+00:00", "UTC0"},
+		{"<+0530>-5:30", "<+0530>-5:30"},
+		{"<MyExt>-02:23:00", "MyExt-2:23"},
+		{"EST5EDT", "EST5EDT,M3.2.0,M11.1.0"},
+		{"EST5EDT4,M3.2.0/2,M11.1.0/02:00:00", "EST5EDT,M3.2.0,M11.1.0"},
+		{"CET-1CEST,M3.5.0,M10.5.0/3", "CET-1CEST,M3.5.0,M10.5.0/3"},
+		{"<-03>3<-02>,M3.5.0/-2,M10.5.0/-1", "<-03>3<-02>,M3.5.0/-2,M10.5.0/-1"},
+		{"IST-2IDT,M3.4.4/26,M10.5.0", "IST-2IDT,M3.4.4/26,M10.5.0"},
+		{"WET0WEST,J60/1,J300/2", "WET0WEST,J60/1,J300"},
+		{"EST5EDT,0/0,J365/25", "EST5EDT,0/0,J365/25"},
+		{"<GMT>0<IST>-1:00:30,M3.5.0/1:30:15,M10.5.0", "GMT0IST-1:00:30,M3.5.0/1:30:15,M10.5.0"},
+	} {
+		rule, err := parseTZRule(tc.rule)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.rule, err)
+		}
+		if s := rule.String(); s != tc.expected {
+			t.Fatalf("%q: expected %q, got %q", tc.rule, tc.expected, s)
+		}
+	}
+}
+
+func TestTZRule_String_GoZoneinfo(t *testing.T) {
+	zr, err := zip.OpenReader(filepath.Join(runtime.GOROOT(), "lib", "time", "zoneinfo.zip"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			t.Skip("zoneinfo.zip not available")
+		}
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	for _, f := range zr.File {
+		data, err := fs.ReadFile(zr, f.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template, err := LoadTZData(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if template.Extend == "" {
+			continue
+		}
+		rule, err := parseTZRule(template.Extend)
+		if err != nil {
+			t.Fatalf("%s: %v", f.Name, err)
+		}
+		// zic emits extend strings in the canonical form.
+		if s := rule.String(); s != template.Extend {
+			t.Errorf("%s: expected %q, got %q", f.Name, template.Extend, s)
+		}
+	}
+}
+
+func TestCachedTZRule(t *testing.T) {
+	for _, s := range []string{"CET-1CEST,M3.5.0,M10.5.0/3", "invalid"} {
+		expected, expectedErr := parseTZRule(s)
+		for i := 0; i < 2; i++ {
+			rule, err := cachedTZRule(s)
+			if !reflect.DeepEqual(rule, expected) || !reflect.DeepEqual(err, expectedErr) {
+				t.Fatalf("%q: expected %+v, %v, got %+v, %v", s, expected, expectedErr, rule, err)
+			}
+		}
+	}
+}
+
+func TestCachedTZRule_Concurrent(t *testing.T) {
+	const extend = "CET-1CEST,M3.5.0,M10.5.0/3"
+	expected, err := parseTZRule(extend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Parse other strings too, so that the cache is written while others read it.
+			for j := 0; j < maxCachedRules/2; j++ {
+				if _, err := cachedTZRule(fmt.Sprintf("<XX%d>-1XDT,J%d,J365", i, j%364+1)); err != nil {
+					t.Error(err)
+					return
+				}
+				if rule, err := cachedTZRule(extend); err != nil || rule != expected {
+					t.Errorf("expected %+v, got %+v, %v", expected, rule, err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
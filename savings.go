@@ -0,0 +1,125 @@
+package timezones
+
+import (
+	"strings"
+	"time"
+)
+
+// ZoneSavings is the daylight saving time savings of a DST zone, see Template.Savings.
+type ZoneSavings struct {
+	// ZoneIndex is the index of the DST zone in Template.Zones.
+	ZoneIndex int
+	// StandardIndex is the index of the associated standard time zone in Template.Zones,
+	// or -1 if there is none.
+	StandardIndex int
+	// Savings is the offset of the DST zone minus the offset of the standard time zone, usually an hour.
+	// It is zero if there is no standard time zone.
+	Savings time.Duration
+}
+
+// Savings returns the savings of each DST zone in Zones against its associated standard time zone,
+// in the order of Zones, as stored by formats that use savings instead of offsets, like VTIMEZONE.
+//
+// The standard time zone of a DST zone is the standard time zone it most often follows in Changes,
+// like SavingsAt, or precedes if there is none before it. The zones of Extend are associated with each other.
+// If a DST zone is never adjacent to a standard time zone, a standard time zone with a matching designation
+// is used, e.g. EST for EDT or CET for CEST.
+func (t *Template) Savings() []ZoneSavings {
+	// counts[dst][std] is how many times the standard time zone was associated with the DST zone.
+	counts := make(map[int]map[int]int)
+	associate := func(dst, std int) {
+		if counts[dst] == nil {
+			counts[dst] = make(map[int]int)
+		}
+		counts[dst][std]++
+	}
+
+	sequence := make([]int, 0, len(t.Changes)+1)
+	sequence = append(sequence, t.FirstZoneIndex)
+	for _, c := range t.Changes {
+		sequence = append(sequence, c.ZoneIndex)
+	}
+	isDST := func(i int) bool {
+		return i >= 0 && i < len(t.Zones) && t.Zones[i].IsDST
+	}
+	isStd := func(i int) bool {
+		return i >= 0 && i < len(t.Zones) && !t.Zones[i].IsDST
+	}
+	for i, zi := range sequence {
+		if !isDST(zi) {
+			continue
+		}
+		std := -1
+		for j := i - 1; j >= 0 && std < 0; j-- {
+			if isStd(sequence[j]) {
+				std = sequence[j]
+			}
+		}
+		for j := i + 1; j < len(sequence) && std < 0; j++ {
+			if isStd(sequence[j]) {
+				std = sequence[j]
+			}
+		}
+		if std >= 0 {
+			associate(zi, std)
+		}
+	}
+	if rule, err := cachedTZRule(t.Extend); err == nil && rule.hasDST {
+		dst, std := t.zoneIndex(rule.dst), t.zoneIndex(rule.std)
+		if dst >= 0 && std >= 0 {
+			associate(dst, std)
+		}
+	}
+
+	var result []ZoneSavings
+	for i, zone := range t.Zones {
+		if !zone.IsDST {
+			continue
+		}
+		std, best := -1, 0
+		for candidate, n := range counts[i] {
+			if n > best || n == best && candidate < std {
+				std, best = candidate, n
+			}
+		}
+		if std < 0 {
+			std = t.standardByName(zone.Name)
+		}
+		s := ZoneSavings{ZoneIndex: i, StandardIndex: std}
+		if std >= 0 {
+			s.Savings = zone.Offset.Round(time.Second) - t.Zones[std].Offset.Round(time.Second)
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+// zoneIndex returns the index of the first zone in Zones that is the same as zone, or -1 if there is none.
+func (t *Template) zoneIndex(zone Zone) int {
+	for i := range t.Zones {
+		if sameZone(t.Zones[i], zone) {
+			return i
+		}
+	}
+	return -1
+}
+
+// standardByName returns the index of the standard time zone named like the DST designation dst,
+// e.g. EST for EDT or CET for CEST, or -1 if there is none.
+func (t *Template) standardByName(dst string) int {
+	var candidates []string
+	if i := strings.LastIndexByte(dst, 'D'); i >= 0 {
+		candidates = append(candidates, dst[:i]+"S"+dst[i+1:])
+	}
+	if strings.HasSuffix(dst, "ST") {
+		candidates = append(candidates, dst[:len(dst)-2]+"T")
+	}
+	for _, name := range candidates {
+		for i, zone := range t.Zones {
+			if !zone.IsDST && zone.Name == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,56 @@
+package timezones
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTemplate_Savings(t *testing.T) {
+	gmt := Zone{Name: "GMT"}
+	bst := Zone{Name: "BST", Offset: time.Hour, IsDST: true}
+	bdst := Zone{Name: "BDST", Offset: 2 * time.Hour, IsDST: true}
+	template := Template{
+		Zones: []Zone{gmt, bst, bdst},
+		Changes: []Change{
+			{Start: time.Date(1941, time.March, 1, 0, 0, 0, 0, time.UTC), ZoneIndex: 1},
+			{Start: time.Date(1941, time.May, 4, 1, 0, 0, 0, time.UTC), ZoneIndex: 2},
+			{Start: time.Date(1941, time.August, 10, 1, 0, 0, 0, time.UTC), ZoneIndex: 1},
+			{Start: time.Date(1945, time.October, 7, 1, 0, 0, 0, time.UTC), ZoneIndex: 0},
+		},
+		Extend: "GMT0BST,M3.5.0/1,M10.5.0",
+	}
+	expected := []ZoneSavings{
+		{ZoneIndex: 1, StandardIndex: 0, Savings: time.Hour},
+		// Double summer time is two hours ahead of the standard time, not of BST.
+		{ZoneIndex: 2, StandardIndex: 0, Savings: 2 * time.Hour},
+	}
+	if savings := template.Savings(); !reflect.DeepEqual(savings, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, savings)
+	}
+
+	// Zones only referenced by Extend.
+	cet := Zone{Name: "CET", Offset: time.Hour}
+	cest := Zone{Name: "CEST", Offset: 2 * time.Hour, IsDST: true}
+	extendOnly := Template{Zones: []Zone{cest, cet}, FirstZoneIndex: 1, Extend: "CET-1CEST,M3.5.0,M10.5.0/3"}
+	expected = []ZoneSavings{{ZoneIndex: 0, StandardIndex: 1, Savings: time.Hour}}
+	if savings := extendOnly.Savings(); !reflect.DeepEqual(savings, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, savings)
+	}
+}
+
+func TestTemplate_Savings_Designation(t *testing.T) {
+	est := Zone{Name: "EST", Offset: -5 * time.Hour}
+	edt := Zone{Name: "EDT", Offset: -4 * time.Hour, IsDST: true}
+	cest := Zone{Name: "CEST", Offset: 2 * time.Hour, IsDST: true}
+	cet := Zone{Name: "CET", Offset: time.Hour}
+	template := Template{Zones: []Zone{edt, est, cest, cet, {Name: "XDT", IsDST: true}}}
+	expected := []ZoneSavings{
+		{ZoneIndex: 0, StandardIndex: 1, Savings: time.Hour},
+		{ZoneIndex: 2, StandardIndex: 3, Savings: time.Hour},
+		{ZoneIndex: 4, StandardIndex: -1},
+	}
+	if savings := template.Savings(); !reflect.DeepEqual(savings, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, savings)
+	}
+}
@@ -0,0 +1,97 @@
+package timezones
+
+import "time"
+
+// Recurrence is how often a Schedule repeats.
+type Recurrence int
+
+const (
+	// Daily repeats every day.
+	Daily Recurrence = iota
+
+	// Weekly repeats every week on the same day of the week.
+	Weekly
+
+	// Monthly repeats every month on the same day of the month.
+	// Months without that day, e.g. the 31st, are skipped.
+	Monthly
+)
+
+// Schedule is a local wall-clock time that repeats, for example every day at 09:00.
+type Schedule struct {
+	// First is the local date and time of the first occurrence. Its location is ignored.
+	First time.Time
+
+	// Every is how often the schedule repeats.
+	Every Recurrence
+
+	// Policy handles occurrences at local times that occur more than once or not at all, see ResolveLocal.
+	// With ResolveStrict, such occurrences are left out.
+	Policy ResolvePolicy
+}
+
+// Expand returns the instants within [from, to) at which the schedule occurs in the zone described
+// by the template, in increasing order and in UTC.
+// It returns the same error as Validate if the template is not valid.
+func (s *Schedule) Expand(template Template, from, to time.Time) ([]time.Time, error) {
+	if err := Validate(template); err != nil {
+		return nil, err
+	}
+	zl := newZoneLookup(&template)
+	minOffset, maxOffset := zl.offsetRange()
+	year, month, day := s.First.Date()
+	hour, min, sec := s.First.Clock()
+	nsec := s.First.Nanosecond()
+	occurrence := func(n int) (time.Time, bool) {
+		switch s.Every {
+		case Weekly:
+			return time.Date(year, month, day+7*n, hour, min, sec, 0, time.UTC), true
+		case Monthly:
+			wall := time.Date(year, month+time.Month(n), day, hour, min, sec, 0, time.UTC)
+			return wall, wall.Day() == day
+		default:
+			return time.Date(year, month, day+n, hour, min, sec, 0, time.UTC), true
+		}
+	}
+
+	// Skip the occurrences that are certainly before from.
+	n := 0
+	first := time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+	if skip := from.Unix() + maxOffset - first.Unix(); skip > 0 {
+		switch s.Every {
+		case Weekly:
+			n = int(skip/(7*secondsPerDay)) - 1
+		case Monthly:
+			n = int(skip/(31*secondsPerDay)) - 1
+		default:
+			n = int(skip/secondsPerDay) - 1
+		}
+		if n < 0 {
+			n = 0
+		}
+	}
+
+	var instants []time.Time
+	for ; ; n++ {
+		wall, ok := occurrence(n)
+		// Instants of a local time are at least wall-maxOffset.
+		if wall.Unix()-maxOffset >= to.Unix() {
+			break
+		}
+		if !ok || wall.Unix()-minOffset < from.Unix() {
+			continue
+		}
+		r := zl.resolveLocal(wall.Unix())
+		u, err := r.pick(wall, s.Policy)
+		if err != nil {
+			// ResolveStrict and the local time occurs more than once or not at all.
+			continue
+		}
+		instant := time.Unix(u, int64(nsec)).UTC()
+		if instant.Before(from) || !instant.Before(to) {
+			continue
+		}
+		instants = append(instants, instant)
+	}
+	return instants, nil
+}
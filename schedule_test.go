@@ -0,0 +1,71 @@
+package timezones
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSchedule_Expand(t *testing.T) {
+	template := Template{Extend: "CET-1CEST,M3.5.0,M10.5.0/3"}
+	utc := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2022, month, day, hour, min, 0, 0, time.UTC)
+	}
+
+	// Every day at 09:00 across the start of DST.
+	s := Schedule{First: time.Date(2020, time.January, 1, 9, 0, 0, 0, time.UTC)}
+	instants, err := s.Expand(template, utc(time.March, 26, 0, 0), utc(time.March, 29, 0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []time.Time{utc(time.March, 26, 8, 0), utc(time.March, 27, 7, 0), utc(time.March, 28, 7, 0)}
+	if !reflect.DeepEqual(instants, expected) {
+		t.Fatalf("expected %v, got %v", expected, instants)
+	}
+
+	// Every Sunday at 02:30, skipped in March and repeated in October.
+	from, to := utc(time.March, 20, 0, 0), utc(time.November, 1, 0, 0)
+	for _, tc := range []struct {
+		policy   ResolvePolicy
+		march    []time.Time
+		october  []time.Time
+		expected int
+	}{
+		{ResolveEarliest, []time.Time{utc(time.March, 27, 0, 30)}, []time.Time{utc(time.October, 30, 0, 30)}, 33},
+		{ResolveLatest, []time.Time{utc(time.March, 27, 1, 30)}, []time.Time{utc(time.October, 30, 1, 30)}, 33},
+		{ResolveShiftForward, []time.Time{utc(time.March, 27, 1, 0)}, []time.Time{utc(time.October, 30, 0, 30)}, 33},
+		{ResolveStrict, nil, nil, 31},
+	} {
+		s := Schedule{First: time.Date(2022, time.January, 2, 2, 30, 0, 0, time.UTC), Every: Weekly, Policy: tc.policy}
+		instants, err := s.Expand(template, from, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(instants) != tc.expected {
+			t.Fatalf("policy %d: expected %d instants, got %v", tc.policy, tc.expected, instants)
+		}
+		var march, october []time.Time
+		for _, instant := range instants {
+			switch instant.Truncate(24 * time.Hour) {
+			case utc(time.March, 27, 0, 0):
+				march = append(march, instant)
+			case utc(time.October, 30, 0, 0):
+				october = append(october, instant)
+			}
+		}
+		if !reflect.DeepEqual(march, tc.march) || !reflect.DeepEqual(october, tc.october) {
+			t.Fatalf("policy %d: expected %v and %v, got %v and %v", tc.policy, tc.march, tc.october, march, october)
+		}
+	}
+
+	// Monthly on the 31st skips shorter months.
+	s = Schedule{First: time.Date(2022, time.January, 31, 12, 0, 0, 0, time.UTC), Every: Monthly}
+	instants, err = s.Expand(Template{Zones: []Zone{{Name: "UTC"}}}, utc(time.January, 1, 0, 0), utc(time.June, 1, 0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []time.Time{utc(time.January, 31, 12, 0), utc(time.March, 31, 12, 0), utc(time.May, 31, 12, 0)}
+	if !reflect.DeepEqual(instants, expected) {
+		t.Fatalf("expected %v, got %v", expected, instants)
+	}
+}
@@ -0,0 +1,100 @@
+package timezones
+
+import (
+	"fmt"
+	"time"
+)
+
+// CompressToExtend returns a copy of the template without the trailing changes that Extend predicts,
+// like zic -b slim does.
+// The result behaves the same as the template, see Template.Diff, but encodes to smaller data.
+// At least one change is kept, so that the zones before the first change stay the same.
+// If Extend is empty or invalid, the template is returned unchanged.
+func (t *Template) CompressToExtend() Template {
+	result := *t
+	if t.Extend == "" {
+		return result
+	}
+	rule, err := cachedTZRule(t.Extend)
+	if err != nil {
+		return result
+	}
+	n := len(t.Changes)
+	for n > 1 && predicts(&rule, t.zoneOf(t.Changes[n-2]), t.Changes[n-2].Start.Unix(), t.Changes[n-1].Start.Unix()) {
+		n--
+	}
+	result.Changes = t.Changes[:n:n]
+	return result
+}
+
+// zoneOf returns the zone the change switches to, or an empty zone if the index is invalid.
+func (t *Template) zoneOf(c Change) Zone {
+	if c.ZoneIndex < 0 || c.ZoneIndex >= len(t.Zones) {
+		return Zone{}
+	}
+	return t.Zones[c.ZoneIndex]
+}
+
+// predicts reports whether the rule uses zone during the whole interval [start, end).
+func predicts(rule *tzRule, zone Zone, start, end int64) bool {
+	for sec := start; sec < end; {
+		ruleZone, _, ruleEnd := rule.lookup(sec)
+		if !sameZone(zone, ruleZone) {
+			return false
+		}
+		sec = ruleEnd
+	}
+	return true
+}
+
+// MaterializeExtend returns a copy of the template with changes generated from Extend until the given time,
+// like zic -b fat does.
+// The changes are generated since the last change, zones used by Extend are added to Zones if needed.
+// Extend is kept, so the result behaves the same as the template.
+//
+// Templates without changes are returned unchanged, as Extend applies to them since the beginning of time.
+// If Extend is empty, the template is returned unchanged; an invalid Extend is an error.
+func (t *Template) MaterializeExtend(until time.Time) (Template, error) {
+	result := *t
+	if t.Extend == "" || len(t.Changes) == 0 {
+		return result, nil
+	}
+	rule, err := cachedTZRule(t.Extend)
+	if err != nil {
+		return Template{}, err
+	}
+	zones := append([]Zone(nil), t.Zones...)
+	changes := append([]Change(nil), t.Changes...)
+	zoneIndex := func(z Zone) int {
+		for i := range zones {
+			if sameZone(zones[i], z) {
+				return i
+			}
+		}
+		zones = append(zones, z)
+		return len(zones) - 1
+	}
+
+	last := changes[len(changes)-1]
+	current := t.zoneOf(last)
+	end := until.Unix()
+	for sec := last.Start.Unix(); sec < end; {
+		zone, _, next := rule.lookup(sec)
+		switch {
+		case sameZone(zone, current):
+		case sec == last.Start.Unix():
+			// Extend is used instead of the zone of the last change, see Template.Changes.
+			changes[len(changes)-1].ZoneIndex = zoneIndex(zone)
+		default:
+			changes = append(changes, Change{Start: time.Unix(sec, 0).UTC(), ZoneIndex: zoneIndex(zone)})
+		}
+		current = zone
+		sec = next
+	}
+	if len(zones) > MaxZones {
+		return Template{}, fmt.Errorf("%w: %d zones, max is %d", ErrTooManyZones, len(zones), MaxZones)
+	}
+	result.Zones = zones
+	result.Changes = changes
+	return result, nil
+}
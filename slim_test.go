@@ -0,0 +1,94 @@
+package timezones
+
+import (
+	"archive/zip"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestTemplate_CompressAndMaterializeExtend(t *testing.T) {
+	cet := Zone{Name: "CET", Offset: time.Hour}
+	template := Template{
+		Zones: []Zone{cet},
+		Changes: []Change{
+			{Start: time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC), ZoneIndex: 0},
+		},
+		Extend: "CET-1CEST,M3.5.0,M10.5.0/3",
+	}
+	until := time.Date(2038, time.January, 1, 0, 0, 0, 0, time.UTC)
+	fat, err := template.MaterializeExtend(until)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fat.Zones) != 2 || len(fat.Changes) != 1+2*48 {
+		t.Fatalf("unexpected fat template with %d zones and %d changes", len(fat.Zones), len(fat.Changes))
+	}
+	if len(template.Zones) != 1 || len(template.Changes) != 1 {
+		t.Fatal("the original template must not be modified")
+	}
+	from := time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if diffs := template.Diff(&fat, from, to); len(diffs) != 0 {
+		t.Fatalf("unexpected differences %+v", diffs)
+	}
+	if _, err := TZData(fat); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	slim := fat.CompressToExtend()
+	if len(slim.Changes) != 1 {
+		t.Fatalf("expected 1 change, got %d", len(slim.Changes))
+	}
+	if diffs := slim.Diff(&fat, from, to); len(diffs) != 0 {
+		t.Fatalf("unexpected differences %+v", diffs)
+	}
+
+	template.Extend = "invalid"
+	if _, err := template.MaterializeExtend(until); !errors.Is(err, ErrInvalidExtend) {
+		t.Fatalf("expected ErrInvalidExtend, got %v", err)
+	}
+	if slim := template.CompressToExtend(); len(slim.Changes) != 1 {
+		t.Fatal("expected template with invalid extend to be unchanged")
+	}
+}
+
+func TestTemplate_CompressToExtend_GoZoneinfo(t *testing.T) {
+	zr, err := zip.OpenReader(filepath.Join(runtime.GOROOT(), "lib", "time", "zoneinfo.zip"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			t.Skip("zoneinfo.zip not available")
+		}
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	from := time.Date(1800, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for _, name := range []string{"Europe/Prague", "America/New_York", "Australia/Sydney", "America/Santiago"} {
+		data, err := fs.ReadFile(zr, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template, err := LoadTZData(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fat, err := template.MaterializeExtend(time.Date(2038, time.January, 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		slim := fat.CompressToExtend()
+		if len(slim.Changes) > len(template.Changes) {
+			t.Fatalf("%s: slim has %d changes, more than %d", name, len(slim.Changes), len(template.Changes))
+		}
+		for _, other := range []*Template{&fat, &slim} {
+			if diffs := template.Diff(other, from, to); len(diffs) != 0 {
+				t.Fatalf("%s: unexpected differences %+v", name, diffs[0])
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package timezones
+
+import (
+	"sort"
+	"time"
+)
+
+// Stats summarizes a database of templates, see DatabaseStats.
+type Stats struct {
+	// Zones is the number of templates.
+	Zones int
+
+	// Transitions is the total number of changes in all templates.
+	Transitions int
+
+	// MinYear and MaxYear are the UTC years of the earliest and the latest change in all templates.
+	// Both are zero if there are no changes.
+	MinYear, MaxYear int
+
+	// Offsets are the distinct offsets of all zones, rounded to seconds, in increasing order.
+	Offsets []time.Duration
+
+	// Size is the total length of the TZif data of the templates, see TZDataSize.
+	// Templates that can't be encoded are not included, they are counted in Invalid instead.
+	Size int
+
+	// Invalid is the number of templates that can't be encoded.
+	Invalid int
+}
+
+// DatabaseStats returns statistics of the templates, e.g. loaded by LoadAll, meant for sanity checks
+// of compiled releases, such as comparing the numbers with the previous release.
+func DatabaseStats(templates map[string]*Template) Stats {
+	stats := Stats{Zones: len(templates)}
+	offsets := make(map[time.Duration]bool)
+	hasChanges := false
+	for _, t := range templates {
+		stats.Transitions += len(t.Changes)
+		for _, c := range t.Changes {
+			year := c.Start.UTC().Year()
+			if !hasChanges || year < stats.MinYear {
+				stats.MinYear = year
+			}
+			if !hasChanges || year > stats.MaxYear {
+				stats.MaxYear = year
+			}
+			hasChanges = true
+		}
+		for _, z := range t.Zones {
+			offsets[z.Offset.Round(time.Second)] = true
+		}
+		size, err := TZDataSize(*t)
+		if err != nil {
+			stats.Invalid++
+			continue
+		}
+		stats.Size += size
+	}
+	stats.Offsets = make([]time.Duration, 0, len(offsets))
+	for offset := range offsets {
+		stats.Offsets = append(stats.Offsets, offset)
+	}
+	sort.Slice(stats.Offsets, func(i, j int) bool {
+		return stats.Offsets[i] < stats.Offsets[j]
+	})
+	return stats
+}
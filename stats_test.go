@@ -0,0 +1,44 @@
+package timezones
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDatabaseStats(t *testing.T) {
+	cet := Zone{Name: "CET", Offset: time.Hour}
+	cest := Zone{Name: "CEST", Offset: 2 * time.Hour, IsDST: true}
+	prague := Template{
+		Zones: []Zone{cet, cest},
+		Changes: []Change{
+			{Start: time.Date(1946, time.May, 6, 1, 0, 0, 0, time.UTC), ZoneIndex: 1},
+			{Start: time.Date(1979, time.September, 30, 1, 0, 0, 0, time.UTC), ZoneIndex: 0},
+		},
+		Extend: "CET-1CEST,M3.5.0,M10.5.0/3",
+	}
+	utc := Template{Zones: []Zone{{Name: "UTC"}}}
+	invalid := Template{Zones: []Zone{cet}, Changes: []Change{{Start: time.Date(1950, time.January, 1, 0, 0, 0, 0, time.UTC), ZoneIndex: 5}}}
+	stats := DatabaseStats(map[string]*Template{"Europe/Prague": &prague, "UTC": &utc, "Invalid": &invalid})
+
+	pragueSize, err := TZDataSize(prague)
+	if err != nil {
+		t.Fatal(err)
+	}
+	utcSize, err := TZDataSize(utc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Stats{
+		Zones:       3,
+		Transitions: 3,
+		MinYear:     1946,
+		MaxYear:     1979,
+		Offsets:     []time.Duration{0, time.Hour, 2 * time.Hour},
+		Size:        pragueSize + utcSize,
+		Invalid:     1,
+	}
+	if !reflect.DeepEqual(stats, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, stats)
+	}
+}
@@ -0,0 +1,88 @@
+package timezones
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// WriteMermaid writes a Mermaid gantt chart of the zones in effect within [from, to) to w,
+// with a section for each distinct zone and a bar for each interval it is in effect, see OffsetIntervals.
+// It is meant for documenting and debugging custom zones; the layout may change.
+func (t *Template) WriteMermaid(w io.Writer, from, to time.Time) error {
+	var buf bytes.Buffer
+	buf.WriteString("gantt\n")
+	if t.Name != "" {
+		fmt.Fprintf(&buf, "    title %s\n", mermaidText(t.Name))
+	}
+	// Unix seconds avoid any ambiguity of time zones in the chart.
+	buf.WriteString("    dateFormat X\n")
+	buf.WriteString("    axisFormat %Y-%m-%d\n")
+	intervals := t.OffsetIntervals(from, to)
+	var sections []Zone
+	bars := make(map[Zone][]Interval)
+	for _, interval := range intervals {
+		key := Zone{Name: interval.Zone.Name, Offset: interval.Zone.Offset.Round(time.Second), IsDST: interval.Zone.IsDST}
+		if _, ok := bars[key]; !ok {
+			sections = append(sections, key)
+		}
+		bars[key] = append(bars[key], interval)
+	}
+	for _, zone := range sections {
+		fmt.Fprintf(&buf, "    section %s\n", mermaidText(timelineZone(zone)))
+		for _, interval := range bars[zone] {
+			tag := ""
+			if zone.IsDST {
+				tag = "active, "
+			}
+			fmt.Fprintf(&buf, "    %s :%s%d, %d\n", mermaidText(zone.Name), tag, interval.Start.Unix(), interval.End.Unix())
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// WriteGraphviz writes a Graphviz DOT graph of the zones in effect within [from, to) to w,
+// with a node for each interval a zone is in effect, see OffsetIntervals, and an edge for each change.
+// DST intervals are filled. It is meant for documenting and debugging custom zones; the layout may change.
+func (t *Template) WriteGraphviz(w io.Writer, from, to time.Time) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "digraph %s {\n", dotQuote(t.Name))
+	buf.WriteString("    rankdir=LR;\n")
+	buf.WriteString("    node [shape=box];\n")
+	intervals := t.OffsetIntervals(from, to)
+	for i, interval := range intervals {
+		label := fmt.Sprintf("%s\n%s - %s", timelineZone(interval.Zone),
+			interval.Start.UTC().Format(time.RFC3339), interval.End.UTC().Format(time.RFC3339))
+		style := ""
+		if interval.Zone.IsDST {
+			style = ", style=filled"
+		}
+		fmt.Fprintf(&buf, "    i%d [label=%s%s];\n", i, dotQuote(label), style)
+		if i > 0 {
+			fmt.Fprintf(&buf, "    i%d -> i%d [label=%s];\n", i-1, i, dotQuote(interval.Start.UTC().Format(time.RFC3339)))
+		}
+	}
+	buf.WriteString("}\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// timelineZone formats the zone as designation, offset and std or dst, like Dump but without padding.
+func timelineZone(z Zone) string {
+	kind := "std"
+	if z.IsDST {
+		kind = "dst"
+	}
+	return fmt.Sprintf("%s %s %s", z.Name, formatOffset(z.Offset), kind)
+}
+
+// mermaidText removes characters that have a meaning in Mermaid gantt charts, e.g. "+01:00" becomes "+0100".
+var mermaidText = strings.NewReplacer(":", "", "#", "", ";", ",", "\n", " ").Replace
+
+// dotQuote returns s as a DOT quoted string.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
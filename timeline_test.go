@@ -0,0 +1,59 @@
+package timezones
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func timelineTemplate() Template {
+	return Template{
+		Name:   "Europe/Prague",
+		Zones:  []Zone{{Name: "CET", Offset: time.Hour}},
+		Extend: "CET-1CEST,M3.5.0,M10.5.0/3",
+	}
+}
+
+func TestTemplate_WriteMermaid(t *testing.T) {
+	template := timelineTemplate()
+	var buf bytes.Buffer
+	err := template.WriteMermaid(&buf, time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `gantt
+    title Europe/Prague
+    dateFormat X
+    axisFormat %Y-%m-%d
+    section CET +0100 std
+    CET :1609459200, 1616893200
+    CET :1635642000, 1640995200
+    section CEST +0200 dst
+    CEST :active, 1616893200, 1635642000
+`
+	if buf.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestTemplate_WriteGraphviz(t *testing.T) {
+	template := timelineTemplate()
+	var buf bytes.Buffer
+	err := template.WriteGraphviz(&buf, time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `digraph "Europe/Prague" {
+    rankdir=LR;
+    node [shape=box];
+    i0 [label="CET +01:00 std\n2021-01-01T00:00:00Z - 2021-03-28T01:00:00Z"];
+    i1 [label="CEST +02:00 dst\n2021-03-28T01:00:00Z - 2021-07-01T00:00:00Z", style=filled];
+    i0 -> i1 [label="2021-03-28T01:00:00Z"];
+}
+`
+	if buf.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
@@ -2,10 +2,11 @@
 package timezones
 
 import (
+	"bytes"
 	"encoding/binary"
-	"errors"
 	"fmt"
 	"math"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -39,11 +40,21 @@ type Template struct {
 	Name string
 
 	// Zones lists local zones.
-	// At the beginning of time, Zone at index 0 applies.
+	// At the beginning of time, the zone at index FirstZoneIndex applies.
 	// When that zone changes to another zone is specified in Changes.
-	// Maximum of 254 zones can be present.
+	// At most MaxZones zones can be present, see CheckFits.
 	Zones []Zone
 
+	// FirstZoneIndex is the index of the zone in Zones that applies before the first change.
+	// The zero value selects Zones[0], so the zones don't need to be reordered when importing data
+	// that lists the initial zone elsewhere.
+	// Templates decoded from TZif data always have the first zone at index 0.
+	//
+	// The first zone may be DST. Go prefers a standard time zone before the first transition in some cases,
+	// see ChooseFirstZone, but the encoded data never triggers them as the first zone is stored in a local time
+	// type not used by any transition, so the first zone applies as written.
+	FirstZoneIndex int
+
 	// Changes specifies zone transitions.
 	// Changes Start times must be in strictly increasing order.
 	// If Extend is non-empty, the ZoneIndex of the last Change is ignored, Extend is used instead.
@@ -54,6 +65,30 @@ type Template struct {
 	// If there is at most one zone specified by Zones and Changes, Extend applies since the beginning of time.
 	// Extend is a TZ string conforming to RFC 8536, section 3.3.
 	Extend string
+
+	// Version is the TZif version of the data the template was decoded from, 1 to 3,
+	// or zero if the template was not decoded from TZif data.
+	// It only affects encoding if Encoder.PreserveVersion is set.
+	Version int
+
+	// Trailing holds the data after the footer that this package does not understand, such as
+	// nonstandard extensions, or the data after the data block if there is no valid footer.
+	// It is nil if there is no such data.
+	// Only the first line of the footer is used as Extend, while Go would use the whole rest of the data,
+	// including Trailing, and ignore it as invalid.
+	// It only affects encoding if Encoder.KeepTrailing is set.
+	Trailing []byte
+
+	// LeapSeconds are the leap second records of the data, in the order of occurrence, for the right/ zones
+	// of the tz database whose clocks count leap seconds. Changes of such zones need to be in the same
+	// time scale as LeapSeconds.
+	// Go ignores leap second records, so they only matter to other readers.
+	LeapSeconds []LeapSecond
+
+	// Metadata holds information about the template that is not part of TZif data, such as its source.
+	// It is loaded from and written to a sidecar file by LoadAll, LoadFile and WriteTree, and ignored
+	// by encoding. It is nil if there is no metadata.
+	Metadata *Metadata
 }
 
 // NewLocation creates a new time.Location from the template.
@@ -73,14 +108,218 @@ func TZData(template Template) ([]byte, error) {
 	return buildTZData(&template)
 }
 
-const headerSize = 4 + 1 + 15 + 6*4 // magic + ver + unused + 6x count
+// MustNewLocation is like NewLocation but panics if the template is not valid.
+// It simplifies safe initialization of global variables holding locations.
+func MustNewLocation(template Template) *time.Location {
+	loc, err := NewLocation(template)
+	if err != nil {
+		panic(`timezones: NewLocation(` + strconv.Quote(template.Name) + `): ` + err.Error())
+	}
+	return loc
+}
+
+// MustTZData is like TZData but panics if the template is not valid.
+// It simplifies safe initialization of global variables holding TZif data.
+func MustTZData(template Template) []byte {
+	data, err := TZData(template)
+	if err != nil {
+		panic(`timezones: TZData(` + strconv.Quote(template.Name) + `): ` + err.Error())
+	}
+	return data
+}
+
+// TZDataSize returns the length of the data TZData would return for the template.
+// It returns the same error as TZData if the template is not valid.
+// Unlike TZData, it does not encode the data.
+func TZDataSize(template Template) (int, error) {
+	var e Encoder
+	return e.Size(template)
+}
+
+// AppendTZData appends the TZif data of the template, the same data TZData returns, to dst and returns
+// the extended buffer.
+// Reusing the buffer avoids allocating new data for each template, e.g. when building many locations,
+// as time.LoadLocationFromTZData does not retain the data.
+// If the template is not valid, it returns dst unchanged and the same error as TZData.
+func AppendTZData(dst []byte, template Template) ([]byte, error) {
+	var e Encoder
+	return e.Append(dst, template)
+}
+
+// IndicatorMode selects the standard/wall and UT/local indicators of TZif data, see Encoder.Indicators.
+type IndicatorMode int
+
+const (
+	// UTIndicators writes all indicators as 1, i.e. UT, which is how the transition times are stored.
+	// This is the default.
+	UTIndicators IndicatorMode = iota
+
+	// WallIndicators writes all indicators as 0, i.e. wall clock local time, for readers that expect
+	// the indicators zic writes for most zones.
+	// The data needs Decoder.IgnoreIndicators to be decoded.
+	WallIndicators
+
+	// NoIndicators omits the indicators, so that their counts in the header are zero,
+	// which readers treat the same as WallIndicators.
+	NoIndicators
+)
+
+// value returns the value of the indicators written in mode m.
+func (m IndicatorMode) value() byte {
+	if m == UTIndicators {
+		return 1
+	}
+	return 0
+}
+
+// Encoder converts templates to TZif data.
+// Fields of the Encoder configure how the templates are validated and encoded.
+// The zero value encodes the same way as TZData.
+type Encoder struct {
+	// RoundOffsets rounds zone offsets that are not a whole number of seconds to the nearest second.
+	// By default, such offsets are rejected as TZif can only represent whole seconds.
+	// Each rounded offset is reported to Warn.
+	RoundOffsets bool
+
+	// StrictDesignations only allows zone names that RFC 8536 recommends: 3 to 6 ASCII alphanumeric
+	// characters, '-' or '+'.
+	// By default, any name that can be stored in TZif and read by Go is accepted,
+	// i.e. a name without NUL bytes and newlines.
+	StrictDesignations bool
+
+	// StrictContinuity returns ErrDiscontinuity as an error instead of reporting it to Warn.
+	// RFC 8536 requires the zone of the last change to agree with Extend at the time of the last change.
+	// Go uses Extend from the time of the last change, so the zone of the last change is not used at all
+	// and a disagreement usually indicates a bug in the data.
+	StrictContinuity bool
+
+	// SelfCheck decodes the encoded data again and compares the result with the template,
+	// returning ErrSelfCheck if they differ.
+	// This is a safety net against bugs in the encoder, which doubles the cost of encoding.
+	SelfCheck bool
+
+	// PreserveVersion encodes templates with the TZif Version they were decoded from instead of version 3.
+	// Version 1 data has only 32-bit transition times and no footer, so templates with changes outside
+	// the 32-bit range or with Extend are rejected; use it for tools that need byte-compatible output
+	// for version 1 input.
+	PreserveVersion bool
+
+	// Version selects the TZif version to encode templates with, 1 to 3, taking precedence over PreserveVersion.
+	// Zero means version 3, or the version of the template with PreserveVersion.
+	// Version 2 is understood by more readers, but its footer can't use the extensions of version 3:
+	// transition times with hours outside 0 to 24, which tzdata uses for year-round daylight saving time.
+	// Version 1 has the restrictions described at PreserveVersion.
+	// Templates that can't be represented in the version are rejected with a descriptive error.
+	Version int
+
+	// KeepTrailing writes Template.Trailing after the footer, so that archival tools don't lose
+	// nonstandard data on round-trip.
+	// Go's time package ignores Extend of such data, see Template.Trailing.
+	KeepTrailing bool
+
+	// StrictLocalTimeTypes writes the zones as local time types without the copy of the first zone that is
+	// added as local time type 0 by default, so that the data has no spurious extra type and type 0 is
+	// the zone in effect before the first change, as RFC 8536 specifies.
+	// Templates without Zones get the standard time zone of Extend as their only type.
+	// Go's time package uses a different zone before the first change if type 0 is used by a change,
+	// see ChooseFirstZone; such templates are reported to Warn with ErrFirstZoneMismatch.
+	StrictLocalTimeTypes bool
+
+	// Designations lays out the time zone designations, nil means SharedSuffixDesignations.
+	Designations DesignationPacker
+
+	// AbbreviateDesignations replaces the longest zone names with numeric designations like "+0530",
+	// as zic's %z format does, until the designations fit into the 255 bytes TZif provides for them.
+	// By default, templates with too long designations are rejected with ErrDesignationsTooLong.
+	// Each rewritten name is reported to Warn as a *FieldError wrapping ErrDesignationRewritten,
+	// after the template passed validation.
+	// Extend is left as is, so rewriting the zone of the last change is reported as a discontinuity too.
+	AbbreviateDesignations bool
+
+	// Warn, if not nil, is called for each problem found in the template that does not prevent
+	// encoding it.
+	Warn func(err error)
+
+	// Indicators selects the standard/wall and UT/local indicators to write.
+	// Go ignores them, but some older readers reject data whose indicators they don't expect.
+	Indicators IndicatorMode
+}
+
+// Encode converts the template to TZif data, see TZData.
+func (e *Encoder) Encode(template Template) ([]byte, error) {
+	return e.build(nil, &template)
+}
+
+// Append appends the TZif data of the template to dst, see AppendTZData.
+func (e *Encoder) Append(dst []byte, template Template) ([]byte, error) {
+	data, err := e.build(dst, &template)
+	if err != nil {
+		return dst, err
+	}
+	return data, nil
+}
+
+// NewLocation creates a new time.Location from the template, see NewLocation.
+func (e *Encoder) NewLocation(template Template) (*time.Location, error) {
+	tzData, err := e.build(nil, &template)
+	if err != nil {
+		return nil, err
+	}
+	return time.LoadLocationFromTZData(template.Name, tzData)
+}
+
+// Size returns the length of the data Encode would return for the template, see TZDataSize.
+func (e *Encoder) Size(template Template) (int, error) {
+	// The layout of designations depends on the order of zones, see prepare.
+	prepared, rewrites := e.prepare(&template)
+	l, err := e.computeLayout(prepared)
+	if err != nil {
+		return 0, withTemplateName(prepared, err)
+	}
+	e.warnAll(prepared, rewrites)
+	return l.size, nil
+}
+
+// prepare returns the template in the form it is encoded in: with the first zone at index 0,
+// designations abbreviated if needed and with a zone for Extend if StrictLocalTimeTypes needs one.
+// It returns template itself if it does not need any changes, and the designations it rewrote,
+// which the caller reports to Warn once the template is known to encode.
+func (e *Encoder) prepare(template *Template) (*Template, []error) {
+	if template.FirstZoneIndex != 0 {
+		// Decoding always puts the first zone at index 0, so encode it that way for SelfCheck to compare equal.
+		normalized := template.withFirstZoneAtZero()
+		template = &normalized
+	}
+	template, rewrites := e.abbreviateDesignations(template)
+	if e.StrictLocalTimeTypes {
+		template = withExtendZone(template)
+	}
+	return template, rewrites
+}
 
-// maxUserZones is how many zones a user can specify.
-// There are max 255 possible local time type records in a TZif file.
-// We reserve zone 0 for the first zone, which must be unused by transitions
-// because Go's time package does not follow the RFC 8536 exactly and chooses nonzero record in
-// some cases if zone 0 is used in transitions, see time.Location.lookupFirstZone.
-const maxUserZones = 254
+func (e *Encoder) warn(template *Template, err error) {
+	if e.Warn != nil {
+		e.Warn(withTemplateName(template, err))
+	}
+}
+
+func (e *Encoder) warnAll(template *Template, errs []error) {
+	for _, err := range errs {
+		e.warn(template, err)
+	}
+}
+
+// withTemplateName adds the name of the template to err, so that errors are easier to attribute
+// when encoding many templates.
+// The result wraps err, so errors.Is and errors.As work as before.
+func withTemplateName(template *Template, err error) error {
+	if template.Name == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", template.Name, err)
+}
+
+const headerSize = 4 + 1 + 15 + 6*4 // magic + ver + unused + 6x count
 
 // buildTZData builds TZIF description from location template.
 // See https://datatracker.ietf.org/doc/html/rfc8536
@@ -88,121 +327,595 @@ const maxUserZones = 254
 // If V2+ data is present in TZIF stream, readers should use V2 data.
 // Go ignores the V1 data completely, in that case, so buildTZData uses empty V1 data block.
 func buildTZData(template *Template) ([]byte, error) {
-	if len(template.Zones) > maxUserZones {
-		return nil, fmt.Errorf("too many zones (%d), max is %d", len(template.Zones), maxUserZones)
+	var e Encoder
+	return e.build(nil, template)
+}
+
+// build is buildTZData with the encoder's options.
+// It appends the data to dst and returns the extended buffer.
+func (e *Encoder) build(dst []byte, template *Template) ([]byte, error) {
+	template, rewrites := e.prepare(template)
+	start := len(dst)
+	var err error
+	if len(template.Changes) == 0 && len(template.Zones) <= 1 && e.version(template) != 1 && !e.KeepTrailing &&
+		!e.StrictLocalTimeTypes && len(template.LeapSeconds) == 0 {
+		dst, err = e.buildExtendOnly(dst, template)
+	} else {
+		dst, err = e.buildGeneric(dst, template)
+	}
+	if err != nil {
+		return nil, withTemplateName(template, err)
+	}
+	e.warnAll(template, rewrites)
+	if e.SelfCheck {
+		// The synthetic zone is what makes the data decode to the template in Go's interpretation,
+		// so compare the local time types as they are without it.
+		d := Decoder{Raw: e.StrictLocalTimeTypes, IgnoreIndicators: e.Indicators == WallIndicators}
+		decoded, err := d.Decode(dst[start:])
+		if err != nil {
+			return nil, withTemplateName(template, fmt.Errorf("%w: %v", ErrSelfCheck, err))
+		}
+		if diff := diffTemplates(template, decoded); diff != "" {
+			return nil, withTemplateName(template, fmt.Errorf("%w: %s", ErrSelfCheck, diff))
+		}
+	}
+	return dst, nil
+}
+
+// diffTemplates compares what a and b encode to and describes the first difference found.
+// It returns an empty string if they are equivalent.
+// Name is not compared, as TZif does not store it.
+func diffTemplates(a, b *Template) string {
+	if len(a.Zones) != len(b.Zones) {
+		return fmt.Sprintf("%d zones != %d zones", len(a.Zones), len(b.Zones))
+	}
+	for i := range a.Zones {
+		za, zb := a.Zones[i], b.Zones[i]
+		if za.Name != zb.Name || za.Offset.Round(time.Second) != zb.Offset.Round(time.Second) || za.IsDST != zb.IsDST {
+			return fmt.Sprintf("Zones[%d]: %+v != %+v", i, za, zb)
+		}
+	}
+	if len(a.Changes) != len(b.Changes) {
+		return fmt.Sprintf("%d changes != %d changes", len(a.Changes), len(b.Changes))
+	}
+	for i := range a.Changes {
+		ca, cb := a.Changes[i], b.Changes[i]
+		if ca.Start.Unix() != cb.Start.Unix() || ca.ZoneIndex != cb.ZoneIndex {
+			return fmt.Sprintf("Changes[%d]: start %s zone %d != start %s zone %d",
+				i, formatTime(ca.Start), ca.ZoneIndex, formatTime(cb.Start), cb.ZoneIndex)
+		}
+	}
+	if a.Extend != b.Extend {
+		return fmt.Sprintf("extend %q != %q", a.Extend, b.Extend)
+	}
+	if len(a.LeapSeconds) != len(b.LeapSeconds) {
+		return fmt.Sprintf("%d leap seconds != %d leap seconds", len(a.LeapSeconds), len(b.LeapSeconds))
+	}
+	for i := range a.LeapSeconds {
+		la, lb := a.LeapSeconds[i], b.LeapSeconds[i]
+		if la.Occurrence.Unix() != lb.Occurrence.Unix() || la.Correction != lb.Correction {
+			return fmt.Sprintf("LeapSeconds[%d]: occurrence %s correction %d != occurrence %s correction %d",
+				i, formatTime(la.Occurrence), la.Correction, formatTime(lb.Occurrence), lb.Correction)
+		}
+	}
+	return ""
+}
+
+// buildGeneric appends TZif data for any template to dst.
+func (e *Encoder) buildGeneric(dst []byte, template *Template) ([]byte, error) {
+	l, err := e.computeLayout(template)
+	if err != nil {
+		return nil, err
+	}
+
+	data, rest := grow(dst, l.size)
+	if l.version > 1 {
+		// V1 header
+		rest = putHeader(rest, l.version, 0, 0, 0, 0, 0, 0)
+	}
+	// V2 header, or the only V1 header
+	rest = putHeader(rest, l.version, l.isutcnt, l.isstdcnt, l.leapcnt, l.timecnt, l.typecnt, len(l.designations))
+	// V2 data block
+	// transition times and transition types
+	// Both are written by index, so that the compiler can eliminate bounds checks in the loops.
+	if l.version == 1 {
+		transitionTimes, transitionTypes := rest[:l.timecnt*4], rest[l.timecnt*4:l.timecnt*5]
+		changes := template.Changes[:len(transitionTypes)]
+		for i := range changes {
+			binary.BigEndian.PutUint32(transitionTimes[i*4:i*4+4], uint32(changes[i].Start.Unix()))
+			transitionTypes[i] = byte(changes[i].ZoneIndex + l.typeShift)
+		}
+	} else {
+		transitionTimes, transitionTypes := rest[:l.timecnt*8], rest[l.timecnt*8:l.timecnt*9]
+		changes := template.Changes[:len(transitionTypes)]
+		for i := range changes {
+			binary.BigEndian.PutUint64(transitionTimes[i*8:i*8+8], uint64(changes[i].Start.Unix()))
+			// We add 1 to ZoneIndex if local time type record 0 is used by firstZone.
+			transitionTypes[i] = byte(changes[i].ZoneIndex + l.typeShift)
+		}
+	}
+	rest = rest[l.timecnt*(l.tsize+1):]
+	// local time type records
+	localTimeType, rest := rest[:l.typecnt*6], rest[l.typecnt*6:]
+	if l.typeShift > 0 {
+		localTimeType = putLocalTimeTypeRecord(localTimeType, l.firstZone.Offset, l.firstZone.IsDST, l.designationIndices[0])
+	}
+	for i := range template.Zones {
+		localTimeType = putLocalTimeTypeRecord(localTimeType, template.Zones[i].Offset, template.Zones[i].IsDST, l.designationIndices[i+l.typeShift])
+	}
+	// time zone designations
+	rest = rest[copy(rest, l.designations):]
+	// leap second records
+	for i := range template.LeapSeconds {
+		rest = putLeapSecondRecord(rest, l.tsize, template.LeapSeconds[i])
+	}
+	// standard/wall indicators and UT/local indicators
+	fill(rest[:l.isstdcnt+l.isutcnt], e.Indicators.value())
+	rest = rest[l.isstdcnt+l.isutcnt:]
+	if l.version > 1 {
+		// footer
+		rest[0], rest = '\n', rest[1:]
+		copy(rest, template.Extend)
+		rest = rest[len(template.Extend):]
+		rest[0], rest = '\n', rest[1:]
+	}
+	if e.KeepTrailing {
+		rest = rest[copy(rest, template.Trailing):]
+	}
+
+	// everything written, do a sanity check
+	if len(rest) != 0 {
+		panic("some data was not written")
+	}
+
+	return data, nil
+}
+
+// buildExtendOnly is a fast path of buildGeneric for templates without changes and with at most
+// one zone, such as fixed offset zones or zones described only by Extend.
+// The output is the same as from buildGeneric, but we don't need to deduplicate designations
+// and the output is allocated at once.
+func (e *Encoder) buildExtendOnly(dst []byte, template *Template) ([]byte, error) {
+	if err := e.validate(template); err != nil {
+		return nil, err
+	}
+	var zone Zone
+	if len(template.Zones) > 0 {
+		zone = template.Zones[0]
+	}
+	typecnt := len(template.Zones) + 1 // first zone is special
+	charcnt := len(zone.Name) + 1
+	if charcnt > MaxDesignationBytes {
+		return nil, fmt.Errorf("%w: charcnt=%d", ErrDesignationsTooLong, charcnt)
+	}
+
+	data, rest := grow(dst, 2*headerSize+typecnt*6+charcnt+2+len(template.Extend))
+	// V1 header
+	version := e.version(template)
+	rest = putHeader(rest, version, 0, 0, 0, 0, 0, 0)
+	// V2 header
+	rest = putHeader(rest, version, 0, 0, 0, 0, typecnt, charcnt)
+	// V2 data block
+	// local time type records, all of them use the same designation
+	for i := 0; i < typecnt; i++ {
+		rest = putLocalTimeTypeRecord(rest, zone.Offset, zone.IsDST, 0)
+	}
+	// time zone designations
+	copy(rest, zone.Name)
+	rest = rest[charcnt:]
+	// footer
+	rest[0] = '\n'
+	copy(rest[1:], template.Extend)
+	rest[len(rest)-1] = '\n'
+
+	return data, nil
+}
+
+// putHeader writes TZif header to buf and returns the rest of buf.
+func putHeader(buf []byte, version, isutcnt, isstdcnt, leapcnt, timecnt, typecnt, charcnt int) []byte {
+	header, rest := buf[:headerSize], buf[headerSize:]
+	header[0] = 'T'
+	header[1] = 'Z'
+	header[2] = 'i'
+	header[3] = 'f'
+	if version > 1 {
+		header[4] = '0' + byte(version)
+	}
+	binary.BigEndian.PutUint32(header[20:24], uint32(isutcnt))
+	binary.BigEndian.PutUint32(header[24:28], uint32(isstdcnt))
+	binary.BigEndian.PutUint32(header[28:32], uint32(leapcnt))
+	binary.BigEndian.PutUint32(header[32:36], uint32(timecnt))
+	binary.BigEndian.PutUint32(header[36:40], uint32(typecnt))
+	binary.BigEndian.PutUint32(header[40:44], uint32(charcnt))
+	return rest
+}
+
+// validate checks that the template can be encoded.
+func (e *Encoder) validate(template *Template) error {
+	if len(template.Zones) > MaxZones {
+		return fmt.Errorf("%w: %d zones, max is %d", ErrTooManyZones, len(template.Zones), MaxZones)
 	}
 	if len(template.Zones) == 0 && template.Extend == "" {
-		return nil, fmt.Errorf("either zones or extend string need to be present")
+		return ErrNoZones
+	}
+	if idx := template.FirstZoneIndex; idx != 0 && (idx < 0 || idx >= len(template.Zones)) {
+		return fmt.Errorf("%w: first zone index %d, there are %d zones", ErrZoneIndex, idx, len(template.Zones))
+	}
+	// The footer is delimited by newlines, so a newline in Extend would end it prematurely.
+	if i := strings.IndexByte(template.Extend, '\n'); i >= 0 {
+		return fmt.Errorf("%w: %q contains newline at index %d", ErrInvalidExtend, template.Extend, i)
+	}
+	if e.Version < 0 || e.Version > 3 {
+		return fmt.Errorf("%w: version %d, supported versions are 1 to 3", ErrUnsupportedVersion, e.Version)
+	}
+	var rule tzRule
+	if template.Extend != "" {
+		var err error
+		rule, err = parseTZRule(template.Extend)
+		if err != nil {
+			return err
+		}
+		if problem := rule.version3Feature(); problem != "" && e.version(template) == 2 {
+			return fmt.Errorf("%w: %q can't be stored in version 2 data: %s", ErrInvalidExtend, template.Extend, problem)
+		}
 	}
 	nchanges := int64(len(template.Changes))
-	if nchanges > math.MaxUint32 {
-		return nil, fmt.Errorf("too many changes (%d), max is %v", nchanges, int64(math.MaxUint32))
+	if nchanges > MaxChanges {
+		return fmt.Errorf("%w: %d changes, max is %d", ErrTooManyChanges, nchanges, int64(MaxChanges))
 	}
+	for i := range template.Changes {
+		if start := template.Changes[i].Start; !fitsUnix(start) {
+			return &FieldError{
+				Field:  "Changes",
+				Index:  i,
+				Err:    ErrStartOutOfRange,
+				Detail: fmt.Sprintf("start %s is not representable as seconds since Unix epoch", formatTime(start)),
+			}
+		}
+		if i > 0 {
+			// TZif stores whole seconds, so compare what will be stored.
+			prev, cur := template.Changes[i-1].Start, template.Changes[i].Start
+			switch {
+			case cur.Unix() == prev.Unix():
+				return &FieldError{
+					Field: "Changes",
+					Index: i,
+					Err:   ErrDuplicateChange,
+					Detail: fmt.Sprintf("start %s is the same second as start %s of Changes[%d]",
+						formatTime(cur), formatTime(prev), i-1),
+				}
+			case cur.Unix() < prev.Unix():
+				return &FieldError{
+					Field: "Changes",
+					Index: i,
+					Err:   ErrUnorderedChanges,
+					Detail: fmt.Sprintf("start %s is not after start %s of Changes[%d]",
+						formatTime(cur), formatTime(prev), i-1),
+				}
+			}
+		}
+		if idx := template.Changes[i].ZoneIndex; idx < 0 || idx >= len(template.Zones) {
+			return &FieldError{
+				Field:  "Changes",
+				Index:  i,
+				Err:    ErrZoneIndex,
+				Detail: fmt.Sprintf("zone index %d, there are %d zones", idx, len(template.Zones)),
+			}
+		}
+	}
+	if err := validateLeapSeconds(template.LeapSeconds); err != nil {
+		return err
+	}
+	for i := range template.Zones {
+		name := template.Zones[i].Name
+		if detail := e.checkDesignation(name); detail != "" {
+			return &FieldError{Field: "Zones", Index: i, Err: ErrInvalidDesignation, Detail: detail}
+		}
+		offset := template.Zones[i].Offset
+		if offset%time.Second != 0 {
+			if !e.RoundOffsets {
+				return &FieldError{
+					Field:  "Zones",
+					Index:  i,
+					Err:    ErrInvalidOffset,
+					Detail: fmt.Sprintf("zone %q offset %v is not a whole number of seconds", name, offset),
+				}
+			}
+			e.warn(template, &FieldError{
+				Field:  "Zones",
+				Index:  i,
+				Err:    ErrInvalidOffset,
+				Detail: fmt.Sprintf("zone %q offset %v rounded to %v", name, offset, offset.Round(time.Second)),
+			})
+		}
+		// RFC 8536 does not allow -2**31 as it can't be negated.
+		if seconds := offset.Round(time.Second) / time.Second; seconds <= math.MinInt32 || seconds > math.MaxInt32 {
+			return &FieldError{
+				Field:  "Zones",
+				Index:  i,
+				Err:    ErrInvalidOffset,
+				Detail: fmt.Sprintf("zone %q offset %v out of range", name, offset),
+			}
+		}
+	}
+	if len(template.Zones) > 0 && !hasStandardZone(template.Zones) {
+		e.warn(template, fmt.Errorf("%w: all %d zones are DST", ErrNoStandardZone, len(template.Zones)))
+	}
+	if template.Extend != "" && len(template.Changes) > 0 {
+		if err := e.checkContinuity(template, &rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+// withFirstZoneAtZero returns a copy of the template with the zones at index 0 and FirstZoneIndex swapped,
+// so that the first zone is at index 0, and the changes updated accordingly.
+// The template is returned unchanged if FirstZoneIndex is out of range.
+func (t *Template) withFirstZoneAtZero() Template {
+	result := *t
+	fz := t.FirstZoneIndex
+	if fz <= 0 || fz >= len(t.Zones) {
+		return result
+	}
+	result.FirstZoneIndex = 0
+	result.Zones = append([]Zone(nil), t.Zones...)
+	result.Zones[0], result.Zones[fz] = result.Zones[fz], result.Zones[0]
+	result.Changes = append([]Change(nil), t.Changes...)
+	for i := range result.Changes {
+		switch result.Changes[i].ZoneIndex {
+		case 0:
+			result.Changes[i].ZoneIndex = fz
+		case fz:
+			result.Changes[i].ZoneIndex = 0
+		}
+	}
+	return result
+}
+
+// hasStandardZone reports whether any of the zones is not DST.
+func hasStandardZone(zones []Zone) bool {
+	for i := range zones {
+		if !zones[i].IsDST {
+			return true
+		}
+	}
+	return false
+}
+
+// checkContinuity checks that the zone of the last change agrees with the extend rule.
+func (e *Encoder) checkContinuity(template *Template, rule *tzRule) error {
+	i := len(template.Changes) - 1
+	last := template.Changes[i]
+	zone := template.Zones[last.ZoneIndex]
+	ruleZone, _, _ := rule.lookup(last.Start.Unix())
+	if zone.Name == ruleZone.Name && zone.Offset.Round(time.Second) == ruleZone.Offset && zone.IsDST == ruleZone.IsDST {
+		return nil
+	}
+	err := &FieldError{
+		Field: "Changes",
+		Index: i,
+		Err:   ErrDiscontinuity,
+		Detail: fmt.Sprintf("zone %s (offset %v, DST %t) differs from zone %s (offset %v, DST %t) of the extend string",
+			zone.Name, zone.Offset, zone.IsDST, ruleZone.Name, ruleZone.Offset, ruleZone.IsDST),
+	}
+	if e.StrictContinuity {
+		return err
+	}
+	e.warn(template, err)
+	return nil
+}
+
+// minUnixTime is the earliest time for which time.Time.Unix does not overflow.
+var minUnixTime = time.Unix(math.MinInt64, 0)
+
+// fitsUnix reports whether t.Unix() is exact, i.e. it does not overflow.
+func fitsUnix(t time.Time) bool {
+	return !t.Before(minUnixTime)
+}
+
+// formatTime formats t for use in error messages.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// checkDesignation checks that the zone name can be used as a time zone designation.
+// It returns a description of the problem or an empty string if the name is fine.
+func (e *Encoder) checkDesignation(name string) string {
+	// NUL terminates the designation and newlines can confuse readers.
+	if i := strings.IndexAny(name, "\x00\n"); i >= 0 {
+		return fmt.Sprintf("%q contains invalid character %q at index %d", name, name[i], i)
+	}
+	if !e.StrictDesignations {
+		return ""
+	}
+	if len(name) < 3 || len(name) > 6 {
+		return fmt.Sprintf("%q must have 3 to 6 characters", name)
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '+') {
+			return fmt.Sprintf("%q contains invalid character %q at index %d", name, c, i)
+		}
+	}
+	return ""
+}
+
+// tzdataLayout describes the sizes of the parts of the TZif data built from a template.
+type tzdataLayout struct {
+	// version of the TZif data, see Encoder.Version.
+	version int
+	// tsize is the size of a transition time, 4 for version 1 and 8 otherwise.
+	tsize     int
+	timecnt   int
+	isutcnt   int
+	isstdcnt  int
+	leapcnt   int
+	typecnt   int
+	firstZone Zone
+	// typeShift is 1 if local time type 0 is a copy of firstZone and 0 otherwise, see Encoder.StrictLocalTimeTypes.
+	// The local time type of Zones[i] is i+typeShift.
+	typeShift int
+	// designations is the time zone designations buffer, designationIndices are the indices of the designations
+	// of the local time types within it.
+	designations       []byte
+	designationIndices []int
+	// size of the whole TZif data in bytes.
+	size int
+}
+
+// computeLayout validates the template and computes the layout of TZif data built from it.
+func (e *Encoder) computeLayout(template *Template) (tzdataLayout, error) {
+	if err := e.validate(template); err != nil {
+		return tzdataLayout{}, err
+	}
+
+	version := e.version(template)
+	tsize := 8
 	size := headerSize + // v1 header + empty v1 data block
 		headerSize // v2 header
-	// We only write transition times, transition types, local time type records, time zone designations.
+	if version == 1 {
+		if template.Extend != "" {
+			return tzdataLayout{}, fmt.Errorf("%w: version 1 data has no footer", ErrInvalidExtend)
+		}
+		for i := range template.Changes {
+			if sec := template.Changes[i].Start.Unix(); sec < math.MinInt32 || sec > math.MaxInt32 {
+				return tzdataLayout{}, &FieldError{Field: "Changes", Index: i, Err: ErrStartOutOfRange,
+					Detail: fmt.Sprintf("%s does not fit into version 1 data", formatTime(template.Changes[i].Start))}
+			}
+		}
+		if err := checkLeapSecondsV1(template.LeapSeconds); err != nil {
+			return tzdataLayout{}, err
+		}
+		tsize = 4
+		size = headerSize
+	}
+	// We write transition times, transition types, local time type records, time zone designations
+	// and the leap second records of the template.
 	// Go seems to ignore standard/wall indicators and UT/local indicators, which seems like a bug in Go, so
-	// we include them.
-	// Go does not read leap seconds, so we don't include any.
+	// we include them unless Indicators is NoIndicators.
+	// Go does not read leap seconds either, so only templates with LeapSeconds have any.
 	timecnt := len(template.Changes)
+	leapcnt := len(template.LeapSeconds)
 	isutcnt := timecnt
 	isstdcnt := timecnt
-	typecnt := len(template.Zones) + 1 // first zone is special
+	if e.Indicators == NoIndicators {
+		isutcnt, isstdcnt = 0, 0
+	}
+	typeShift := e.typeShift()
+	if e.StrictLocalTimeTypes {
+		e.checkFirstZone(template)
+	}
+	typecnt := len(template.Zones) + typeShift
 	var firstZone Zone
 	if len(template.Zones) > 0 {
 		firstZone = template.Zones[0]
 	}
-	zd := zoneDesignations{
-		names:   make([]string, 0, typecnt),
-		offsets: make([]int, 0, typecnt),
-	}
 	// Build time zone designations.
 	// We need to deduplicate them because the index into time zone designations is only a single byte.
-	zd.add(firstZone.Name)
-	for i := range template.Zones {
-		zd.add(template.Zones[i].Name)
-	}
-	if zd.charcnt > math.MaxUint8 {
-		return nil, fmt.Errorf("time zone designators don't fit into limit, charcnt=%d", zd.charcnt)
+	designations, designationIndices, err := e.layoutDesignations(template.Zones)
+	if err != nil {
+		return tzdataLayout{}, err
 	}
 	// Add the size of the V2 data block.
-	dataBlockSize := timecnt*8 + timecnt + typecnt*6 + zd.charcnt + isstdcnt + isutcnt
+	dataBlockSize := timecnt*tsize + timecnt + typecnt*6 + len(designations) + leapcnt*(tsize+4) + isstdcnt + isutcnt
 	size += dataBlockSize
-	// Add the size of footer.
-	size += 2 + len(template.Extend)
+	if version > 1 {
+		// Add the size of footer.
+		size += 2 + len(template.Extend)
+	}
+	if e.KeepTrailing {
+		size += len(template.Trailing)
+	}
 
-	data := make([]byte, size)
-	// V1 header
-	v1Header, rest := data[:headerSize], data[headerSize:]
-	v1Header[0] = 'T'
-	v1Header[1] = 'Z'
-	v1Header[2] = 'i'
-	v1Header[3] = 'f'
-	v1Header[4] = '3' // version
-	// V2 header
-	v2Header, rest := rest[:headerSize], rest[headerSize:]
-	v2Header[0] = 'T'
-	v2Header[1] = 'Z'
-	v2Header[2] = 'i'
-	v2Header[3] = 'f'
-	v2Header[4] = '3' // version
-	binary.BigEndian.PutUint32(v2Header[20:24], uint32(isutcnt))
-	binary.BigEndian.PutUint32(v2Header[24:28], uint32(isstdcnt))
-	binary.BigEndian.PutUint32(v2Header[32:36], uint32(timecnt))
-	binary.BigEndian.PutUint32(v2Header[36:40], uint32(typecnt))
-	binary.BigEndian.PutUint32(v2Header[40:44], uint32(zd.charcnt))
-	// V2 data block
-	// transition times
-	transitionTimes, rest := rest[:timecnt*8], rest[timecnt*8:]
-	for i := range template.Changes {
-		if i > 0 && !template.Changes[i].Start.After(template.Changes[i-1].Start) {
-			return nil, fmt.Errorf("zone changes must be in strictly ascending order")
-		}
-		binary.BigEndian.PutUint64(transitionTimes[:8], uint64(template.Changes[i].Start.Unix()))
-		transitionTimes = transitionTimes[8:]
+	return tzdataLayout{
+		timecnt:            timecnt,
+		isutcnt:            isutcnt,
+		isstdcnt:           isstdcnt,
+		leapcnt:            leapcnt,
+		typecnt:            typecnt,
+		version:            version,
+		tsize:              tsize,
+		firstZone:          firstZone,
+		typeShift:          typeShift,
+		designations:       designations,
+		designationIndices: designationIndices,
+		size:               size,
+	}, nil
+}
+
+// withExtendZone returns the template with the standard time zone of Extend as its only zone
+// if it has no zones, so that the data has a local time type without the synthetic zone.
+func withExtendZone(template *Template) *Template {
+	if len(template.Zones) > 0 || template.Extend == "" {
+		return template
 	}
-	// transition types
-	transitionTypes, rest := rest[:timecnt], rest[timecnt:]
-	for i := range template.Changes {
-		// We add 1 to ZoneIndex because local time type record 0 is used by firstZone.
-		transitionTypes[0] = byte(template.Changes[i].ZoneIndex + 1)
-		transitionTypes = transitionTypes[1:]
+	rule, err := parseTZRule(template.Extend)
+	if err != nil {
+		// Reported by validate.
+		return template
 	}
-	// local time type records
-	localTimeType, rest := rest[:typecnt*6], rest[typecnt*6:]
-	localTimeType = putLocalTimeTypeRecord(localTimeType, firstZone.Offset, firstZone.IsDST, zd.offsets[0])
-	for i := range template.Zones {
-		localTimeType = putLocalTimeTypeRecord(localTimeType, template.Zones[i].Offset, template.Zones[i].IsDST, zd.offsets[i+1])
+	result := *template
+	result.Zones = []Zone{rule.std}
+	return &result
+}
+
+// typeShift returns the number of local time types written before those of the zones.
+// The first local time type is special, so it is a copy of the first zone unless StrictLocalTimeTypes is set.
+func (e *Encoder) typeShift() int {
+	if e.StrictLocalTimeTypes {
+		return 0
 	}
-	// time zone designations
-	for i := range zd.names {
-		n := copy(rest, zd.names[i])
-		rest = rest[n+1:]
+	return 1
+}
+
+// checkFirstZone reports to Warn if Go uses a different zone than Zones[0] before the first change
+// of data without the synthetic zone.
+func (e *Encoder) checkFirstZone(template *Template) {
+	zeroIsUsed := false
+	for i := range template.Changes {
+		zeroIsUsed = zeroIsUsed || template.Changes[i].ZoneIndex == 0
 	}
-	// no leap second records
-	// standard/wall indicators and UT/local indicators
-	// We are always using UT, so all indicators are 1.
-	fill(rest[:isstdcnt+isutcnt], 1)
-	rest = rest[isstdcnt+isutcnt:]
-	// footer
-	rest[0], rest = '\n', rest[1:]
-	copy(rest, template.Extend)
-	rest = rest[len(template.Extend):]
-	rest[0], rest = '\n', rest[1:]
+	if i, reason := chooseFirstZone(template.Zones, template.Changes, zeroIsUsed); i != 0 {
+		e.warn(template, fmt.Errorf("%w: Go uses zone %d: %s", ErrFirstZoneMismatch, i, reason))
+	}
+}
 
-	// everything written, do a sanity check
-	if len(rest) != 0 {
-		panic("some data was not written")
+// designationNames returns the names of the local time types of zones, with the synthetic first zone
+// if typeShift is 1.
+func designationNames(zones []Zone, typeShift int) []string {
+	names := make([]string, 0, len(zones)+typeShift)
+	if typeShift > 0 {
+		var firstZone Zone
+		if len(zones) > 0 {
+			firstZone = zones[0]
+		}
+		names = append(names, firstZone.Name)
 	}
+	for i := range zones {
+		names = append(names, zones[i].Name)
+	}
+	return names
+}
 
-	return data, nil
+// version returns the TZif version to encode the template with.
+func (e *Encoder) version(template *Template) int {
+	if e.Version != 0 {
+		return e.Version
+	}
+	if e.PreserveVersion && template.Version >= 1 && template.Version <= 3 {
+		return template.Version
+	}
+	return 3
 }
 
 // zoneDesignations builds the buffer that holds zone names.
 type zoneDesignations struct {
 	charcnt int
-	names   []string
+	// names are the distinct designations stored, starts are their offsets.
+	names  []string
+	starts []int
+	// offsets are the designation offsets of the added zones, in the order of add calls.
 	offsets []int
 }
 
@@ -210,19 +923,20 @@ func (zd *zoneDesignations) add(name string) {
 	for i := 0; i < len(zd.names); i++ {
 		if strings.HasSuffix(zd.names[i], name) {
 			// Reuse existing record.
-			zd.offsets = append(zd.offsets, zd.offsets[i]+len(zd.names[i])-len(name))
+			zd.offsets = append(zd.offsets, zd.starts[i]+len(zd.names[i])-len(name))
 			return
 		}
 	}
 	// Add new record.
 	zd.names = append(zd.names, name)
+	zd.starts = append(zd.starts, zd.charcnt)
 	zd.offsets = append(zd.offsets, zd.charcnt)
 	zd.charcnt += len(name) + 1
 }
 
 func putLocalTimeTypeRecord(buf []byte, offset time.Duration, isDST bool, nameOffset int) []byte {
 	record, rest := buf[:6], buf[6:]
-	binary.BigEndian.PutUint32(record[0:4], uint32(offset/time.Second))
+	binary.BigEndian.PutUint32(record[0:4], uint32(offset.Round(time.Second)/time.Second))
 	if isDST {
 		record[4] = 1
 	}
@@ -230,6 +944,13 @@ func putLocalTimeTypeRecord(buf []byte, offset time.Duration, isDST bool, nameOf
 	return rest
 }
 
+// grow extends dst by n zero bytes and returns the extended buffer and the added bytes.
+func grow(dst []byte, n int) ([]byte, []byte) {
+	start := len(dst)
+	dst = append(dst, make([]byte, n)...)
+	return dst, dst[start:]
+}
+
 // fill the buffer with a constant value.
 func fill(buffer []byte, value byte) {
 	l := len(buffer)
@@ -242,129 +963,148 @@ func fill(buffer []byte, value byte) {
 	}
 }
 
-var (
-	errInvalid            = errors.New("timezones: invalid tzdata")
-	errUnsupportedVersion = errors.New("timezones: unsupported tzdata version")
-	errTooManyZones       = errors.New("timezones: too many zones")
-	errStdUT              = errors.New("timezones: unsupported isstd/isut indicator values")
-)
-
 // LoadTZData into a template.
+//
+// LoadTZData is safe to use with untrusted input: malformed data results in an error, never a panic
+// or an out-of-range template. The memory allocated is proportional to the length of tzdata.
+// Use a Decoder with MaxChanges or MaxDataSize set to limit it further.
 func LoadTZData(tzdata []byte) (*Template, error) {
-	if len(tzdata) < headerSize {
-		return nil, errInvalid
-	}
-	header := tzdata[:headerSize]
-	if header[0] != 'T' || header[1] != 'Z' || header[2] != 'i' || header[3] != 'f' {
-		return nil, errInvalid
-	}
-	var version int
-	switch header[4] {
-	case 0:
-		version = 1
-	case '2':
-		version = 2
-	case '3':
-		version = 3
-	default:
-		return nil, errUnsupportedVersion
+	var d Decoder
+	return d.Decode(tzdata)
+}
+
+// LoadTZDataNamed is like LoadTZData, but sets the Name of the returned template to name,
+// usually the name of the location, e.g. "Europe/Bratislava", and adds it to errors.
+func LoadTZDataNamed(name string, tzdata []byte) (*Template, error) {
+	template, err := LoadTZData(tzdata)
+	if err != nil {
+		return nil, withTemplateName(&Template{Name: name}, err)
 	}
+	template.Name = name
+	return template, nil
+}
 
-	var isutcnt, isstdcnt, leapcnt, timecnt, typecnt, charcnt uint32
-	isutcnt = binary.BigEndian.Uint32(header[20:24])
-	isstdcnt = binary.BigEndian.Uint32(header[24:28])
-	leapcnt = binary.BigEndian.Uint32(header[28:32])
-	timecnt = binary.BigEndian.Uint32(header[32:36])
-	typecnt = binary.BigEndian.Uint32(header[36:40])
-	charcnt = binary.BigEndian.Uint32(header[40:44])
-	rest := tzdata[headerSize:]
-	tsize := 4 // v1 times are 32 bit
-	size := uint64(timecnt)*uint64(tsize+1) +
-		uint64(typecnt)*6 +
-		uint64(charcnt) +
-		uint64(leapcnt)*uint64(tsize+4) +
-		uint64(isstdcnt) +
-		uint64(isutcnt)
-	if uint64(len(rest)) < size {
-		return nil, errInvalid
+// splitFooter splits the data after the data block into the TZ string of the footer and the trailing data.
+// The footer is a single line enclosed in newlines, anything after it is nonstandard.
+// If the data does not start with a footer, all of it is trailing.
+// Trailing is nil if there is no data after the footer.
+func splitFooter(rest []byte) (extend string, hasFooter bool, trailing []byte) {
+	if len(rest) > 0 && rest[0] == '\n' {
+		if end := bytes.IndexByte(rest[1:], '\n'); end >= 0 {
+			extend, hasFooter = string(rest[1:1+end]), true
+			rest = rest[2+end:]
+		}
 	}
-	if size > math.MaxInt {
-		return nil, errInvalid
+	if len(rest) == 0 {
+		return extend, hasFooter, nil
 	}
-	if version > 1 {
-		// skip V1 data block
-		rest = rest[size:]
-		// read V2 header
-		if len(rest) < headerSize {
-			return nil, errInvalid
-		}
-		header, rest = rest[:headerSize], rest[headerSize:]
+	return extend, hasFooter, rest
+}
 
-		if header[0] != 'T' || header[1] != 'Z' || header[2] != 'i' || header[3] != 'f' {
-			return nil, errInvalid
-		}
+// Decoder decodes TZif data into templates.
+//
+// Unlike LoadTZData, a Decoder reuses the memory of the template it returned in the previous call to Decode.
+// This reduces allocations when decoding a large number of files sequentially.
+// Callers must copy the parts of the returned template they want to keep before calling Decode again.
+//
+// The zero value is ready to use.
+// A Decoder must not be used by multiple goroutines simultaneously.
+type Decoder struct {
+	// MaxDataSize is the maximum length of the TZif data in bytes, zero means no limit.
+	// Longer data is rejected with ErrLimitExceeded before it is parsed.
+	MaxDataSize int
 
-		if header[4] != tzdata[4] {
-			return nil, errInvalid
-		}
-		tsize = 8
+	// MaxChanges is the maximum number of transitions in the decoded data block, zero means no limit.
+	// Data with more transitions is rejected with ErrLimitExceeded before memory for them is allocated.
+	MaxChanges int
 
-		isutcnt = binary.BigEndian.Uint32(header[20:24])
-		isstdcnt = binary.BigEndian.Uint32(header[24:28])
-		leapcnt = binary.BigEndian.Uint32(header[28:32])
-		timecnt = binary.BigEndian.Uint32(header[32:36])
-		typecnt = binary.BigEndian.Uint32(header[36:40])
-		charcnt = binary.BigEndian.Uint32(header[40:44])
-		size = uint64(timecnt)*uint64(tsize+1) +
-			uint64(typecnt)*6 +
-			uint64(charcnt) +
-			uint64(leapcnt)*uint64(tsize+4) +
-			uint64(isstdcnt) +
-			uint64(isutcnt)
-		if uint64(len(rest)) < size {
-			return nil, errInvalid
-		}
-		if size > math.MaxInt {
-			return nil, errInvalid
-		}
+	// AllowFooterOnly accepts data without any local time types as long as it has no transitions and
+	// its footer holds a valid TZ string. The decoded template has no Zones, only Extend.
+	// RFC 8536 requires at least one local time type, so such data is rejected with ErrInvalid by default.
+	AllowFooterOnly bool
+
+	// StrictDesignationIndices rejects local time types whose designation index points into the middle of
+	// another designation instead of the start of one.
+	// RFC 8536 allows such suffix sharing and TZData uses it, but some validators flag it.
+	// See SuffixDesignations to find the types that share designations.
+	StrictDesignationIndices bool
+
+	// Raw disables the heuristics that make the decoded template behave like Go's time package
+	// and that undo the synthetic zone added by TZData.
+	// The zones are returned in the order of the local time type records, so Zones[0] is local time type 0,
+	// which RFC 8536 uses before the first transition. Go may use a different zone there,
+	// see AnalyzeCompatibility.
+	Raw bool
+
+	// IgnoreIndicators accepts standard/wall and UT/local indicators other than 1, like the 0 indicators
+	// zic writes for most zones or WallIndicators, and ignores them like Go does.
+	// Such data is rejected with ErrUnsupportedIndicators by default, as the transition times
+	// of templates are always UT.
+	IgnoreIndicators bool
+
+	template Template
+	changes  []Change
+	zones    []Zone
+}
+
+// Decode TZif data into a template.
+// The returned template is valid until the next call to Decode.
+func (d *Decoder) Decode(tzdata []byte) (*Template, error) {
+	if d.MaxDataSize > 0 && len(tzdata) > d.MaxDataSize {
+		return nil, fmt.Errorf("%w: %d bytes of data, limit is %d", ErrLimitExceeded, len(tzdata), d.MaxDataSize)
+	}
+	h, rest, err := parseHeaders(tzdata)
+	if err != nil {
+		return nil, err
+	}
+	if d.MaxChanges > 0 && uint64(h.timecnt) > uint64(d.MaxChanges) {
+		return nil, fmt.Errorf("%w: %d transitions, limit is %d", ErrLimitExceeded, h.timecnt, d.MaxChanges)
+	}
+	// RFC 8536 requires at least one local time type and one designation character,
+	// the rest of the decoding relies on that unless the data consists of the footer only.
+	footerOnly := h.typecnt == 0
+	if footerOnly && (!d.AllowFooterOnly || h.timecnt != 0) || !footerOnly && h.charcnt == 0 {
+		return nil, ErrInvalid
 	}
 
-	timesLen := int(timecnt) * tsize
+	timesLen := int(h.timecnt) * h.tsize
 	times, rest := rest[:timesLen], rest[timesLen:]
-	typesLen := int(timecnt)
+	typesLen := int(h.timecnt)
 	types, rest := rest[:typesLen], rest[typesLen:]
-	lttLen := int(typecnt) * 6
+	lttLen := int(h.typecnt) * 6
 	ltt, rest := rest[:lttLen], rest[lttLen:]
-	charLen := int(charcnt)
+	charLen := int(h.charcnt)
 	chars, rest := string(rest[:charLen]), rest[charLen:]
-	leapLen := int(leapcnt) * (tsize + 4)
+	leapLen := int(h.leapcnt) * (h.tsize + 4)
 	leap, rest := rest[:leapLen], rest[leapLen:]
-	_ = leap
-	isstdLen := int(isstdcnt)
+	isstdLen := int(h.isstdcnt)
 	isstd, rest := rest[:isstdLen], rest[isstdLen:]
-	isutLen := int(isutcnt)
+	isutLen := int(h.isutcnt)
 	isut, rest := rest[:isutLen], rest[isutLen:]
 
 	for i := range isstd {
-		if isstd[i] != 1 {
-			return nil, errStdUT
+		if isstd[i] != 1 && !d.IgnoreIndicators {
+			return nil, ErrUnsupportedIndicators
 		}
 	}
 
 	for i := range isut {
-		if isut[i] != 1 {
-			return nil, errStdUT
+		if isut[i] != 1 && !d.IgnoreIndicators {
+			return nil, ErrUnsupportedIndicators
 		}
 	}
 
-	changes := make([]Change, int(timecnt))
-	if version == 1 {
-		for i := 0; i < int(timecnt); i++ {
+	if d.changes == nil || cap(d.changes) < int(h.timecnt) {
+		d.changes = make([]Change, int(h.timecnt))
+	}
+	changes := d.changes[:h.timecnt]
+	if h.version == 1 {
+		for i := 0; i < int(h.timecnt); i++ {
 			changes[i].Start = time.Unix(int64(int32(binary.BigEndian.Uint32(times))), 0)
 			times = times[4:]
 		}
 	} else {
-		for i := 0; i < int(timecnt); i++ {
+		for i := 0; i < int(h.timecnt); i++ {
 			changes[i].Start = time.Unix(int64(binary.BigEndian.Uint64(times)), 0)
 			times = times[8:]
 		}
@@ -372,13 +1112,19 @@ func LoadTZData(tzdata []byte) (*Template, error) {
 
 	zeroIsUsed := false
 	for i := 0; i < typesLen; i++ {
+		if uint32(types[i]) >= h.typecnt {
+			return nil, ErrInvalid
+		}
 		changes[i].ZoneIndex = int(types[i])
 		if changes[i].ZoneIndex == 0 {
 			zeroIsUsed = true
 		}
 	}
 
-	zones := make([]Zone, int(typecnt))
+	if d.zones == nil || cap(d.zones) < int(h.typecnt) {
+		d.zones = make([]Zone, int(h.typecnt))
+	}
+	zones := d.zones[:h.typecnt]
 	for i := 0; i < len(zones); i++ {
 		zones[i].Offset = time.Duration(int32(binary.BigEndian.Uint32(ltt[0:4]))) * time.Second
 		switch ltt[4] {
@@ -387,11 +1133,15 @@ func LoadTZData(tzdata []byte) (*Template, error) {
 		case 1:
 			zones[i].IsDST = true
 		default:
-			return nil, errInvalid
+			return nil, ErrInvalid
 		}
 		idx := int(ltt[5])
 		if idx >= len(chars) {
-			return nil, errInvalid
+			return nil, ErrInvalid
+		}
+		if d.StrictDesignationIndices && !designationStart(chars, idx) {
+			return nil, fmt.Errorf("%w: designation index %d of local time type %d is not at the start of a designation",
+				ErrInvalid, idx, i)
 		}
 		zones[i].Name = zeroTerminated(chars[idx:])
 		ltt = ltt[6:]
@@ -400,7 +1150,10 @@ func LoadTZData(tzdata []byte) (*Template, error) {
 	// TZif says type at index 0 is always used for times before the first transition.
 	// Go implements a different algorithm.
 	// We do what Go does, so that we are compatible with the time package.
-	fz := firstZone(zones, changes, zeroIsUsed)
+	fz := 0
+	if !d.Raw {
+		fz = firstZone(zones, changes, zeroIsUsed)
+	}
 	if fz != 0 {
 		// Swap the zones and update indexes so that first zone is always at index 0.
 		zeroIsUsed = false
@@ -416,31 +1169,136 @@ func LoadTZData(tzdata []byte) (*Template, error) {
 		}
 	}
 
-	var extend string
-	if len(rest) >= 2 && rest[0] == '\n' && rest[len(rest)-1] == '\n' {
-		extend = string(rest[1 : len(rest)-1])
+	extend, _, trailing := splitFooter(rest)
+	if trailing != nil {
+		trailing = append([]byte(nil), trailing...)
 	}
 
 	// buildTZData adds a special zone 0 (so that Go always uses it as first zone and because at least one zone
 	// is required in the tzif file).
 	// If we are reading output of buildTZData, remove the first zone, so that the round-tripped Template is the same.
-	if !zeroIsUsed && len(zones) >= 2 && zones[0] == zones[1] || len(changes) == 0 && extend != "" {
+	if footerOnly {
+		if extend == "" {
+			return nil, ErrInvalid
+		}
+		if _, err := parseTZRule(extend); err != nil {
+			return nil, ErrInvalid
+		}
+	} else if !d.Raw && (!zeroIsUsed && len(zones) >= 2 && zones[0] == zones[1] || len(changes) == 0 && extend != "") {
 		zones = zones[1:]
 		for i := range changes {
 			changes[i].ZoneIndex -= 1
 		}
 	}
 
-	if len(zones) > maxUserZones {
-		// Template.Zones can have only maxUserZones so that we can always create *time.Location unambiguously.
-		return nil, errTooManyZones
+	if len(zones) > MaxZones {
+		// Template.Zones can have only MaxZones so that we can always create *time.Location unambiguously.
+		return nil, ErrTooManyZones
 	}
 
-	return &Template{
-		Zones:   zones,
-		Changes: changes,
-		Extend:  extend,
-	}, nil
+	d.template = Template{
+		Zones:       zones,
+		Changes:     changes,
+		Extend:      extend,
+		Version:     h.version,
+		Trailing:    trailing,
+		LeapSeconds: parseLeapSeconds(leap, h.tsize),
+	}
+	return &d.template, nil
+}
+
+// tzifHeader holds the fields of a TZif header.
+type tzifHeader struct {
+	version                                               int
+	isutcnt, isstdcnt, leapcnt, timecnt, typecnt, charcnt uint32
+	// tsize is the size of a transition time in the data block, 4 for V1 and 8 for V2+.
+	tsize int
+}
+
+// readHeader reads the header at the start of data.
+func readHeader(data []byte, tsize int) (tzifHeader, error) {
+	if len(data) < headerSize {
+		return tzifHeader{}, ErrInvalid
+	}
+	header := data[:headerSize]
+	if header[0] != 'T' || header[1] != 'Z' || header[2] != 'i' || header[3] != 'f' {
+		return tzifHeader{}, ErrInvalid
+	}
+	h := tzifHeader{tsize: tsize}
+	switch header[4] {
+	case 0:
+		h.version = 1
+	case '2':
+		h.version = 2
+	case '3':
+		h.version = 3
+	default:
+		return tzifHeader{}, ErrUnsupportedVersion
+	}
+	h.isutcnt = binary.BigEndian.Uint32(header[20:24])
+	h.isstdcnt = binary.BigEndian.Uint32(header[24:28])
+	h.leapcnt = binary.BigEndian.Uint32(header[28:32])
+	h.timecnt = binary.BigEndian.Uint32(header[32:36])
+	h.typecnt = binary.BigEndian.Uint32(header[36:40])
+	h.charcnt = binary.BigEndian.Uint32(header[40:44])
+	return h, nil
+}
+
+// dataSize returns the size of the data block described by the header.
+func (h *tzifHeader) dataSize() uint64 {
+	return uint64(h.timecnt)*uint64(h.tsize+1) +
+		uint64(h.typecnt)*6 +
+		uint64(h.charcnt) +
+		uint64(h.leapcnt)*uint64(h.tsize+4) +
+		uint64(h.isstdcnt) +
+		uint64(h.isutcnt)
+}
+
+// parseHeaders reads the TZif headers.
+// It returns the header of the data block that should be decoded and the data starting at that data block.
+// The data block is the V1 data block for version 1 data, for later versions the V1 data block is skipped
+// and the V2+ data block is used.
+// parseHeaders verifies that the data block fits into the returned data.
+func parseHeaders(tzdata []byte) (tzifHeader, []byte, error) {
+	h, err := readHeader(tzdata, 4) // v1 times are 32 bit
+	if err != nil {
+		return tzifHeader{}, nil, err
+	}
+	rest := tzdata[headerSize:]
+	size := h.dataSize()
+	if uint64(len(rest)) < size {
+		return tzifHeader{}, nil, ErrInvalid
+	}
+	if size > math.MaxInt {
+		return tzifHeader{}, nil, ErrInvalid
+	}
+	if h.version == 1 {
+		return h, rest, nil
+	}
+	// skip V1 data block
+	rest = rest[size:]
+	// read V2 header
+	h2, err := readHeader(rest, 8)
+	if err != nil {
+		return tzifHeader{}, nil, ErrInvalid
+	}
+	if h2.version != h.version {
+		return tzifHeader{}, nil, ErrInvalid
+	}
+	rest = rest[headerSize:]
+	size = h2.dataSize()
+	if uint64(len(rest)) < size {
+		return tzifHeader{}, nil, ErrInvalid
+	}
+	if size > math.MaxInt {
+		return tzifHeader{}, nil, ErrInvalid
+	}
+	return h2, rest, nil
+}
+
+// designationStart reports whether idx is the start of a designation in chars.
+func designationStart(chars string, idx int) bool {
+	return idx == 0 || chars[idx-1] == 0
 }
 
 func zeroTerminated(s string) string {
@@ -454,20 +1312,27 @@ func zeroTerminated(s string) string {
 
 // firstZone selects the first zone the same way as Go does.
 func firstZone(zones []Zone, changes []Change, zeroIsUsed bool) int {
+	i, _ := chooseFirstZone(zones, changes, zeroIsUsed)
+	return i
+}
+
+// chooseFirstZone is firstZone along with the reason for the choice, see ChooseFirstZone.
+func chooseFirstZone(zones []Zone, changes []Change, zeroIsUsed bool) (int, string) {
 	if !zeroIsUsed {
-		return 0
+		return 0, "zone 0 is not used by any change"
 	}
 	if len(changes) > 0 && zones[changes[0].ZoneIndex].IsDST {
 		for i := changes[0].ZoneIndex - 1; i >= 0; i-- {
 			if !zones[i].IsDST {
-				return i
+				return i, fmt.Sprintf("zone 0 is used by a change and the first change is to DST zone %d, "+
+					"so the closest standard time zone before it is used", changes[0].ZoneIndex)
 			}
 		}
 	}
 	for i := range zones {
 		if !zones[i].IsDST {
-			return i
+			return i, "zone 0 is used by a change, so the first standard time zone is used"
 		}
 	}
-	return 0
+	return 0, "zone 0 is used by a change, but there is no standard time zone to use instead"
 }
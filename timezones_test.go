@@ -1,7 +1,11 @@
 package timezones
 
 import (
+	"bytes"
+	"errors"
+	"math"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -225,6 +229,10 @@ func TestRoundtrip(t *testing.T) {
 			for i := range t2.Changes {
 				t2.Changes[i].Start = t2.Changes[i].Start.In(time.UTC)
 			}
+			if t2.Version != 3 {
+				t.Fatalf("expected version 3, got %d", t2.Version)
+			}
+			t2.Version = 0
 			if !reflect.DeepEqual(t2, &test.template) {
 				t.Fatalf("got=%+v want=%+v", t2, &test.template)
 			}
@@ -353,6 +361,14 @@ func TestZoneDesignations_Add(t *testing.T) {
 	expect([]string{"WEST", "REST"}, []int{0, 5, 1})
 	zd.add("REST")
 	expect([]string{"WEST", "REST"}, []int{0, 5, 1, 5})
+
+	// Offsets of names added after a reused record must not be shifted.
+	zd = zoneDesignations{}
+	zd.add("LMT")
+	zd.add("LMT")
+	zd.add("MMT")
+	zd.add("MMT")
+	expect([]string{"LMT", "MMT"}, []int{0, 0, 4, 4})
 }
 
 func TestFill(t *testing.T) {
@@ -365,3 +381,1286 @@ func TestFill(t *testing.T) {
 		}
 	}
 }
+
+func TestTZDataSize(t *testing.T) {
+	templates := []Template{
+		benchTemplate(),
+		{
+			Zones: []Zone{{Name: "MyFixed", Offset: time.Hour}},
+		},
+		{
+			Extend: "<MyExt>-02:23:00<MyExtDST>-03:23:00,M1.2.3/10:00:00,M2.3.4/10:00:00",
+		},
+	}
+	for _, template := range templates {
+		size, err := TZDataSize(template)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, err := TZData(template)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if size != len(data) {
+			t.Fatalf("expected size %d, got %d", len(data), size)
+		}
+	}
+}
+
+func TestTZDataSize_Invalid(t *testing.T) {
+	template := benchTemplate()
+	template.Changes[1].Start = template.Changes[0].Start
+	if _, err := TZDataSize(template); err == nil {
+		t.Fatal("expected error for changes out of order")
+	}
+	if _, err := TZDataSize(Template{}); err == nil {
+		t.Fatal("expected error for empty template")
+	}
+}
+
+func TestAppendTZData(t *testing.T) {
+	for _, template := range []Template{
+		benchTemplate(),
+		{Zones: []Zone{{Name: "MyFixed", Offset: time.Hour}}, Extend: "<MyFixed>-1"},
+	} {
+		expected, err := TZData(template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf, err := AppendTZData([]byte("prefix"), template)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(buf[:6]) != "prefix" || !bytes.Equal(buf[6:], expected) {
+			t.Fatalf("unexpected data %q", buf)
+		}
+
+		// A buffer with enough capacity is reused.
+		buf = make([]byte, 0, len(expected)+10)
+		appended, err := AppendTZData(buf, template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if &appended[0] != &buf[:1][0] || !bytes.Equal(appended, expected) {
+			t.Fatal("expected data in the supplied buffer")
+		}
+
+		e := Encoder{SelfCheck: true}
+		if appended, err = e.Append(appended[:3], template); err != nil || !bytes.Equal(appended[3:], expected) {
+			t.Fatalf("unexpected self-checked data, error %v", err)
+		}
+	}
+
+	dst := []byte("prefix")
+	buf, err := AppendTZData(dst, Template{})
+	if !errors.Is(err, ErrNoZones) || string(buf) != "prefix" {
+		t.Fatalf("expected unchanged buffer and ErrNoZones, got %q, %v", buf, err)
+	}
+}
+
+func BenchmarkAppendTZData(b *testing.B) {
+	template := benchTemplate()
+	var buf []byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var err error
+		buf, err = AppendTZData(buf[:0], template)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func largeTemplate(nchanges int) Template {
+	template := benchTemplate()
+	changes := make([]Change, nchanges)
+	start := time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := range changes {
+		changes[i].Start = start.Add(time.Duration(i) * time.Hour)
+		changes[i].ZoneIndex = i % 2
+	}
+	template.Changes = changes
+	return template
+}
+
+func BenchmarkLocationTemplate_tzdata_100k(b *testing.B) {
+	template := largeTemplate(100000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf, err := buildTZData(&template)
+		if err != nil {
+			b.Fatal(err)
+		}
+		benchTZData = buf
+	}
+}
+
+func TestBuildTZData_Large(t *testing.T) {
+	template := largeTemplate(100000)
+	tzdata, err := TZData(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t2, err := LoadTZData(tzdata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(t2.Changes) != len(template.Changes) {
+		t.Fatalf("expected %d changes, got %d", len(template.Changes), len(t2.Changes))
+	}
+	for i := range t2.Changes {
+		if !t2.Changes[i].Start.Equal(template.Changes[i].Start) || t2.Changes[i].ZoneIndex != template.Changes[i].ZoneIndex {
+			t.Fatalf("change %d differs: got %+v want %+v", i, t2.Changes[i], template.Changes[i])
+		}
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	tzdata1, err := TZData(benchTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tzdata2, err := TZData(Template{
+		Zones: []Zone{{Name: "MyFixed", Offset: 2*time.Hour + 23*time.Minute}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d Decoder
+	for _, tzdata := range [][]byte{tzdata1, tzdata2, tzdata1} {
+		got, err := d.Decode(tzdata)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected, err := LoadTZData(tzdata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("got=%+v want=%+v", got, expected)
+		}
+	}
+}
+
+func TestDecoder_ReusesMemory(t *testing.T) {
+	tzdata, err := TZData(benchTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d Decoder
+	if _, err := d.Decode(tzdata); err != nil {
+		t.Fatal(err)
+	}
+	allocs := testing.AllocsPerRun(10, func() {
+		if _, err := d.Decode(tzdata); err != nil {
+			t.Fatal(err)
+		}
+	})
+	loadAllocs := testing.AllocsPerRun(10, func() {
+		if _, err := LoadTZData(tzdata); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs >= loadAllocs {
+		t.Fatalf("expected Decoder to allocate less than LoadTZData, got %v and %v", allocs, loadAllocs)
+	}
+}
+
+func BenchmarkDecoder(b *testing.B) {
+	template := benchTemplate()
+	buf, err := buildTZData(&template)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var d Decoder
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tmpl, err := d.Decode(buf)
+		if err != nil {
+			b.Fatal(err)
+		}
+		benchLoadTZData = tmpl
+	}
+}
+
+func BenchmarkLocationTemplate_tzdata_ExtendOnly(b *testing.B) {
+	template := Template{
+		Name:   "MyExt",
+		Zones:  []Zone{{Name: "MyExt", Offset: 2*time.Hour + 23*time.Minute}},
+		Extend: "<MyExt>-02:23:00<MyExtDST>-03:23:00,M1.2.3/10:00:00,M2.3.4/10:00:00",
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf, err := buildTZData(&template)
+		if err != nil {
+			b.Fatal(err)
+		}
+		benchTZData = buf
+	}
+}
+
+func TestBuildExtendOnlyTZData(t *testing.T) {
+	extend := "<MyExt>-02:23:00<MyExtDST>-03:23:00,M1.2.3/10:00:00,M2.3.4/10:00:00"
+	templates := []Template{
+		{Extend: extend},
+		{Zones: []Zone{{Name: "MyFixed", Offset: 2*time.Hour + 23*time.Minute}}},
+		{Zones: []Zone{{Name: "MyExt", Offset: 2*time.Hour + 23*time.Minute}}, Extend: extend},
+		{Zones: []Zone{{Name: "MyDST", Offset: -time.Hour, IsDST: true}}, Changes: []Change{}},
+		{Zones: []Zone{{Name: "", Offset: 0}}},
+	}
+	var e Encoder
+	for _, template := range templates {
+		expected, err := e.buildGeneric(nil, &template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := e.buildExtendOnly(nil, &template)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(got, expected) {
+			t.Fatalf("output differs for %+v:\ngot  %q\nwant %q", template, got, expected)
+		}
+	}
+
+	invalid := []Template{
+		{},
+		{Zones: []Zone{{Name: strings.Repeat("A", 255)}}},
+	}
+	for _, template := range invalid {
+		if _, err := e.buildExtendOnly(nil, &template); err == nil {
+			t.Fatalf("expected error for %+v", template)
+		}
+		if _, err := e.buildGeneric(nil, &template); err == nil {
+			t.Fatalf("expected error for %+v", template)
+		}
+	}
+}
+
+func BenchmarkLocationTemplate_tzdataGeneric_ExtendOnly(b *testing.B) {
+	template := Template{
+		Name:   "MyExt",
+		Zones:  []Zone{{Name: "MyExt", Offset: 2*time.Hour + 23*time.Minute}},
+		Extend: "<MyExt>-02:23:00<MyExtDST>-03:23:00,M1.2.3/10:00:00,M2.3.4/10:00:00",
+	}
+	var e Encoder
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf, err := e.buildGeneric(nil, &template)
+		if err != nil {
+			b.Fatal(err)
+		}
+		benchTZData = buf
+	}
+}
+
+func TestTZData_ZoneIndexOutOfRange(t *testing.T) {
+	for _, idx := range []int{-1, 2, 255, 1000} {
+		template := benchTemplate()
+		template.Changes[7].ZoneIndex = idx
+		_, err := TZData(template)
+		if err == nil {
+			t.Fatalf("expected error for zone index %d", idx)
+		}
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Field != "Changes" || fieldErr.Index != 7 {
+			t.Fatalf("expected error to name the change, got %v", err)
+		}
+		if !errors.Is(err, ErrZoneIndex) {
+			t.Fatalf("expected ErrZoneIndex, got %v", err)
+		}
+	}
+}
+
+func TestEncoder_FirstZoneIndex(t *testing.T) {
+	cet := Zone{Name: "CET", Offset: time.Hour}
+	cest := Zone{Name: "CEST", Offset: 2 * time.Hour, IsDST: true}
+	lmt := Zone{Name: "LMT", Offset: 57*time.Minute + 44*time.Second}
+	template := Template{
+		Zones:          []Zone{cet, cest, lmt},
+		FirstZoneIndex: 2,
+		Changes: []Change{
+			{Start: time.Date(1891, time.October, 1, 0, 0, 0, 0, time.UTC), ZoneIndex: 0},
+			{Start: time.Date(1916, time.April, 30, 22, 0, 0, 0, time.UTC), ZoneIndex: 1},
+			{Start: time.Date(1916, time.September, 30, 23, 0, 0, 0, time.UTC), ZoneIndex: 0},
+		},
+	}
+	e := Encoder{SelfCheck: true}
+	loc, err := e.NewLocation(template)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name, _ := time.Date(1800, time.January, 1, 0, 0, 0, 0, time.UTC).In(loc).Zone(); name != "LMT" {
+		t.Fatalf("expected LMT before the first change, got %s", name)
+	}
+	if zone := template.ZoneAt(time.Date(1800, time.January, 1, 0, 0, 0, 0, time.UTC)); zone != lmt {
+		t.Fatalf("expected LMT before the first change, got %+v", zone)
+	}
+
+	data, err := TZData(template)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decoded, err := LoadTZData(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	normalized := template.withFirstZoneAtZero()
+	if diff := diffTemplates(decoded, &normalized); diff != "" || normalized.Zones[0] != lmt {
+		t.Fatalf("expected %+v, got %+v: %s", normalized, *decoded, diff)
+	}
+	if template.Fingerprint() != normalized.Fingerprint() {
+		t.Fatal("expected the same fingerprint as the normalized template")
+	}
+	if diffs := template.Diff(&normalized, time.Date(1800, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)); len(diffs) != 0 {
+		t.Fatalf("expected no differences, got %+v", diffs)
+	}
+
+	for _, idx := range []int{-1, 3} {
+		template.FirstZoneIndex = idx
+		if _, err := TZData(template); !errors.Is(err, ErrZoneIndex) {
+			t.Fatalf("%d: expected ErrZoneIndex, got %v", idx, err)
+		}
+	}
+}
+
+func TestTZData_InvalidOffsets(t *testing.T) {
+	offsets := []time.Duration{
+		time.Hour + time.Millisecond,
+		math.MaxInt32*time.Second + time.Second,
+		math.MinInt32 * time.Second,
+	}
+	for _, offset := range offsets {
+		for _, zones := range [][]Zone{{{Name: "A", Offset: offset}}, {{Name: "A"}, {Name: "B", Offset: offset}}} {
+			_, err := TZData(Template{Zones: zones})
+			if err == nil {
+				t.Fatalf("expected error for offset %v", offset)
+			}
+		}
+	}
+}
+
+func TestEncoder_RoundOffsets(t *testing.T) {
+	var warnings []error
+	e := Encoder{
+		RoundOffsets: true,
+		Warn: func(err error) {
+			warnings = append(warnings, err)
+		},
+	}
+	template := Template{
+		Name: "MyRounded",
+		Zones: []Zone{
+			{Name: "A", Offset: time.Hour + 600*time.Millisecond},
+			{Name: "B", Offset: 2 * time.Hour},
+		},
+		Changes: []Change{{Start: time.Date(2022, time.January, 9, 10, 0, 0, 0, time.UTC), ZoneIndex: 1}},
+	}
+	tzdata, err := e.Encode(template)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var fieldErr *FieldError
+	if len(warnings) != 1 || !errors.As(warnings[0], &fieldErr) || fieldErr.Field != "Zones" || fieldErr.Index != 0 {
+		t.Fatalf("expected a warning for zone 0, got %v", warnings)
+	}
+	t2, err := LoadTZData(tzdata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if t2.Zones[0].Offset != time.Hour+time.Second {
+		t.Fatalf("expected rounded offset, got %v", t2.Zones[0].Offset)
+	}
+
+	loc, err := e.NewLocation(template)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, offset := time.Date(2000, time.January, 1, 0, 0, 0, 0, loc).Zone()
+	if offset != 3601 {
+		t.Fatalf("expected offset 3601, got %d", offset)
+	}
+
+	size, err := e.Size(template)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if size != len(tzdata) {
+		t.Fatalf("expected size %d, got %d", len(tzdata), size)
+	}
+}
+
+func TestTZData_InvalidDesignations(t *testing.T) {
+	for _, name := range []string{"A\x00B", "A\nB", "\n", "\x00"} {
+		for _, zones := range [][]Zone{{{Name: name}}, {{Name: "A"}, {Name: name}}} {
+			_, err := TZData(Template{Zones: zones})
+			if err == nil {
+				t.Fatalf("expected error for designation %q", name)
+			}
+		}
+	}
+}
+
+func TestEncoder_StrictDesignations(t *testing.T) {
+	valid := []string{"UTC", "CEST", "+0530", "-03", "ABCDEF", "a1-+"}
+	invalid := []string{"", "AB", "ABCDEFG", "A B", "Ščť", "AB_C", "LMT\n"}
+	e := Encoder{StrictDesignations: true}
+	for _, name := range valid {
+		if _, err := e.Encode(Template{Zones: []Zone{{Name: name}}}); err != nil {
+			t.Fatalf("unexpected error for designation %q: %v", name, err)
+		}
+	}
+	for _, name := range invalid {
+		if _, err := e.Encode(Template{Zones: []Zone{{Name: name}}}); err == nil {
+			t.Fatalf("expected error for designation %q", name)
+		}
+		if name == "LMT\n" {
+			continue
+		}
+		// Relaxed mode accepts the names Go can handle.
+		if _, err := TZData(Template{Zones: []Zone{{Name: name}}}); err != nil {
+			t.Fatalf("unexpected error for designation %q in relaxed mode: %v", name, err)
+		}
+	}
+}
+
+func TestTZData_ExtendNewline(t *testing.T) {
+	extends := []string{"\n", "UTC0\n", "EST5EDT\n,M3.2.0,M11.1.0"}
+	for _, extend := range extends {
+		for _, zones := range [][]Zone{nil, {{Name: "EST", Offset: -5 * time.Hour}}, {{Name: "EST"}, {Name: "EDT"}}} {
+			_, err := TZData(Template{Zones: zones, Extend: extend})
+			if err == nil {
+				t.Fatalf("expected error for extend %q", extend)
+			}
+			if !strings.Contains(err.Error(), "newline") {
+				t.Fatalf("expected error to mention newline, got %v", err)
+			}
+		}
+	}
+}
+
+func TestTZData_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template Template
+		err      error
+		field    string
+		index    int
+	}{
+		{
+			name:     "no zones",
+			template: Template{},
+			err:      ErrNoZones,
+		},
+		{
+			name:     "too many zones",
+			template: Template{Zones: make([]Zone, MaxZones+1)},
+			err:      ErrTooManyZones,
+		},
+		{
+			name:     "designations too long",
+			template: Template{Zones: []Zone{{Name: strings.Repeat("A", 200)}, {Name: strings.Repeat("B", 200)}}},
+			err:      ErrDesignationsTooLong,
+		},
+		{
+			name:     "designations too long, single zone",
+			template: Template{Zones: []Zone{{Name: strings.Repeat("A", 300)}}},
+			err:      ErrDesignationsTooLong,
+		},
+		{
+			name:     "invalid extend",
+			template: Template{Extend: "UTC0\n"},
+			err:      ErrInvalidExtend,
+		},
+		{
+			name: "unordered changes",
+			template: Template{
+				Zones: []Zone{{Name: "A"}, {Name: "B"}},
+				Changes: []Change{
+					{Start: time.Unix(10, 0), ZoneIndex: 1},
+					{Start: time.Unix(20, 0), ZoneIndex: 0},
+					{Start: time.Unix(15, 0), ZoneIndex: 1},
+				},
+			},
+			err:   ErrUnorderedChanges,
+			field: "Changes",
+			index: 2,
+		},
+		{
+			name: "zone index",
+			template: Template{
+				Zones:   []Zone{{Name: "A"}, {Name: "B"}},
+				Changes: []Change{{Start: time.Unix(10, 0), ZoneIndex: 2}},
+			},
+			err:   ErrZoneIndex,
+			field: "Changes",
+			index: 0,
+		},
+		{
+			name:     "invalid offset",
+			template: Template{Zones: []Zone{{Name: "A"}, {Name: "B", Offset: time.Millisecond}}},
+			err:      ErrInvalidOffset,
+			field:    "Zones",
+			index:    1,
+		},
+		{
+			name:     "invalid designation",
+			template: Template{Zones: []Zone{{Name: "A\x00"}}},
+			err:      ErrInvalidDesignation,
+			field:    "Zones",
+			index:    0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := TZData(test.template)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected %v, got %v", test.err, err)
+			}
+			var fieldErr *FieldError
+			isFieldErr := errors.As(err, &fieldErr)
+			if test.field == "" {
+				if isFieldErr {
+					t.Fatalf("unexpected field error %v", err)
+				}
+				return
+			}
+			if !isFieldErr || fieldErr.Field != test.field || fieldErr.Index != test.index {
+				t.Fatalf("expected field error for %s[%d], got %v", test.field, test.index, err)
+			}
+		})
+	}
+}
+
+func TestFieldError_Error(t *testing.T) {
+	err := &FieldError{Field: "Zones", Index: 3, Err: ErrInvalidOffset, Detail: "1ms is not a whole number of seconds"}
+	expected := "timezones: invalid offset in Zones[3]: 1ms is not a whole number of seconds"
+	if err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err.Error())
+	}
+	err.Detail = ""
+	expected = "timezones: invalid offset in Zones[3]"
+	if err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestTZData_UnorderedChangesDetail(t *testing.T) {
+	template := largeTemplate(2000)
+	template.Changes[1234].Start = template.Changes[1000].Start
+	_, err := TZData(template)
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Index != 1234 {
+		t.Fatalf("expected field error for change 1234, got %v", err)
+	}
+	expected := "MyChanges: timezones: zone changes must be in strictly ascending order in Changes[1234]: " +
+		"start 1900-02-11T16:00:00Z is not after start 1900-02-21T09:00:00Z of Changes[1233]"
+	if err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestTZData_InvalidExtend(t *testing.T) {
+	_, err := TZData(Template{Extend: "EST5EDT,M3.2.0"})
+	if !errors.Is(err, ErrInvalidExtend) {
+		t.Fatalf("expected ErrInvalidExtend, got %v", err)
+	}
+}
+
+func TestEncoder_Continuity(t *testing.T) {
+	template := Template{
+		Zones: []Zone{
+			{Name: "CET", Offset: time.Hour},
+			{Name: "CEST", Offset: 2 * time.Hour, IsDST: true},
+		},
+		Changes: []Change{
+			{Start: time.Date(2022, time.March, 27, 1, 0, 0, 0, time.UTC), ZoneIndex: 1},
+			{Start: time.Date(2022, time.October, 30, 1, 0, 0, 0, time.UTC), ZoneIndex: 0},
+		},
+		Extend: "CET-1CEST,M3.5.0,M10.5.0/3",
+	}
+	var warnings []error
+	e := Encoder{
+		Warn: func(err error) {
+			warnings = append(warnings, err)
+		},
+	}
+	if _, err := e.Encode(template); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+
+	// The last change switches to CEST, but the rule says CET.
+	template.Changes[1].ZoneIndex = 1
+	if _, err := e.Encode(template); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var fieldErr *FieldError
+	if len(warnings) != 1 || !errors.As(warnings[0], &fieldErr) || fieldErr.Index != 1 || fieldErr.Err != ErrDiscontinuity {
+		t.Fatalf("expected discontinuity warning, got %v", warnings)
+	}
+
+	e.StrictContinuity = true
+	_, err := e.Encode(template)
+	if !errors.Is(err, ErrDiscontinuity) {
+		t.Fatalf("expected ErrDiscontinuity, got %v", err)
+	}
+}
+
+func TestTZData_StartOutOfRange(t *testing.T) {
+	template := benchTemplate()
+	template.Changes = template.Changes[:2]
+	template.Changes[0].Start = time.Unix(math.MinInt64, 0).Add(-time.Second)
+	_, err := TZData(template)
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Index != 0 || !errors.Is(err, ErrStartOutOfRange) {
+		t.Fatalf("expected ErrStartOutOfRange for change 0, got %v", err)
+	}
+
+	// The extreme values are fine.
+	template.Changes[0].Start = time.Unix(math.MinInt64, 0)
+	template.Changes[1].Start = time.Unix(math.MaxInt64-62135596800, 0)
+	tzdata, err := TZData(template)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t2, err := LoadTZData(tzdata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range template.Changes {
+		if !t2.Changes[i].Start.Equal(template.Changes[i].Start) {
+			t.Fatalf("expected start %v, got %v", template.Changes[i].Start, t2.Changes[i].Start)
+		}
+	}
+}
+
+func TestTZData_DuplicateChange(t *testing.T) {
+	for _, delta := range []time.Duration{0, 500 * time.Millisecond} {
+		template := benchTemplate()
+		template.Changes[5].Start = template.Changes[4].Start.Add(delta)
+		_, err := TZData(template)
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Index != 5 || !errors.Is(err, ErrDuplicateChange) {
+			t.Fatalf("expected ErrDuplicateChange for change 5, got %v", err)
+		}
+		if errors.Is(err, ErrUnorderedChanges) {
+			t.Fatalf("duplicate should not be reported as ErrUnorderedChanges: %v", err)
+		}
+	}
+}
+
+func TestEncoder_NoStandardZone(t *testing.T) {
+	var warnings []error
+	e := Encoder{
+		Warn: func(err error) {
+			warnings = append(warnings, err)
+		},
+	}
+	template := Template{
+		Zones: []Zone{
+			{Name: "DST1", Offset: time.Hour, IsDST: true},
+			{Name: "DST2", Offset: 2 * time.Hour, IsDST: true},
+		},
+		Changes: []Change{{Start: time.Date(2022, time.January, 9, 10, 0, 0, 0, time.UTC), ZoneIndex: 1}},
+	}
+	if _, err := e.Encode(template); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(warnings) != 1 || !errors.Is(warnings[0], ErrNoStandardZone) {
+		t.Fatalf("expected ErrNoStandardZone warning, got %v", warnings)
+	}
+
+	warnings = nil
+	template.Zones[1].IsDST = false
+	if _, err := e.Encode(template); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestEncoder_SelfCheck(t *testing.T) {
+	e := Encoder{SelfCheck: true, RoundOffsets: true}
+	templates := []Template{
+		benchTemplate(),
+		largeTemplate(1000),
+		{Zones: []Zone{{Name: "MyFixed", Offset: 2*time.Hour + 23*time.Minute}}},
+		{Extend: "<MyExt>-02:23:00<MyExtDST>-03:23:00,M1.2.3/10:00:00,M2.3.4/10:00:00"},
+		{
+			Zones: []Zone{
+				{Name: "A", Offset: time.Hour + 100*time.Millisecond},
+				{Name: "A", Offset: time.Hour},
+				{Name: "B", Offset: 2 * time.Hour, IsDST: true},
+			},
+			Changes: []Change{
+				{Start: time.Unix(1000, 0), ZoneIndex: 2},
+				{Start: time.Unix(2000, 0), ZoneIndex: 1},
+			},
+		},
+	}
+	for _, template := range templates {
+		tzdata, err := e.Encode(template)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected, err := TZData(template)
+		if err != nil && !errors.Is(err, ErrInvalidOffset) {
+			t.Fatal(err)
+		}
+		if err == nil && !bytes.Equal(tzdata, expected) {
+			t.Fatal("self-check must not change the output")
+		}
+	}
+}
+
+func TestDiffTemplates(t *testing.T) {
+	a := benchTemplate()
+	b := benchTemplate()
+	if diff := diffTemplates(&a, &b); diff != "" {
+		t.Fatalf("unexpected difference %q", diff)
+	}
+	b.Changes[3].ZoneIndex = 1
+	if diff := diffTemplates(&a, &b); !strings.HasPrefix(diff, "Changes[3]") {
+		t.Fatalf("unexpected difference %q", diff)
+	}
+	b = benchTemplate()
+	b.Zones[1].IsDST = false
+	if diff := diffTemplates(&a, &b); !strings.HasPrefix(diff, "Zones[1]") {
+		t.Fatalf("unexpected difference %q", diff)
+	}
+	b = benchTemplate()
+	b.Extend = "UTC0"
+	if diff := diffTemplates(&a, &b); !strings.HasPrefix(diff, "extend") {
+		t.Fatalf("unexpected difference %q", diff)
+	}
+}
+
+func TestLoadTZData_Malformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data rawTZif
+	}{
+		{
+			name: "no types",
+			data: rawTZif{version: '2', footer: "\nUTC0\n"},
+		},
+		{
+			name: "no chars",
+			data: rawTZif{
+				version: '2',
+				ltt:     []rawLocalTimeType{{utoff: 0, isdst: 0, idx: 0}},
+				footer:  "\n\n",
+			},
+		},
+		{
+			name: "transition type out of range",
+			data: rawTZif{
+				version: '2',
+				times:   []int64{100},
+				types:   []byte{1},
+				ltt:     []rawLocalTimeType{{utoff: 0, isdst: 0, idx: 0}},
+				chars:   "UTC\x00",
+				footer:  "\n\n",
+			},
+		},
+		{
+			name: "designation index out of range",
+			data: rawTZif{
+				version: '2',
+				ltt:     []rawLocalTimeType{{utoff: 0, isdst: 0, idx: 4}},
+				chars:   "UTC\x00",
+				footer:  "\n\n",
+			},
+		},
+		{
+			name: "invalid isdst",
+			data: rawTZif{
+				version: 0,
+				ltt:     []rawLocalTimeType{{utoff: 0, isdst: 2, idx: 0}},
+				chars:   "UTC\x00",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := LoadTZData(test.data.bytes())
+			if !errors.Is(err, ErrInvalid) {
+				t.Fatalf("expected ErrInvalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDecoder_Limits(t *testing.T) {
+	tzdata, err := TZData(benchTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := benchTemplate()
+	d := Decoder{MaxChanges: len(template.Changes), MaxDataSize: len(tzdata)}
+	if _, err := d.Decode(tzdata); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, d := range []Decoder{{MaxChanges: len(template.Changes) - 1}, {MaxDataSize: len(tzdata) - 1}} {
+		if _, err := d.Decode(tzdata); !errors.Is(err, ErrLimitExceeded) {
+			t.Fatalf("expected ErrLimitExceeded, got %v", err)
+		}
+	}
+}
+
+func TestDecoder_AllowFooterOnly(t *testing.T) {
+	data := rawTZif{version: '2', footer: "\nCET-1CEST,M3.5.0,M10.5.0/3\n"}
+	if _, err := LoadTZData(data.bytes()); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid, got %v", err)
+	}
+	d := Decoder{AllowFooterOnly: true}
+	template, err := d.Decode(data.bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Template{Zones: []Zone{}, Changes: []Change{}, Extend: "CET-1CEST,M3.5.0,M10.5.0/3", Version: 2}
+	if !reflect.DeepEqual(*template, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, *template)
+	}
+	if _, err := NewLocation(*template); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, data := range []rawTZif{
+		{version: '2', footer: "\n\n"},
+		{version: '2', footer: "\nnot a rule\n"},
+		{version: '2', times: []int64{100}, types: []byte{0}, footer: "\nUTC0\n"},
+		{version: 0},
+	} {
+		if _, err := d.Decode(data.bytes()); !errors.Is(err, ErrInvalid) {
+			t.Fatalf("expected ErrInvalid for %+v, got %v", data, err)
+		}
+	}
+}
+
+func TestEncoder_ErrorsNameTemplate(t *testing.T) {
+	template := Template{
+		Name:  "Custom/Zone",
+		Zones: []Zone{{Name: "CUS", Offset: time.Hour + time.Millisecond}},
+	}
+	var warnings []error
+	e := Encoder{Warn: func(err error) { warnings = append(warnings, err) }}
+	expected := `Custom/Zone: timezones: invalid offset in Zones[0]: zone "CUS" offset 1h0m0.001s is not a whole number of seconds`
+	for _, f := range []func() error{
+		func() error { _, err := e.Encode(template); return err },
+		func() error { _, err := e.Size(template); return err },
+		func() error { _, err := e.NewLocation(template); return err },
+	} {
+		err := f()
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Err != ErrInvalidOffset {
+			t.Fatalf("expected field error, got %v", err)
+		}
+		if err.Error() != expected {
+			t.Fatalf("expected %q, got %q", expected, err.Error())
+		}
+	}
+
+	e.RoundOffsets = true
+	if _, err := e.Encode(template); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0].Error(), "Custom/Zone: ") {
+		t.Fatalf("expected warning naming the template, got %v", warnings)
+	}
+
+	template.Name = ""
+	if _, err := TZData(template); err == nil || strings.HasPrefix(err.Error(), ": ") {
+		t.Fatalf("unexpected error for unnamed template: %v", err)
+	}
+}
+
+func TestLoadTZDataNamed(t *testing.T) {
+	data, err := TZData(benchTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	template, err := LoadTZDataNamed("Custom/Bench", data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if template.Name != "Custom/Bench" {
+		t.Fatalf("expected name Custom/Bench, got %q", template.Name)
+	}
+	_, err = LoadTZDataNamed("Custom/Bench", data[:10])
+	if !errors.Is(err, ErrInvalid) || !strings.HasPrefix(err.Error(), "Custom/Bench: ") {
+		t.Fatalf("expected ErrInvalid naming the template, got %v", err)
+	}
+}
+
+func TestMustNewLocation(t *testing.T) {
+	template := Template{Name: "MyFixed", Zones: []Zone{{Name: "MyFixed", Offset: time.Hour}}}
+	if loc := MustNewLocation(template); loc.String() != "MyFixed" {
+		t.Fatalf("unexpected location %v", loc)
+	}
+	if data := MustTZData(template); len(data) == 0 {
+		t.Fatal("expected data")
+	}
+
+	template.Zones = nil
+	for _, f := range []func(){
+		func() { MustNewLocation(template) },
+		func() { MustTZData(template) },
+	} {
+		func() {
+			defer func() {
+				r := recover()
+				if msg, ok := r.(string); !ok || !strings.Contains(msg, `("MyFixed")`) {
+					t.Fatalf("expected panic naming the template, got %v", r)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestNewLocation_FirstZoneDST(t *testing.T) {
+	cest := Zone{Name: "CEST", Offset: 2 * time.Hour, IsDST: true}
+	cet := Zone{Name: "CET", Offset: time.Hour}
+	first := time.Date(2020, time.October, 25, 1, 0, 0, 0, time.UTC)
+	for _, template := range []Template{
+		{Zones: []Zone{cest, cet}, Changes: []Change{{Start: first, ZoneIndex: 1}}},
+		{Zones: []Zone{cet, cest}, FirstZoneIndex: 1, Changes: []Change{{Start: first, ZoneIndex: 0}}},
+		// The first zone is used by a change, which would make Go prefer CET if it was stored as local time type 0.
+		{Zones: []Zone{cest, cet}, Changes: []Change{{Start: first, ZoneIndex: 1}, {Start: first.AddDate(0, 5, 0), ZoneIndex: 0}}},
+	} {
+		loc, err := NewLocation(template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name, offset := first.Add(-time.Second).In(loc).Zone()
+		if name != "CEST" || offset != 7200 || !first.Add(-time.Second).In(loc).IsDST() {
+			t.Fatalf("%+v: expected CEST before the first change, got %s %d", template, name, offset)
+		}
+	}
+}
+
+func TestEncoder_PreserveVersion(t *testing.T) {
+	data := rawTZif{
+		times: []int64{100, 200},
+		types: []byte{1, 0},
+		ltt:   []rawLocalTimeType{{3600, 0, 0}, {7200, 1, 4}},
+		chars: "CET\x00CEST\x00",
+		isstd: []byte{1, 1},
+		isut:  []byte{1, 1},
+	}
+	template, err := LoadTZData(data.bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if template.Version != 1 {
+		t.Fatalf("expected version 1, got %d", template.Version)
+	}
+
+	if encoded, err := TZData(*template); err != nil || encoded[4] != '3' {
+		t.Fatalf("expected version 3 by default, got %v", err)
+	}
+
+	e := Encoder{PreserveVersion: true, SelfCheck: true}
+	encoded, err := e.Encode(*template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size, err := e.Size(*template); err != nil || size != len(encoded) {
+		t.Fatalf("Size returned %d, %v, encoded %d bytes", size, err, len(encoded))
+	}
+	info, err := QuickInfo(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != 1 || info.TransitionCount != 2 || info.HasFooter {
+		t.Fatalf("unexpected info %+v", info)
+	}
+	loc, err := time.LoadLocationFromTZData("", encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, _ := time.Unix(150, 0).In(loc).Zone(); name != "CEST" {
+		t.Fatalf("expected CEST, got %s", name)
+	}
+
+	v2 := *template
+	v2.Version = 2
+	v2.Extend = "CET-1CEST,M3.5.0,M10.5.0/3"
+	encoded, err = e.Encode(v2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encoded[4] != '2' || encoded[headerSize+4] != '2' {
+		t.Fatalf("expected version 2 headers, got %q and %q", encoded[4], encoded[headerSize+4])
+	}
+
+	withExtend := *template
+	withExtend.Extend = "CET-1"
+	if _, err := e.Encode(withExtend); !errors.Is(err, ErrInvalidExtend) {
+		t.Fatalf("expected ErrInvalidExtend, got %v", err)
+	}
+	outOfRange := *template
+	outOfRange.Changes = []Change{{Start: time.Date(2040, time.January, 1, 0, 0, 0, 0, time.UTC), ZoneIndex: 1}}
+	if _, err := e.Encode(outOfRange); !errors.Is(err, ErrStartOutOfRange) {
+		t.Fatalf("expected ErrStartOutOfRange, got %v", err)
+	}
+}
+
+func TestEncoder_Version(t *testing.T) {
+	template := Template{
+		Zones:   []Zone{{Name: "CET", Offset: time.Hour}, {Name: "CEST", Offset: 2 * time.Hour, IsDST: true}},
+		Changes: []Change{{Start: time.Date(2020, time.March, 29, 1, 0, 0, 0, time.UTC), ZoneIndex: 1}},
+		Extend:  "CET-1CEST,M3.5.0,M10.5.0/3",
+	}
+	for _, version := range []int{2, 3} {
+		e := Encoder{Version: version, SelfCheck: true}
+		encoded, err := e.Encode(template)
+		if err != nil {
+			t.Fatalf("version %d: unexpected error: %v", version, err)
+		}
+		if encoded[4] != '0'+byte(version) || encoded[headerSize+4] != '0'+byte(version) {
+			t.Fatalf("version %d: unexpected headers %q and %q", version, encoded[4], encoded[headerSize+4])
+		}
+	}
+
+	// Version takes precedence over PreserveVersion.
+	e := Encoder{Version: 2, PreserveVersion: true}
+	preserved := template
+	preserved.Version = 3
+	if encoded, err := e.Encode(preserved); err != nil || encoded[4] != '2' {
+		t.Fatalf("expected version 2, got %v", err)
+	}
+
+	// Year-round daylight saving time needs version 3.
+	permanent := Template{
+		Zones:  []Zone{{Name: "-03", Offset: -3 * time.Hour, IsDST: true}},
+		Extend: "<-04>4<-03>,J1/0,J365/25",
+	}
+	if _, err := e.Encode(permanent); !errors.Is(err, ErrInvalidExtend) || !strings.Contains(err.Error(), "version 2") {
+		t.Fatalf("expected ErrInvalidExtend, got %v", err)
+	}
+	// Negative daylight saving time all year ends at an hour version 2 allows, but only version 3
+	// interprets the rule as covering the whole year.
+	negativePermanent := Template{
+		Zones:  []Zone{{Name: "GMT", Offset: 0, IsDST: true}},
+		Extend: "IST-1GMT0,0/0,J365/23",
+	}
+	if _, err := e.Encode(negativePermanent); !errors.Is(err, ErrInvalidExtend) || !strings.Contains(err.Error(), "all year") {
+		t.Fatalf("expected ErrInvalidExtend, got %v", err)
+	}
+	if _, err := (&Encoder{Version: 3}).Encode(negativePermanent); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	negative := template
+	negative.Extend = "CET-1CEST,M3.5.0/-1,M10.5.0/3"
+	if _, err := e.Encode(negative); !errors.Is(err, ErrInvalidExtend) {
+		t.Fatalf("expected ErrInvalidExtend, got %v", err)
+	}
+	if _, err := (&Encoder{Version: 3}).Encode(permanent); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	e = Encoder{Version: 1}
+	if _, err := e.Encode(template); !errors.Is(err, ErrInvalidExtend) {
+		t.Fatalf("expected ErrInvalidExtend, got %v", err)
+	}
+	template.Extend = ""
+	encoded, err := e.Encode(template)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if encoded[4] != 0 {
+		t.Fatalf("expected version 1, got %q", encoded[4])
+	}
+
+	for _, version := range []int{-1, 4} {
+		e := Encoder{Version: version}
+		if _, err := e.Encode(template); !errors.Is(err, ErrUnsupportedVersion) {
+			t.Fatalf("version %d: expected ErrUnsupportedVersion, got %v", version, err)
+		}
+	}
+}
+
+func TestEncoder_StrictLocalTimeTypes(t *testing.T) {
+	template := benchTemplate()
+	var warnings []error
+	e := Encoder{StrictLocalTimeTypes: true, SelfCheck: true, Warn: func(err error) {
+		warnings = append(warnings, err)
+	}}
+	data, err := e.Encode(template)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if size, err := e.Size(template); err != nil || size != len(data) {
+		t.Fatalf("Size returned %d, %v, encoded %d bytes", size, err, len(data))
+	}
+	info, err := QuickInfo(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.TypeCount != 2 {
+		t.Fatalf("expected 2 local time types, got %d", info.TypeCount)
+	}
+	strict, err := time.LoadLocationFromTZData("", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc := MustNewLocation(template)
+	for _, instant := range []time.Time{time.Unix(0, 0), template.Changes[0].Start, template.Changes[1].Start} {
+		for _, d := range []time.Duration{-time.Second, 0} {
+			name, offset := instant.Add(d).In(strict).Zone()
+			expectedName, expectedOffset := instant.Add(d).In(loc).Zone()
+			if name != expectedName || offset != expectedOffset {
+				t.Fatalf("%v: expected %s %d, got %s %d", instant.Add(d), expectedName, expectedOffset, name, offset)
+			}
+		}
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings %v", warnings)
+	}
+
+	// Templates without zones use the standard time of Extend as the only type.
+	data, err = e.Encode(Template{Extend: "<MyFixed>-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info, err = QuickInfo(data); err != nil || info.TypeCount != 1 {
+		t.Fatalf("expected 1 local time type, got %+v, %v", info, err)
+	}
+	decoded, err := LoadTZData(data)
+	if err != nil || decoded.Extend != "<MyFixed>-1" || len(decoded.Zones) != 0 {
+		t.Fatalf("unexpected template %+v, error %v", decoded, err)
+	}
+
+	// Go uses the standard time zone before the first change, not the DST zone 0.
+	mismatch := Template{
+		Zones: []Zone{{Name: "DST", Offset: 2 * time.Hour, IsDST: true}, {Name: "STD", Offset: time.Hour}},
+		Changes: []Change{
+			{Start: time.Unix(100, 0), ZoneIndex: 0},
+			{Start: time.Unix(200, 0), ZoneIndex: 1},
+		},
+	}
+	if _, err := e.Encode(mismatch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(warnings) != 1 || !errors.Is(warnings[0], ErrFirstZoneMismatch) {
+		t.Fatalf("expected ErrFirstZoneMismatch, got %v", warnings)
+	}
+}
+
+func TestEncoder_Indicators(t *testing.T) {
+	template := benchTemplate()
+	footerSize := len(template.Extend) + 2
+	for _, tc := range []struct {
+		mode     IndicatorMode
+		count    int
+		expected byte
+	}{
+		{UTIndicators, len(template.Changes), 1},
+		{WallIndicators, len(template.Changes), 0},
+		{NoIndicators, 0, 0},
+	} {
+		e := Encoder{Indicators: tc.mode, SelfCheck: true}
+		data, err := e.Encode(template)
+		if err != nil {
+			t.Fatalf("mode %d: unexpected error: %v", tc.mode, err)
+		}
+		info, err := QuickInfo(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.IsUTCount != tc.count || info.IsStdCount != tc.count {
+			t.Fatalf("mode %d: expected %d indicators, got %+v", tc.mode, tc.count, info)
+		}
+		indicators := data[len(data)-footerSize-2*tc.count : len(data)-footerSize]
+		for i, b := range indicators {
+			if b != tc.expected {
+				t.Fatalf("mode %d: expected indicator %d to be %d, got %d", tc.mode, i, tc.expected, b)
+			}
+		}
+		if _, err := time.LoadLocationFromTZData("", data); err != nil {
+			t.Fatalf("mode %d: Go rejected the data: %v", tc.mode, err)
+		}
+		if size, err := e.Size(template); err != nil || size != len(data) {
+			t.Fatalf("mode %d: Size returned %d, %v, encoded %d bytes", tc.mode, size, err, len(data))
+		}
+	}
+
+	data, err := (&Encoder{Indicators: WallIndicators}).Encode(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTZData(data); !errors.Is(err, ErrUnsupportedIndicators) {
+		t.Fatalf("expected ErrUnsupportedIndicators, got %v", err)
+	}
+	d := Decoder{IgnoreIndicators: true}
+	decoded, err := d.Decode(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(decoded.Changes) != len(template.Changes) {
+		t.Fatalf("expected %d changes, got %d", len(template.Changes), len(decoded.Changes))
+	}
+}
+
+func TestLoadTZData_Trailing(t *testing.T) {
+	data := rawTZif{
+		version: '2',
+		ltt:     []rawLocalTimeType{{3600, 0, 0}},
+		chars:   "CET\x00",
+		footer:  "\nCET-1\nX-VENDOR: data\n",
+	}
+	template, err := LoadTZData(data.bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if template.Extend != "CET-1" || string(template.Trailing) != "X-VENDOR: data\n" {
+		t.Fatalf("unexpected extend %q and trailing data %q", template.Extend, template.Trailing)
+	}
+
+	encoded, err := TZData(*template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasSuffix(encoded, []byte("\nCET-1\n")) {
+		t.Fatalf("expected trailing data to be dropped by default, got %q", encoded)
+	}
+	e := Encoder{KeepTrailing: true}
+	encoded, err = e.Encode(*template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasSuffix(encoded, []byte("\nCET-1\nX-VENDOR: data\n")) {
+		t.Fatalf("expected trailing data, got %q", encoded)
+	}
+	if size, err := e.Size(*template); err != nil || size != len(encoded) {
+		t.Fatalf("Size returned %d, %v, encoded %d bytes", size, err, len(encoded))
+	}
+	decoded, err := LoadTZData(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded.Trailing, template.Trailing) {
+		t.Fatalf("expected trailing data %q, got %q", template.Trailing, decoded.Trailing)
+	}
+
+	// Without a valid footer, all data after the data block is trailing.
+	data.footer = "garbage"
+	template, err = LoadTZData(data.bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if template.Extend != "" || string(template.Trailing) != "garbage" {
+		t.Fatalf("unexpected extend %q and trailing data %q", template.Extend, template.Trailing)
+	}
+}
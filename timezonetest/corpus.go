@@ -0,0 +1,75 @@
+package timezonetest
+
+import (
+	"encoding/binary"
+	"math/rand"
+
+	"github.com/martin-sucha/timezones"
+)
+
+// Corpus returns n TZif blobs for seeding fuzzers of timezones.LoadTZData and of other TZif consumers.
+// The same seed and n always produce the same blobs.
+//
+// About half of the blobs are valid data encoded from templates created by Generate.
+// The rest are near-valid: the encoded data with a targeted mutation, such as truncation, a flipped byte,
+// an unexpected version, an inflated count in the header or a damaged footer.
+func Corpus(seed int64, n int) [][]byte {
+	r := rand.New(rand.NewSource(seed))
+	corpus := make([][]byte, 0, n)
+	for len(corpus) < n {
+		template := Generate(r.Int63(), Options{
+			MaxZones:   1 + r.Intn(8),
+			MaxChanges: r.Intn(20),
+			Extend:     r.Intn(2) == 0,
+		})
+		tzdata, err := timezones.TZData(template)
+		if err != nil {
+			// Generate only creates templates that can be encoded.
+			panic(err)
+		}
+		if r.Intn(2) == 0 {
+			corpus = append(corpus, tzdata)
+			continue
+		}
+		corpus = append(corpus, mutate(r, tzdata))
+	}
+	return corpus
+}
+
+// v2HeaderOffset is the offset of the version 2+ header in data created by timezones.TZData,
+// which writes an empty version 1 block.
+const v2HeaderOffset = 44
+
+// mutate returns a copy of the TZif data with a random targeted mutation.
+func mutate(r *rand.Rand, tzdata []byte) []byte {
+	data := append([]byte(nil), tzdata...)
+	switch r.Intn(6) {
+	case 0:
+		// Truncate.
+		return data[:r.Intn(len(data))]
+	case 1:
+		// Flip a byte.
+		data[r.Intn(len(data))] ^= byte(1 + r.Intn(255))
+	case 2:
+		// Unexpected version.
+		versions := []byte{0, '1', '4', '9', 0xff}
+		data[4] = versions[r.Intn(len(versions))]
+	case 3:
+		// Inflate one of the six counts of the version 2+ header.
+		offset := v2HeaderOffset + 20 + 4*r.Intn(6)
+		count := binary.BigEndian.Uint32(data[offset:])
+		binary.BigEndian.PutUint32(data[offset:], count+1+uint32(r.Intn(1000)))
+	case 4:
+		// Remove the final newline of the footer.
+		data = data[:len(data)-1]
+	case 5:
+		// Replace the footer with garbage.
+		footer := len(data) - 1
+		for footer > 0 && data[footer-1] != '\n' {
+			footer--
+		}
+		garbage := []string{"", "<", "CET-1CEST,M3.5.0", "XYZ+99:99", "A-1B,J0,J367"}
+		data = append(data[:footer], garbage[r.Intn(len(garbage))]+"\n"...)
+	}
+	return data
+}
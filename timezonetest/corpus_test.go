@@ -0,0 +1,31 @@
+package timezonetest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func TestCorpus(t *testing.T) {
+	corpus := Corpus(1, 200)
+	if len(corpus) != 200 {
+		t.Fatalf("expected 200 blobs, got %d", len(corpus))
+	}
+	valid := 0
+	for _, tzdata := range corpus {
+		if _, err := timezones.LoadTZData(tzdata); err == nil {
+			valid++
+		}
+	}
+	if valid == 0 || valid == len(corpus) {
+		t.Fatalf("expected a mix of valid and invalid blobs, got %d valid", valid)
+	}
+
+	again := Corpus(1, 200)
+	for i := range corpus {
+		if !bytes.Equal(corpus[i], again[i]) {
+			t.Fatalf("blob %d differs for the same seed", i)
+		}
+	}
+}
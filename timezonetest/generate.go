@@ -0,0 +1,132 @@
+// Package timezonetest provides helpers for testing code that works with time zones built by package timezones.
+package timezonetest
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+// Options bound the templates created by Generate.
+type Options struct {
+	// MaxZones is the maximum number of zones, at most 254. If zero, 8 is used.
+	MaxZones int
+
+	// MaxChanges is the maximum number of changes. If zero, 50 is used.
+	MaxChanges int
+
+	// Extend enables generating Extend strings.
+	// Extend may need a zone of its own, so it is only generated if MaxZones is at least 2.
+	Extend bool
+}
+
+// Generate returns a pseudo-random template that can be encoded by timezones.TZData.
+// The same seed and options always produce the same template.
+//
+// Zone names are 3 to 6 uppercase letters, offsets are whole quarter-hours between -12 and +14 hours,
+// and Zones[0] is always standard time. Changes start between 1900 and 2040.
+// If Extend is generated, it agrees with the zone of the last change.
+func Generate(seed int64, options Options) timezones.Template {
+	if options.MaxZones <= 0 {
+		options.MaxZones = 8
+	}
+	if options.MaxZones > 254 {
+		options.MaxZones = 254
+	}
+	if options.MaxChanges <= 0 {
+		options.MaxChanges = 50
+	}
+	r := rand.New(rand.NewSource(seed))
+
+	extend := options.Extend && options.MaxZones >= 2
+	maxZones := options.MaxZones
+	if extend {
+		// Keep space for a zone of the extend string.
+		maxZones--
+	}
+
+	var template timezones.Template
+	nzones := 1 + r.Intn(maxZones)
+	for i := 0; i < nzones; i++ {
+		template.Zones = append(template.Zones, timezones.Zone{
+			Name:   randomName(r),
+			Offset: randomOffset(r),
+			IsDST:  i > 0 && r.Intn(3) == 0,
+		})
+	}
+
+	nchanges := r.Intn(options.MaxChanges + 1)
+	start := time.Date(1900+r.Intn(100), time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+	maxStep := (time.Date(2040, time.January, 1, 0, 0, 0, 0, time.UTC).Unix() - start) / int64(nchanges+1)
+	for i := 0; i < nchanges; i++ {
+		start += 1 + r.Int63n(maxStep)
+		template.Changes = append(template.Changes, timezones.Change{
+			Start:     time.Unix(start, 0).UTC(),
+			ZoneIndex: r.Intn(nzones),
+		})
+	}
+
+	if extend && r.Intn(4) != 0 {
+		extendTemplate(r, &template)
+	}
+	return template
+}
+
+// extendTemplate adds an Extend string and makes the last change agree with it.
+func extendTemplate(r *rand.Rand, template *timezones.Template) {
+	std := timezones.Zone{Name: randomName(r), Offset: randomOffset(r)}
+	extend := std.Name + posixOffset(std.Offset)
+	if r.Intn(2) == 0 {
+		dst := timezones.Zone{Name: randomName(r), Offset: std.Offset + time.Hour, IsDST: true}
+		startMonth := 1 + r.Intn(12)
+		endMonth := 1 + (startMonth+2+r.Intn(6))%12
+		extend += fmt.Sprintf("%s,M%d.%d.0,M%d.%d.0/3", dst.Name, startMonth, 1+r.Intn(5), endMonth, 1+r.Intn(5))
+	}
+	template.Extend = extend
+
+	// Find the zone of the extend string in effect at the time of the last change.
+	loc, err := timezones.NewLocation(timezones.Template{Extend: extend})
+	if err != nil {
+		panic(err)
+	}
+	if len(template.Changes) == 0 {
+		// Extend applies since the beginning of time, other zones would be unused.
+		template.Zones = template.Zones[:1]
+		return
+	}
+	last := &template.Changes[len(template.Changes)-1]
+	zone := ZoneAt(loc, last.Start)
+	for i := range template.Zones {
+		if template.Zones[i] == zone {
+			last.ZoneIndex = i
+			return
+		}
+	}
+	template.Zones = append(template.Zones, zone)
+	last.ZoneIndex = len(template.Zones) - 1
+}
+
+func randomName(r *rand.Rand) string {
+	name := make([]byte, 3+r.Intn(4))
+	for i := range name {
+		name[i] = byte('A' + r.Intn(26))
+	}
+	return string(name)
+}
+
+func randomOffset(r *rand.Rand) time.Duration {
+	return time.Duration(-48+r.Intn(48+56+1)) * 15 * time.Minute
+}
+
+// posixOffset formats offset for a TZ string, which uses the opposite sign.
+func posixOffset(offset time.Duration) string {
+	sign := "-"
+	if offset <= 0 {
+		sign = ""
+		offset = -offset
+	}
+	minutes := int(offset / time.Minute)
+	return fmt.Sprintf("%s%d:%02d", sign, minutes/60, minutes%60)
+}
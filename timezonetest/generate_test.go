@@ -0,0 +1,30 @@
+package timezonetest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func TestGenerate(t *testing.T) {
+	for seed := int64(0); seed < 500; seed++ {
+		options := Options{Extend: seed%2 == 0}
+		template := Generate(seed, options)
+		if !reflect.DeepEqual(template, Generate(seed, options)) {
+			t.Fatalf("seed %d: expected the same template", seed)
+		}
+		var warnings []error
+		e := timezones.Encoder{StrictDesignations: true, Warn: func(err error) { warnings = append(warnings, err) }}
+		if _, err := e.Encode(template); err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		if len(warnings) > 0 {
+			t.Fatalf("seed %d: unexpected warnings %v", seed, warnings)
+		}
+		if len(template.Zones) > 8 || len(template.Changes) > 50 {
+			t.Fatalf("seed %d: template exceeds options: %d zones, %d changes", seed, len(template.Zones),
+				len(template.Changes))
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package timezonetest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+// Divergence describes an instant at which two locations use different zones.
+type Divergence struct {
+	At        time.Time
+	Got, Want timezones.Zone
+}
+
+func (d *Divergence) Error() string {
+	return fmt.Sprintf("at %s got %s %v (DST %t), want %s %v (DST %t)", d.At.UTC().Format(time.RFC3339),
+		d.Got.Name, d.Got.Offset, d.Got.IsDST, d.Want.Name, d.Want.Offset, d.Want.IsDST)
+}
+
+// CompareLocations compares the zones used by got and want at each of the probes.
+// It returns a *Divergence for the first probe at which they differ, or nil.
+func CompareLocations(got, want *time.Location, probes []time.Time) error {
+	for _, t := range probes {
+		g, w := ZoneAt(got, t), ZoneAt(want, t)
+		if g != w {
+			return &Divergence{At: t, Got: g, Want: w}
+		}
+	}
+	return nil
+}
+
+// CompareWithSystem compares the location built from the template with the location name loaded by
+// time.LoadLocation at each of the probes.
+// It returns a *Divergence for the first probe at which they differ, or an error if any of the locations
+// can't be loaded.
+func CompareWithSystem(name string, template timezones.Template, probes []time.Time) error {
+	want, err := time.LoadLocation(name)
+	if err != nil {
+		return err
+	}
+	got, err := timezones.NewLocation(template)
+	if err != nil {
+		return err
+	}
+	if err := CompareLocations(got, want, probes); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// Probes returns instants from from (inclusive) to to (exclusive) spaced by step.
+func Probes(from, to time.Time, step time.Duration) []time.Time {
+	var probes []time.Time
+	for t := from; t.Before(to); t = t.Add(step) {
+		probes = append(probes, t)
+	}
+	return probes
+}
+
+// ZoneAt returns the zone loc uses at t.
+func ZoneAt(loc *time.Location, t time.Time) timezones.Zone {
+	local := t.In(loc)
+	name, offset := local.Zone()
+	return timezones.Zone{Name: name, Offset: time.Duration(offset) * time.Second, IsDST: local.IsDST()}
+}
@@ -0,0 +1,46 @@
+package timezonetest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func TestCompareWithSystem(t *testing.T) {
+	if _, err := time.LoadLocation("Europe/Bratislava"); err != nil {
+		t.Skipf("zone data not available: %v", err)
+	}
+	template := timezones.Template{
+		Zones:  []timezones.Zone{{Name: "CET", Offset: time.Hour}},
+		Extend: "CET-1CEST,M3.5.0,M10.5.0/3",
+	}
+	probes := Probes(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC), 12*time.Hour)
+	if err := CompareWithSystem("Europe/Bratislava", template, probes); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	template.Extend = "CET-1CEST,M3.5.0,M10.4.0/3"
+	err := CompareWithSystem("Europe/Bratislava", template, probes)
+	var d *Divergence
+	if !errors.As(err, &d) {
+		t.Fatalf("expected divergence, got %v", err)
+	}
+	if d.At.Month() != time.October || d.Got.IsDST || !d.Want.IsDST {
+		t.Fatalf("unexpected divergence %v", d)
+	}
+
+	if err := CompareWithSystem("Nowhere/Missing", template, probes); err == nil {
+		t.Fatal("expected error for missing zone")
+	}
+}
+
+func TestProbes(t *testing.T) {
+	from := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	probes := Probes(from, from.Add(3*time.Hour), time.Hour)
+	if len(probes) != 3 || !probes[2].Equal(from.Add(2*time.Hour)) {
+		t.Fatalf("unexpected probes %v", probes)
+	}
+}
@@ -0,0 +1,91 @@
+package timezonetest
+
+import (
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+// AnomalyKind classifies a change of the zone.
+type AnomalyKind string
+
+const (
+	// Gap is a change to a greater offset, which skips the local times in between.
+	Gap AnomalyKind = "gap"
+
+	// Fold is a change to a smaller offset, which repeats the local times in between.
+	Fold AnomalyKind = "fold"
+
+	// AbbreviationChange is a change of the designation or the DST flag without a change of the offset.
+	AbbreviationChange AnomalyKind = "abbreviation"
+)
+
+// Anomaly is a change of the zone found by Scan.
+type Anomaly struct {
+	Kind AnomalyKind
+
+	// At is the first instant the new zone is in effect.
+	At time.Time
+
+	// Before and After are the zones before and after the change.
+	Before, After timezones.Zone
+
+	// Jump is the change of the offset, positive for gaps and negative for folds.
+	Jump time.Duration
+}
+
+// Scan walks the instants from from (inclusive) to to (exclusive) in increments of step and reports every
+// change of the zone of loc as an anomaly, ordered by time.
+// The exact instant of each change is found by bisection, so step only needs to be shorter than
+// the shortest period between two changes; a minute finds every change that keeps a zone for at least a minute.
+func Scan(loc *time.Location, from, to time.Time, step time.Duration) []Anomaly {
+	var anomalies []Anomaly
+	prev := ZoneAt(loc, from)
+	prevTime := from
+	for t := from.Add(step); ; t = t.Add(step) {
+		if !t.Before(to) {
+			t = to
+		}
+		zone := ZoneAt(loc, t)
+		if zone != prev {
+			at := bisect(loc, prevTime, t, prev)
+			after := ZoneAt(loc, at)
+			anomalies = append(anomalies, newAnomaly(at, prev, after))
+			prev, prevTime = after, at
+			// Look for another change between the one found and t.
+			t = at
+			continue
+		}
+		if !t.Before(to) {
+			break
+		}
+		prev, prevTime = zone, t
+	}
+	return anomalies
+}
+
+// bisect returns the first instant in (lo, hi] with a zone different than zone, which is the zone at lo.
+func bisect(loc *time.Location, lo, hi time.Time, zone timezones.Zone) time.Time {
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2).Truncate(time.Second)
+		if ZoneAt(loc, mid) == zone {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi
+}
+
+func newAnomaly(at time.Time, before, after timezones.Zone) Anomaly {
+	a := Anomaly{At: at.UTC(), Before: before, After: after, Jump: after.Offset - before.Offset}
+	switch {
+	case a.Jump > 0:
+		a.Kind = Gap
+	case a.Jump < 0:
+		a.Kind = Fold
+	default:
+		a.Kind = AbbreviationChange
+	}
+	return a
+}
@@ -0,0 +1,60 @@
+package timezonetest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func TestScan(t *testing.T) {
+	template := DoubleDST()
+	loc, err := timezones.NewLocation(template)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	from := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2031, time.January, 1, 0, 0, 0, 0, time.UTC)
+	gmt, bst, bdst := template.Zones[0], template.Zones[1], template.Zones[2]
+	expected := []Anomaly{
+		{Kind: Gap, At: time.Date(2030, time.March, 31, 1, 0, 0, 0, time.UTC), Before: gmt, After: bst, Jump: time.Hour},
+		{Kind: Gap, At: time.Date(2030, time.May, 1, 1, 0, 0, 0, time.UTC), Before: bst, After: bdst, Jump: time.Hour},
+		{Kind: Fold, At: time.Date(2030, time.August, 1, 1, 0, 0, 0, time.UTC), Before: bdst, After: bst, Jump: -time.Hour},
+		{Kind: Fold, At: time.Date(2030, time.October, 27, 1, 0, 0, 0, time.UTC), Before: bst, After: gmt, Jump: -time.Hour},
+	}
+	for _, step := range []time.Duration{time.Minute, 24 * time.Hour} {
+		if anomalies := Scan(loc, from, to, step); !reflect.DeepEqual(anomalies, expected) {
+			t.Fatalf("step %v: expected %+v, got %+v", step, expected, anomalies)
+		}
+	}
+}
+
+func TestScan_AbbreviationChange(t *testing.T) {
+	template := timezones.Template{
+		Zones: []timezones.Zone{
+			{Name: "AAA", Offset: time.Hour},
+			{Name: "BBB", Offset: time.Hour},
+		},
+		Changes: []timezones.Change{
+			{Start: time.Date(2030, time.June, 1, 12, 30, 15, 0, time.UTC), ZoneIndex: 1},
+		},
+	}
+	loc, err := timezones.NewLocation(template)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	anomalies := Scan(loc, time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2031, time.January, 1, 0, 0, 0, 0, time.UTC), time.Hour)
+	expected := []Anomaly{
+		{
+			Kind:   AbbreviationChange,
+			At:     time.Date(2030, time.June, 1, 12, 30, 15, 0, time.UTC),
+			Before: template.Zones[0],
+			After:  template.Zones[1],
+		},
+	}
+	if !reflect.DeepEqual(anomalies, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, anomalies)
+	}
+}
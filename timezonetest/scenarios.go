@@ -0,0 +1,99 @@
+package timezonetest
+
+import (
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+// The scenario templates describe fictional zones with daylight saving time edge cases.
+// Each function returns a new template, so callers may modify it.
+
+// SpringForwardAtMidnight returns a zone whose clocks jump from 00:00 to 01:00 on the second Sunday of March,
+// so the local midnight does not exist on that day, and fall back from 00:00 to 23:00 of the previous day
+// on the first Sunday of November. Its standard offset is -03:00.
+func SpringForwardAtMidnight() timezones.Template {
+	return timezones.Template{
+		Name:   "Scenario/SpringForwardAtMidnight",
+		Zones:  []timezones.Zone{{Name: "MDS", Offset: -3 * time.Hour}},
+		Extend: "MDS3MDD,M3.2.0/0,M11.1.0/0",
+	}
+}
+
+// DoubleDST returns a zone with double summer time in 2030 like Britain during World War II:
+// GMT until 31 March 2030, BST (+01:00) until 1 May, BDST (+02:00) until 1 August, BST until 27 October and GMT
+// after that.
+func DoubleDST() timezones.Template {
+	return timezones.Template{
+		Name: "Scenario/DoubleDST",
+		Zones: []timezones.Zone{
+			{Name: "GMT"},
+			{Name: "BST", Offset: time.Hour, IsDST: true},
+			{Name: "BDST", Offset: 2 * time.Hour, IsDST: true},
+		},
+		Changes: []timezones.Change{
+			{Start: time.Date(2030, time.March, 31, 1, 0, 0, 0, time.UTC), ZoneIndex: 1},
+			{Start: time.Date(2030, time.May, 1, 1, 0, 0, 0, time.UTC), ZoneIndex: 2},
+			{Start: time.Date(2030, time.August, 1, 1, 0, 0, 0, time.UTC), ZoneIndex: 1},
+			{Start: time.Date(2030, time.October, 27, 1, 0, 0, 0, time.UTC), ZoneIndex: 0},
+		},
+	}
+}
+
+// OffsetChangeWithoutDST returns a zone that changes its standard offset from +03:00 to +04:00
+// on 1 January 2030 at 00:00 local time without observing daylight saving time.
+func OffsetChangeWithoutDST() timezones.Template {
+	return timezones.Template{
+		Name: "Scenario/OffsetChangeWithoutDST",
+		Zones: []timezones.Zone{
+			{Name: "+03", Offset: 3 * time.Hour},
+			{Name: "+04", Offset: 4 * time.Hour},
+		},
+		Changes: []timezones.Change{
+			{Start: time.Date(2029, time.December, 31, 21, 0, 0, 0, time.UTC), ZoneIndex: 1},
+		},
+		Extend: "<+04>-4",
+	}
+}
+
+// NegativeDST returns a zone with negative daylight saving time like Europe/Dublin:
+// the standard time IST (+01:00) applies in summer and the daylight saving time GMT (+00:00) in winter,
+// from the last Sunday of October to the last Sunday of March.
+func NegativeDST() timezones.Template {
+	return timezones.Template{
+		Name:   "Scenario/NegativeDST",
+		Zones:  []timezones.Zone{{Name: "IST", Offset: time.Hour}},
+		Extend: "IST-1GMT0,M10.5.0,M3.5.0/1",
+	}
+}
+
+// HalfHourOffset returns a zone with a fixed +05:30 offset.
+func HalfHourOffset() timezones.Template {
+	return timezones.Template{
+		Name:  "Scenario/HalfHourOffset",
+		Zones: []timezones.Zone{{Name: "+0530", Offset: 5*time.Hour + 30*time.Minute}},
+	}
+}
+
+// HalfHourDST returns a southern hemisphere zone like Australia/Lord_Howe, with +10:30 standard time
+// and daylight saving time that moves the clocks by only 30 minutes, to +11:00,
+// from the first Sunday of October to the first Sunday of April.
+func HalfHourDST() timezones.Template {
+	return timezones.Template{
+		Name:   "Scenario/HalfHourDST",
+		Zones:  []timezones.Zone{{Name: "+1030", Offset: 10*time.Hour + 30*time.Minute}},
+		Extend: "<+1030>-10:30<+11>-11,M10.1.0,M4.1.0",
+	}
+}
+
+// Scenarios returns all scenario templates.
+func Scenarios() []timezones.Template {
+	return []timezones.Template{
+		SpringForwardAtMidnight(),
+		DoubleDST(),
+		OffsetChangeWithoutDST(),
+		NegativeDST(),
+		HalfHourOffset(),
+		HalfHourDST(),
+	}
+}
@@ -0,0 +1,97 @@
+package timezonetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func TestScenarios(t *testing.T) {
+	for _, template := range Scenarios() {
+		var warnings []error
+		e := timezones.Encoder{Warn: func(err error) { warnings = append(warnings, err) }}
+		if _, err := e.NewLocation(template); err != nil {
+			t.Fatalf("%s: %v", template.Name, err)
+		}
+		if len(warnings) > 0 {
+			t.Fatalf("%s: unexpected warnings %v", template.Name, warnings)
+		}
+	}
+}
+
+func TestScenarios_Behavior(t *testing.T) {
+	location := func(template timezones.Template) *time.Location {
+		loc, err := timezones.NewLocation(template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return loc
+	}
+	type probe struct {
+		at   time.Time
+		name string
+		dst  bool
+	}
+	tests := []struct {
+		template timezones.Template
+		probes   []probe
+	}{
+		{
+			template: SpringForwardAtMidnight(),
+			probes: []probe{
+				{time.Date(2030, time.March, 10, 2, 59, 59, 0, time.UTC), "MDS", false},
+				{time.Date(2030, time.March, 10, 3, 0, 0, 0, time.UTC), "MDD", true},
+				{time.Date(2030, time.November, 3, 1, 59, 59, 0, time.UTC), "MDD", true},
+				{time.Date(2030, time.November, 3, 2, 0, 0, 0, time.UTC), "MDS", false},
+			},
+		},
+		{
+			template: DoubleDST(),
+			probes: []probe{
+				{time.Date(2030, time.March, 1, 0, 0, 0, 0, time.UTC), "GMT", false},
+				{time.Date(2030, time.April, 1, 0, 0, 0, 0, time.UTC), "BST", true},
+				{time.Date(2030, time.June, 1, 0, 0, 0, 0, time.UTC), "BDST", true},
+				{time.Date(2030, time.September, 1, 0, 0, 0, 0, time.UTC), "BST", true},
+				{time.Date(2030, time.December, 1, 0, 0, 0, 0, time.UTC), "GMT", false},
+			},
+		},
+		{
+			template: OffsetChangeWithoutDST(),
+			probes: []probe{
+				{time.Date(2029, time.December, 31, 20, 59, 59, 0, time.UTC), "+03", false},
+				{time.Date(2029, time.December, 31, 21, 0, 0, 0, time.UTC), "+04", false},
+				{time.Date(2040, time.July, 1, 0, 0, 0, 0, time.UTC), "+04", false},
+			},
+		},
+		{
+			template: NegativeDST(),
+			probes: []probe{
+				{time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC), "GMT", true},
+				{time.Date(2030, time.July, 1, 0, 0, 0, 0, time.UTC), "IST", false},
+			},
+		},
+		{
+			template: HalfHourDST(),
+			probes: []probe{
+				{time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC), "+11", true},
+				{time.Date(2030, time.July, 1, 0, 0, 0, 0, time.UTC), "+1030", false},
+			},
+		},
+	}
+	for _, test := range tests {
+		loc := location(test.template)
+		for _, p := range test.probes {
+			zone := ZoneAt(loc, p.at)
+			if zone.Name != p.name || zone.IsDST != p.dst {
+				t.Fatalf("%s at %s: expected %s (DST %t), got %+v", test.template.Name, p.at, p.name, p.dst, zone)
+			}
+		}
+	}
+
+	// The local midnight does not exist on the day clocks spring forward.
+	midnight := time.Date(2030, time.March, 10, 0, 30, 0, 0, location(SpringForwardAtMidnight()))
+	if midnight.Hour() == 0 {
+		t.Fatalf("expected 00:30 to be normalized, got %s", midnight)
+	}
+}
@@ -0,0 +1,87 @@
+package timezonetest
+
+import (
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+// Step is a zone in effect for a duration, see Script.
+type Step struct {
+	Zone timezones.Zone
+
+	// Duration for which Zone is in effect. Durations shorter than a second are rounded up to a second,
+	// as TZif stores whole seconds.
+	Duration time.Duration
+}
+
+// Script returns a template named name whose zones follow the steps, starting at start and repeating them
+// cyclically until the template has n changes, e.g. to stress test schedulers with transitions far more
+// frequent than yearly.
+// Before start, the zone of the last step is in effect.
+// The template has no Extend, so the zone of the last change applies after it.
+//
+// The steps may use at most 254 distinct zones, otherwise the template can't be encoded.
+func Script(name string, start time.Time, steps []Step, n int) timezones.Template {
+	template := timezones.Template{Name: name}
+	if len(steps) == 0 {
+		return template
+	}
+	zoneIndex := func(zone timezones.Zone) int {
+		for i := range template.Zones {
+			if template.Zones[i] == zone {
+				return i
+			}
+		}
+		template.Zones = append(template.Zones, zone)
+		return len(template.Zones) - 1
+	}
+	indices := make([]int, len(steps))
+	for i := range steps {
+		indices[i] = zoneIndex(steps[i].Zone)
+	}
+	template.FirstZoneIndex = indices[len(steps)-1]
+	at := start.Unix()
+	template.Changes = make([]timezones.Change, 0, n)
+	for i := 0; i < n; i++ {
+		step := i % len(steps)
+		template.Changes = append(template.Changes, timezones.Change{Start: time.Unix(at, 0).UTC(), ZoneIndex: indices[step]})
+		seconds := int64((steps[step].Duration + time.Second - 1) / time.Second)
+		if seconds < 1 {
+			seconds = 1
+		}
+		at += seconds
+	}
+	return template
+}
+
+// Accelerating returns a template alternating between standard time at offset and daylight saving time
+// an hour ahead of it, starting at start with the first period lasting first and each following period
+// half as long as the previous one, down to a second, until the template has n changes.
+func Accelerating(start time.Time, offset, first time.Duration, n int) timezones.Template {
+	std := timezones.Zone{Name: "STD", Offset: offset}
+	dst := timezones.Zone{Name: "DST", Offset: offset + time.Hour, IsDST: true}
+	steps := make([]Step, 0, n)
+	duration := first
+	for i := 0; i < n; i++ {
+		zone := dst
+		if i%2 == 1 {
+			zone = std
+		}
+		steps = append(steps, Step{Zone: zone, Duration: duration})
+		if duration > time.Second {
+			duration /= 2
+		}
+	}
+	template := Script("Scenario/Accelerating", start, steps, n)
+	// Script starts in the zone of the last step, which may be DST, but standard time should apply before start.
+	for i := range template.Zones {
+		if template.Zones[i] == std {
+			template.FirstZoneIndex = i
+			return template
+		}
+	}
+	template.Zones = append(template.Zones, std)
+	template.FirstZoneIndex = len(template.Zones) - 1
+	return template
+}
@@ -0,0 +1,65 @@
+package timezonetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func TestScript(t *testing.T) {
+	start := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	a := timezones.Zone{Name: "AAA", Offset: time.Hour}
+	b := timezones.Zone{Name: "BBB", Offset: 2 * time.Hour, IsDST: true}
+	c := timezones.Zone{Name: "CCC", Offset: -time.Hour}
+	template := Script("Test/Script", start, []Step{{a, time.Hour}, {b, 30 * time.Minute}, {c, time.Millisecond}}, 7)
+	if err := timezones.Validate(template); err != nil {
+		t.Fatal(err)
+	}
+	loc, err := timezones.NewLocation(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, probe := range []struct {
+		at   time.Time
+		zone timezones.Zone
+	}{
+		{start.Add(-time.Second), c},
+		{start, a},
+		{start.Add(time.Hour), b},
+		{start.Add(90 * time.Minute), c},
+		{start.Add(90*time.Minute + time.Second), a},
+		{start.Add(180*time.Minute + time.Second), c},
+		// The zone of the last change applies afterwards.
+		{start.Add(24 * time.Hour), a},
+	} {
+		if zone := ZoneAt(loc, probe.at); zone != probe.zone {
+			t.Errorf("%v: expected %+v, got %+v", probe.at, probe.zone, zone)
+		}
+	}
+}
+
+func TestAccelerating(t *testing.T) {
+	start := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for _, n := range []int{1, 2, 40} {
+		template := Accelerating(start, time.Hour, time.Hour, n)
+		if err := timezones.Validate(template); err != nil {
+			t.Fatalf("%d: %v", n, err)
+		}
+		if len(template.Changes) != n {
+			t.Fatalf("%d: expected %d changes, got %d", n, n, len(template.Changes))
+		}
+		if zone := template.ZoneAt(start.Add(-time.Second)); zone.IsDST {
+			t.Fatalf("%d: expected standard time before start, got %+v", n, zone)
+		}
+	}
+	template := Accelerating(start, 0, time.Hour, 40)
+	last := template.Changes[len(template.Changes)-1].Start
+	prev := template.Changes[len(template.Changes)-2].Start
+	if d := last.Sub(prev); d != time.Second {
+		t.Fatalf("expected the last period to last a second, got %v", d)
+	}
+	if d := template.Changes[1].Start.Sub(template.Changes[0].Start); d != time.Hour {
+		t.Fatalf("expected the first period to last an hour, got %v", d)
+	}
+}
@@ -0,0 +1,107 @@
+package timezonetest
+
+import (
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+// Shrink returns a minimal variant of the template for which fails still returns true.
+//
+// Shrink repeatedly tries to remove changes, Extend and zones, and to simplify zone names and offsets,
+// keeping each simplification if the result can be encoded by timezones.TZData and still fails.
+// fails must return true for template itself, otherwise template is returned unchanged.
+// fails must not modify the template passed to it.
+func Shrink(template timezones.Template, fails func(timezones.Template) bool) timezones.Template {
+	current := clone(template)
+	if !fails(current) {
+		return template
+	}
+	try := func(candidate timezones.Template) bool {
+		if _, err := timezones.TZData(candidate); err != nil {
+			return false
+		}
+		if !fails(candidate) {
+			return false
+		}
+		current = candidate
+		return true
+	}
+
+	for progress := true; progress; {
+		progress = false
+
+		// Remove chunks of changes, starting with big ones.
+		for size := len(current.Changes); size > 0; size /= 2 {
+			for start := 0; start+size <= len(current.Changes); {
+				candidate := clone(current)
+				candidate.Changes = append(candidate.Changes[:start], candidate.Changes[start+size:]...)
+				if try(candidate) {
+					progress = true
+				} else {
+					start += size
+				}
+			}
+		}
+
+		if current.Extend != "" {
+			candidate := clone(current)
+			candidate.Extend = ""
+			if try(candidate) {
+				progress = true
+			}
+		}
+
+		for i := len(current.Zones) - 1; i >= 0; i-- {
+			if candidate, ok := removeZone(current, i); ok && try(candidate) {
+				progress = true
+			}
+		}
+
+		for i := range current.Zones {
+			simplifications := []func(*timezones.Zone){
+				func(z *timezones.Zone) { z.Name = "AAA" },
+				func(z *timezones.Zone) { z.Offset = z.Offset.Truncate(time.Hour) },
+				func(z *timezones.Zone) { z.IsDST = false },
+			}
+			for _, simplify := range simplifications {
+				candidate := clone(current)
+				simplify(&candidate.Zones[i])
+				if candidate.Zones[i] != current.Zones[i] && try(candidate) {
+					progress = true
+				}
+			}
+		}
+	}
+	return current
+}
+
+// removeZone removes the zone at index i if no change uses it.
+// If it is the first zone, Zones[0] becomes the first zone.
+func removeZone(template timezones.Template, i int) (timezones.Template, bool) {
+	for _, c := range template.Changes {
+		if c.ZoneIndex == i {
+			return timezones.Template{}, false
+		}
+	}
+	candidate := clone(template)
+	candidate.Zones = append(candidate.Zones[:i], candidate.Zones[i+1:]...)
+	switch {
+	case candidate.FirstZoneIndex == i:
+		candidate.FirstZoneIndex = 0
+	case candidate.FirstZoneIndex > i:
+		candidate.FirstZoneIndex--
+	}
+	for j := range candidate.Changes {
+		if candidate.Changes[j].ZoneIndex > i {
+			candidate.Changes[j].ZoneIndex--
+		}
+	}
+	return candidate, true
+}
+
+func clone(template timezones.Template) timezones.Template {
+	template.Zones = append([]timezones.Zone(nil), template.Zones...)
+	template.Changes = append([]timezones.Change(nil), template.Changes...)
+	return template
+}
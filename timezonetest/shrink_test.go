@@ -0,0 +1,47 @@
+package timezonetest
+
+import (
+	"testing"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func TestShrink(t *testing.T) {
+	// The failure is a change to a DST zone.
+	fails := func(template timezones.Template) bool {
+		for _, c := range template.Changes {
+			if template.Zones[c.ZoneIndex].IsDST {
+				return true
+			}
+		}
+		return false
+	}
+	found := false
+	for seed := int64(0); seed < 100 && !found; seed++ {
+		template := Generate(seed, Options{Extend: true})
+		if !fails(template) {
+			continue
+		}
+		found = true
+		shrunk := Shrink(template, fails)
+		if len(shrunk.Changes) != 1 || len(shrunk.Zones) != 1 || shrunk.Extend != "" {
+			t.Fatalf("seed %d: expected minimal template, got %+v", seed, shrunk)
+		}
+		if !fails(shrunk) {
+			t.Fatalf("seed %d: shrunk template does not fail", seed)
+		}
+		for _, z := range shrunk.Zones {
+			if z.Name != "AAA" || z.Offset%(60*60*1e9) != 0 {
+				t.Fatalf("seed %d: expected simplified zones, got %+v", seed, shrunk.Zones)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("no failing template generated")
+	}
+
+	template := Generate(1, Options{})
+	if shrunk := Shrink(template, func(timezones.Template) bool { return false }); len(shrunk.Changes) != len(template.Changes) {
+		t.Fatal("expected passing template to be returned unchanged")
+	}
+}
@@ -0,0 +1,55 @@
+package timezonetest
+
+import (
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+// Transitions returns the instants within [from, to) at which the zone of the template may change,
+// including the transitions generated from Extend.
+func Transitions(template timezones.Template, from, to time.Time) ([]time.Time, error) {
+	if len(template.Changes) == 0 && template.Extend != "" {
+		// Extend applies since the beginning of time, start generating transitions from from.
+		template.Changes = []timezones.Change{{Start: from}}
+		if len(template.Zones) == 0 {
+			template.Zones = []timezones.Zone{{}}
+		}
+	}
+	fat, err := template.MaterializeExtend(to)
+	if err != nil {
+		return nil, err
+	}
+	var transitions []time.Time
+	for _, c := range fat.Changes {
+		if !c.Start.Before(from) && c.Start.Before(to) {
+			transitions = append(transitions, c.Start)
+		}
+	}
+	return transitions, nil
+}
+
+// CompareAtTransitions compares the zones used by got and want one second before, at and one second after
+// each of the transitions, which catches off-by-one errors that sampling at arbitrary instants misses.
+// It returns a *Divergence for the first instant at which they differ, or nil.
+func CompareAtTransitions(got, want *time.Location, transitions []time.Time) error {
+	probes := make([]time.Time, 0, 3*len(transitions))
+	for _, t := range transitions {
+		probes = append(probes, t.Add(-time.Second), t, t.Add(time.Second))
+	}
+	return CompareLocations(got, want, probes)
+}
+
+// CompareTemplateAtTransitions compares the location built from the template with want
+// around each transition of the template within [from, to), see CompareAtTransitions.
+func CompareTemplateAtTransitions(template timezones.Template, want *time.Location, from, to time.Time) error {
+	got, err := timezones.NewLocation(template)
+	if err != nil {
+		return err
+	}
+	transitions, err := Transitions(template, from, to)
+	if err != nil {
+		return err
+	}
+	return CompareAtTransitions(got, want, transitions)
+}
@@ -0,0 +1,65 @@
+package timezonetest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/martin-sucha/timezones"
+)
+
+func TestTransitions(t *testing.T) {
+	from := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2031, time.January, 1, 0, 0, 0, 0, time.UTC)
+	transitions, err := Transitions(NegativeDST(), from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []time.Time{
+		from,
+		time.Date(2030, time.March, 31, 1, 0, 0, 0, time.UTC),
+		time.Date(2030, time.October, 27, 1, 0, 0, 0, time.UTC),
+	}
+	if len(transitions) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, transitions)
+	}
+	for i := range expected {
+		if !transitions[i].Equal(expected[i]) {
+			t.Fatalf("expected %v, got %v", expected, transitions)
+		}
+	}
+
+	transitions, err = Transitions(DoubleDST(), from, to)
+	if err != nil || len(transitions) != 4 {
+		t.Fatalf("expected 4 transitions, got %v %v", transitions, err)
+	}
+}
+
+func TestCompareTemplateAtTransitions(t *testing.T) {
+	from := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2031, time.January, 1, 0, 0, 0, 0, time.UTC)
+	want, err := timezones.NewLocation(DoubleDST())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := CompareTemplateAtTransitions(DoubleDST(), want, from, to); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Off by one second.
+	template := DoubleDST()
+	template.Changes[2].Start = template.Changes[2].Start.Add(time.Second)
+	err = CompareTemplateAtTransitions(template, want, from, to)
+	var d *Divergence
+	if !errors.As(err, &d) || !d.At.Equal(DoubleDST().Changes[2].Start) {
+		t.Fatalf("expected divergence at the third transition, got %v", err)
+	}
+	// Sampling every hour at half past does not notice.
+	got, err := timezones.NewLocation(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := CompareLocations(got, want, Probes(from.Add(30*time.Minute), to, time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
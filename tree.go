@@ -0,0 +1,146 @@
+package timezones
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// LinkPolicy chooses how WriteTree writes aliases.
+type LinkPolicy int
+
+const (
+	// SymlinkAliases writes each alias as a relative symbolic link to its target.
+	// This is the smallest tree, but symbolic links need privileges on Windows and some container image
+	// tools don't preserve them.
+	SymlinkAliases LinkPolicy = iota
+
+	// HardLinkAliases writes each alias as a hard link to the file of its target, which zic prefers.
+	// Hard links don't work across file systems and many archive formats store them as copies.
+	HardLinkAliases
+
+	// CopyAliases writes each alias as a separate TZif file with the same content as its target.
+	// It works everywhere at the cost of space.
+	CopyAliases
+)
+
+// TreeOptions configures WriteTree.
+type TreeOptions struct {
+	// Encoder encodes the templates. The zero value encodes the same way as TZData.
+	Encoder Encoder
+
+	// Links chooses how aliases are written, SymlinkAliases by default.
+	Links LinkPolicy
+}
+
+// WriteTree writes templates as a zoneinfo directory tree rooted at dir, the inverse of LoadAll.
+// Each template is written to the file named by its key in templates, a slash-separated path like
+// "Europe/Bratislava", creating subdirectories as needed.
+//
+// Aliases map alias paths to paths of templates, e.g. as returned by ApplyRenames, and are written
+// according to options.Links.
+// A key of templates that is also an alias is written as an alias.
+// Template.Metadata is written to a sidecar file next to the file of the template, see MetadataSuffix.
+// Aliases get no sidecar.
+// Existing files in the way are replaced, as are sidecars left by templates that no longer have metadata.
+//
+// It returns an error wrapping ErrUnknownZone if an alias refers to a path that is not a template
+// or is an alias itself.
+func WriteTree(dir string, templates map[string]*Template, aliases map[string]string, options TreeOptions) error {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		if _, ok := aliases[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	// Sort, so that the reported error does not depend on the map order.
+	sort.Strings(names)
+	for _, name := range names {
+		path, err := treePath(dir, name)
+		if err != nil {
+			return err
+		}
+		data, err := options.Encoder.Encode(*templates[name])
+		if err != nil {
+			return err
+		}
+		if err := replaceFile(path, func() error { return os.WriteFile(path, data, 0o644) }); err != nil {
+			return err
+		}
+		if err := writeMetadata(path, templates[name].Metadata); err != nil {
+			return err
+		}
+	}
+
+	names = names[:0]
+	for alias := range aliases {
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+	for _, alias := range names {
+		target := aliases[alias]
+		if _, isAlias := aliases[target]; templates[target] == nil || isAlias {
+			return fmt.Errorf("%w: alias %s refers to %s", ErrUnknownZone, alias, target)
+		}
+		path, err := treePath(dir, alias)
+		if err != nil {
+			return err
+		}
+		targetPath, err := treePath(dir, target)
+		if err != nil {
+			return err
+		}
+		if err := replaceFile(path, func() error { return writeAlias(path, targetPath, options.Links) }); err != nil {
+			return fmt.Errorf("alias %s: %w", alias, err)
+		}
+		if err := writeMetadata(path, nil); err != nil {
+			return fmt.Errorf("alias %s: %w", alias, err)
+		}
+	}
+	return nil
+}
+
+// treePath returns the path of the file for the slash-separated name within dir.
+func treePath(dir, name string) (string, error) {
+	if !fs.ValidPath(name) || name == "." {
+		return "", fmt.Errorf("name %q is not a valid relative path", name)
+	}
+	return filepath.Join(dir, filepath.FromSlash(name)), nil
+}
+
+// replaceFile creates the parent directories of path, removes any file at path and calls create.
+// The file is removed rather than overwritten so that a symbolic link left by a previous run
+// is not followed.
+func replaceFile(path string, create func() error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return create()
+}
+
+// writeAlias creates path as an alias of the already written targetPath.
+func writeAlias(path, targetPath string, links LinkPolicy) error {
+	switch links {
+	case SymlinkAliases:
+		rel, err := filepath.Rel(filepath.Dir(path), targetPath)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(rel, path)
+	case HardLinkAliases:
+		return os.Link(targetPath, path)
+	case CopyAliases:
+		data, err := os.ReadFile(targetPath)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, data, 0o644)
+	default:
+		return fmt.Errorf("unknown link policy %d", links)
+	}
+}
@@ -0,0 +1,98 @@
+package timezones
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteTree(t *testing.T) {
+	bench := benchTemplate()
+	bench.Name = "Custom/Bench"
+	fixed := Template{Name: "Etc/MyFixed", Zones: []Zone{{Name: "MyFixed", Offset: 2 * time.Hour}}}
+	templates := map[string]*Template{
+		"Custom/Bench": &bench,
+		"Etc/MyFixed":  &fixed,
+		"Old/Bench":    &bench,
+	}
+	aliases := map[string]string{"Old/Bench": "Custom/Bench", "Bench": "Custom/Bench"}
+	for _, tc := range []struct {
+		name  string
+		links LinkPolicy
+		mode  os.FileMode
+	}{
+		{"symlink", SymlinkAliases, os.ModeSymlink},
+		{"hard link", HardLinkAliases, 0},
+		{"copy", CopyAliases, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			// Write twice to check that existing files are replaced.
+			for i := 0; i < 2; i++ {
+				if err := WriteTree(dir, templates, aliases, TreeOptions{Links: tc.links}); err != nil {
+					if tc.links == SymlinkAliases && i == 0 {
+						t.Skipf("symbolic links not supported: %v", err)
+					}
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			loaded, err := LoadAll(os.DirFS(dir), LoadOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(loaded) != 4 {
+				t.Fatalf("expected 4 templates, got %d", len(loaded))
+			}
+			expected, err := TZData(bench)
+			if err != nil {
+				t.Fatal(err)
+			}
+			target, err := os.Stat(filepath.Join(dir, "Custom", "Bench"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, alias := range []string{"Bench", "Old/Bench"} {
+				path := filepath.Join(dir, filepath.FromSlash(alias))
+				fi, err := os.Lstat(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if fi.Mode()&os.ModeSymlink != tc.mode {
+					t.Fatalf("%s: unexpected mode %v", alias, fi.Mode())
+				}
+				if fi, err = os.Stat(path); err != nil {
+					t.Fatal(err)
+				}
+				if same := os.SameFile(fi, target); same != (tc.links != CopyAliases) {
+					t.Fatalf("%s: same file as target is %v", alias, same)
+				}
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(data, expected) {
+					t.Fatalf("%s: unexpected content", alias)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteTree_Errors(t *testing.T) {
+	fixed := Template{Zones: []Zone{{Name: "MyFixed", Offset: 2 * time.Hour}}}
+	templates := map[string]*Template{"Etc/MyFixed": &fixed}
+	err := WriteTree(t.TempDir(), templates, map[string]string{"Fixed": "Etc/Missing"}, TreeOptions{})
+	if !errors.Is(err, ErrUnknownZone) {
+		t.Fatalf("expected ErrUnknownZone, got %v", err)
+	}
+	err = WriteTree(t.TempDir(), templates, map[string]string{"A": "B", "B": "Etc/MyFixed"}, TreeOptions{})
+	if !errors.Is(err, ErrUnknownZone) {
+		t.Fatalf("expected ErrUnknownZone, got %v", err)
+	}
+	if err := WriteTree(t.TempDir(), map[string]*Template{"../Fixed": &fixed}, nil, TreeOptions{}); err == nil {
+		t.Fatal("expected error for invalid path")
+	}
+}
@@ -0,0 +1,91 @@
+package timezones
+
+import (
+	"encoding/binary"
+)
+
+// rawTZif describes arbitrary TZif data for tests, including data that TZData can't produce.
+type rawTZif struct {
+	// version is 0 for version 1 data, '2' or '3' otherwise.
+	version byte
+	times   []int64
+	types   []byte
+	ltt     []rawLocalTimeType
+	chars   string
+	leaps   []rawLeap
+	isstd   []byte
+	isut    []byte
+	// footer is written after the data block as is, it needs to include the newlines.
+	footer string
+}
+
+type rawLocalTimeType struct {
+	utoff int32
+	isdst byte
+	idx   byte
+}
+
+type rawLeap struct {
+	occur int64
+	corr  int32
+}
+
+func (r *rawTZif) bytes() []byte {
+	var data []byte
+	if r.version == 0 {
+		return r.appendBlock(data, 4)
+	}
+	data = r.appendHeader(data, &rawTZif{version: r.version})
+	data = r.appendBlock(data, 8)
+	return append(data, r.footer...)
+}
+
+func (r *rawTZif) appendHeader(data []byte, counts *rawTZif) []byte {
+	header := make([]byte, headerSize)
+	copy(header, "TZif")
+	header[4] = r.version
+	binary.BigEndian.PutUint32(header[20:24], uint32(len(counts.isut)))
+	binary.BigEndian.PutUint32(header[24:28], uint32(len(counts.isstd)))
+	binary.BigEndian.PutUint32(header[28:32], uint32(len(counts.leaps)))
+	binary.BigEndian.PutUint32(header[32:36], uint32(len(counts.times)))
+	binary.BigEndian.PutUint32(header[36:40], uint32(len(counts.ltt)))
+	binary.BigEndian.PutUint32(header[40:44], uint32(len(counts.chars)))
+	return append(data, header...)
+}
+
+func (r *rawTZif) appendBlock(data []byte, tsize int) []byte {
+	data = r.appendHeader(data, r)
+	appendTime := func(data []byte, t int64) []byte {
+		if tsize == 4 {
+			return appendUint32(data, uint32(t))
+		}
+		return appendUint64(data, uint64(t))
+	}
+	for _, t := range r.times {
+		data = appendTime(data, t)
+	}
+	data = append(data, r.types...)
+	for _, t := range r.ltt {
+		data = appendUint32(data, uint32(t.utoff))
+		data = append(data, t.isdst, t.idx)
+	}
+	data = append(data, r.chars...)
+	for _, l := range r.leaps {
+		data = appendTime(data, l.occur)
+		data = appendUint32(data, uint32(l.corr))
+	}
+	data = append(data, r.isstd...)
+	return append(data, r.isut...)
+}
+
+func appendUint32(data []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return append(data, buf[:]...)
+}
+
+func appendUint64(data []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	return append(data, buf[:]...)
+}
@@ -0,0 +1,85 @@
+package timezones
+
+import "fmt"
+
+// Validate checks that TZData can encode the template, without encoding it.
+// It returns the same error as TZData.
+func Validate(template Template) error {
+	var e Encoder
+	return e.Validate(template)
+}
+
+// Validate checks that Encode can encode the template, without encoding it.
+// It returns the same error as Encode and reports the same warnings to Warn.
+func (e *Encoder) Validate(template Template) error {
+	_, err := e.Size(template)
+	return err
+}
+
+// ValidateStrict is a stricter variant of Validate intended for test assertions in code that promises
+// to only produce canonical templates.
+// In addition to Validate, it returns ErrDiscontinuity if the last change disagrees with Extend,
+// and ErrNotCanonical if
+//   - a zone other than the first zone, see FirstZoneIndex, is not used by any change,
+//   - two zones are the same,
+//   - a change does not change the zone,
+//   - or Extend is not in the canonical form zic emits, for example "CET-1CEST,M3.5.0,M10.5.0/3".
+//
+// The result wraps ErrNotCanonical and is a *FieldError for problems with Zones and Changes.
+func ValidateStrict(template Template) error {
+	e := Encoder{StrictContinuity: true}
+	if err := e.Validate(template); err != nil {
+		return err
+	}
+	if err := checkCanonical(&template); err != nil {
+		return withTemplateName(&template, err)
+	}
+	return nil
+}
+
+// checkCanonical checks the invariants of ValidateStrict on a valid template.
+func checkCanonical(template *Template) error {
+	used := make([]bool, len(template.Zones))
+	if len(template.Zones) > 0 {
+		// The first zone applies before the first change and TZif needs at least one local time type.
+		used[template.FirstZoneIndex] = true
+	}
+	for i, c := range template.Changes {
+		used[c.ZoneIndex] = true
+		prev := template.FirstZoneIndex
+		if i > 0 {
+			prev = template.Changes[i-1].ZoneIndex
+		}
+		if sameZone(template.Zones[prev], template.Zones[c.ZoneIndex]) {
+			return &FieldError{
+				Field:  "Changes",
+				Index:  i,
+				Err:    ErrNotCanonical,
+				Detail: fmt.Sprintf("change to zone %d does not change the zone in effect", c.ZoneIndex),
+			}
+		}
+	}
+	for i, z := range template.Zones {
+		if !used[i] {
+			return &FieldError{Field: "Zones", Index: i, Err: ErrNotCanonical, Detail: fmt.Sprintf("zone %q is unused", z.Name)}
+		}
+		for j := 0; j < i; j++ {
+			if sameZone(template.Zones[j], z) {
+				return &FieldError{
+					Field:  "Zones",
+					Index:  i,
+					Err:    ErrNotCanonical,
+					Detail: fmt.Sprintf("zone %q is the same as Zones[%d]", z.Name, j),
+				}
+			}
+		}
+	}
+	if template.Extend != "" {
+		// Validate has already parsed Extend successfully.
+		rule, _ := parseTZRule(template.Extend)
+		if canonical := rule.String(); canonical != template.Extend {
+			return fmt.Errorf("%w: extend %q, canonical form is %q", ErrNotCanonical, template.Extend, canonical)
+		}
+	}
+	return nil
+}
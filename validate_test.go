@@ -0,0 +1,102 @@
+package timezones
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	if err := Validate(benchTemplate()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := Validate(Template{Zones: []Zone{{Name: "ABC", Offset: time.Millisecond}}})
+	if !errors.Is(err, ErrInvalidOffset) {
+		t.Fatalf("expected ErrInvalidOffset, got %v", err)
+	}
+	e := Encoder{RoundOffsets: true}
+	if err := e.Validate(Template{Zones: []Zone{{Name: "ABC", Offset: time.Millisecond}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateStrict(t *testing.T) {
+	cet := Zone{Name: "CET", Offset: time.Hour}
+	cest := Zone{Name: "CEST", Offset: 2 * time.Hour, IsDST: true}
+	march := time.Date(2020, time.March, 29, 1, 0, 0, 0, time.UTC)
+	october := time.Date(2020, time.October, 25, 1, 0, 0, 0, time.UTC)
+	extend := "CET-1CEST,M3.5.0,M10.5.0/3"
+
+	for _, template := range []Template{
+		{Zones: []Zone{cet}},
+		{Extend: extend},
+		{Zones: []Zone{cet}, Extend: extend},
+		{Zones: []Zone{cet, cest}, Changes: []Change{{Start: march, ZoneIndex: 1}, {Start: october, ZoneIndex: 0}}},
+		{Zones: []Zone{cet, cest}, Changes: []Change{{Start: march, ZoneIndex: 1}}, Extend: extend},
+	} {
+		if err := ValidateStrict(template); err != nil {
+			t.Fatalf("%+v: unexpected error: %v", template, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		template Template
+		field    string
+		index    int
+	}{
+		{
+			name:     "unused zone",
+			template: Template{Zones: []Zone{cet, cest}},
+			field:    "Zones",
+			index:    1,
+		},
+		{
+			name:     "duplicate zone",
+			template: Template{Zones: []Zone{cet, cest, cet}, Changes: []Change{{Start: march, ZoneIndex: 1}, {Start: october, ZoneIndex: 2}}},
+			field:    "Zones",
+			index:    2,
+		},
+		{
+			name: "redundant change",
+			template: Template{Zones: []Zone{cet, cest}, Changes: []Change{
+				{Start: march, ZoneIndex: 1},
+				{Start: october, ZoneIndex: 0},
+				{Start: october.Add(time.Hour), ZoneIndex: 0},
+			}},
+			field: "Changes",
+			index: 2,
+		},
+		{
+			name:     "first change to first zone",
+			template: Template{Zones: []Zone{cet}, Changes: []Change{{Start: march, ZoneIndex: 0}}},
+			field:    "Changes",
+			index:    0,
+		},
+	} {
+		err := ValidateStrict(tc.template)
+		var fieldErr *FieldError
+		if !errors.Is(err, ErrNotCanonical) || !errors.As(err, &fieldErr) {
+			t.Fatalf("%s: expected ErrNotCanonical field error, got %v", tc.name, err)
+		}
+		if fieldErr.Field != tc.field || fieldErr.Index != tc.index {
+			t.Fatalf("%s: expected %s[%d], got %v", tc.name, tc.field, tc.index, err)
+		}
+	}
+
+	err := ValidateStrict(Template{Name: "Test", Extend: "CET-01:00CEST,M3.5.0/2,M10.5.0/3"})
+	if !errors.Is(err, ErrNotCanonical) {
+		t.Fatalf("expected ErrNotCanonical, got %v", err)
+	}
+	expected := `Test: timezones: template is not canonical: extend "CET-01:00CEST,M3.5.0/2,M10.5.0/3", ` +
+		`canonical form is "CET-1CEST,M3.5.0,M10.5.0/3"`
+	if err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err.Error())
+	}
+
+	// The zone of the last change must agree with Extend.
+	err = ValidateStrict(Template{Zones: []Zone{cet, cest}, Changes: []Change{{Start: october, ZoneIndex: 1}}, Extend: extend})
+	if !errors.Is(err, ErrDiscontinuity) {
+		t.Fatalf("expected ErrDiscontinuity, got %v", err)
+	}
+}
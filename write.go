@@ -0,0 +1,165 @@
+package timezones
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// streamThreshold is the number of changes from which Encoder.Write streams the data instead of
+// encoding it at once.
+// Templates with fewer changes encode to a few kilobytes at most, so streaming would only add overhead.
+const streamThreshold = 4096
+
+// WriteTZData writes the TZif data of the template to w, the same data TZData returns.
+// Unlike TZData, it does not build the whole data in memory, so that templates with many changes
+// can be written directly to a file or an HTTP response.
+// The template is validated before anything is written to w.
+func WriteTZData(w io.Writer, template Template) error {
+	var e Encoder
+	return e.Write(w, template)
+}
+
+// Write writes the TZif data of the template to w, see WriteTZData.
+// With SelfCheck, the data is checked and so built in memory before it is written.
+// Like Encode, Write copies Changes of templates with nonzero FirstZoneIndex to renumber the zones.
+func (e *Encoder) Write(w io.Writer, template Template) error {
+	if e.SelfCheck || len(template.Changes) < streamThreshold {
+		data, err := e.build(nil, &template)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+	prepared, rewrites := e.prepare(&template)
+	l, err := e.computeLayout(prepared)
+	if err != nil {
+		return withTemplateName(prepared, err)
+	}
+	e.warnAll(prepared, rewrites)
+	return e.stream(w, prepared, l)
+}
+
+// stream writes the TZif data of the template with the layout l to w, in the same format as buildGeneric,
+// holding only a small buffer in memory.
+func (e *Encoder) stream(w io.Writer, template *Template, l tzdataLayout) error {
+	buf := make([]byte, 0, 4096)
+	// flush writes buf to w if it has less than n bytes of space left.
+	flush := func(n int) error {
+		if cap(buf)-len(buf) >= n {
+			return nil
+		}
+		_, err := w.Write(buf)
+		buf = buf[:0]
+		return err
+	}
+	// put extends buf by n bytes and returns them.
+	put := func(n int) ([]byte, error) {
+		if err := flush(n); err != nil {
+			return nil, err
+		}
+		if cap(buf) < n {
+			// Only a long Extend does not fit.
+			buf = make([]byte, 0, n)
+		}
+		buf = buf[:len(buf)+n]
+		b := buf[len(buf)-n:]
+		// The buffer is reused, so clear the bytes left from previous writes.
+		for i := range b {
+			b[i] = 0
+		}
+		return b, nil
+	}
+
+	if l.version > 1 {
+		// V1 header
+		b, err := put(headerSize)
+		if err != nil {
+			return err
+		}
+		putHeader(b, l.version, 0, 0, 0, 0, 0, 0)
+	}
+	// V2 header, or the only V1 header
+	b, err := put(headerSize)
+	if err != nil {
+		return err
+	}
+	putHeader(b, l.version, l.isutcnt, l.isstdcnt, l.leapcnt, l.timecnt, l.typecnt, len(l.designations))
+	// V2 data block
+	// transition times
+	for i := range template.Changes {
+		b, err := put(l.tsize)
+		if err != nil {
+			return err
+		}
+		if l.tsize == 4 {
+			binary.BigEndian.PutUint32(b, uint32(template.Changes[i].Start.Unix()))
+		} else {
+			binary.BigEndian.PutUint64(b, uint64(template.Changes[i].Start.Unix()))
+		}
+	}
+	// transition types
+	for i := range template.Changes {
+		b, err := put(1)
+		if err != nil {
+			return err
+		}
+		// See buildGeneric.
+		b[0] = byte(template.Changes[i].ZoneIndex + l.typeShift)
+	}
+	// local time type records
+	if l.typeShift > 0 {
+		if b, err = put(6); err != nil {
+			return err
+		}
+		putLocalTimeTypeRecord(b, l.firstZone.Offset, l.firstZone.IsDST, l.designationIndices[0])
+	}
+	for i := range template.Zones {
+		if b, err = put(6); err != nil {
+			return err
+		}
+		putLocalTimeTypeRecord(b, template.Zones[i].Offset, template.Zones[i].IsDST, l.designationIndices[i+l.typeShift])
+	}
+	// time zone designations
+	if b, err = put(len(l.designations)); err != nil {
+		return err
+	}
+	copy(b, l.designations)
+	// leap second records
+	for i := range template.LeapSeconds {
+		if b, err = put(l.tsize + 4); err != nil {
+			return err
+		}
+		putLeapSecondRecord(b, l.tsize, template.LeapSeconds[i])
+	}
+	// standard/wall indicators and UT/local indicators, see buildGeneric.
+	for n := l.isstdcnt + l.isutcnt; n > 0; {
+		chunk := n
+		if chunk > cap(buf) {
+			chunk = cap(buf)
+		}
+		if b, err = put(chunk); err != nil {
+			return err
+		}
+		fill(b, e.Indicators.value())
+		n -= chunk
+	}
+	if l.version > 1 {
+		// footer
+		if b, err = put(len(template.Extend) + 2); err != nil {
+			return err
+		}
+		b[0] = '\n'
+		copy(b[1:], template.Extend)
+		b[len(b)-1] = '\n'
+	}
+	if err := flush(cap(buf)); err != nil {
+		return err
+	}
+	if e.KeepTrailing && len(template.Trailing) > 0 {
+		if _, err := w.Write(template.Trailing); err != nil {
+			return err
+		}
+	}
+	return nil
+}
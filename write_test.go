@@ -0,0 +1,119 @@
+package timezones
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// streamTemplate returns largeTemplate with an Extend and a first zone that is not at index 0.
+func streamTemplate(nchanges int) Template {
+	template := largeTemplate(nchanges)
+	template.Zones = append(template.Zones, Zone{Name: "LMT", Offset: 2*time.Hour + 4*time.Second})
+	template.FirstZoneIndex = 2
+	template.Extend = "<Std>-2:23<Dst>-2:53,M3.5.0,M10.5.0/3"
+	return template
+}
+
+func TestWriteTZData(t *testing.T) {
+	for _, n := range []int{0, 10, streamThreshold + 1, 100000} {
+		template := streamTemplate(n)
+		expected, err := TZData(template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := WriteTZData(&buf, template); err != nil {
+			t.Fatalf("%d changes: unexpected error: %v", n, err)
+		}
+		if !bytes.Equal(buf.Bytes(), expected) {
+			t.Fatalf("%d changes: written data differs from TZData", n)
+		}
+	}
+}
+
+func TestEncoder_Write(t *testing.T) {
+	template := streamTemplate(streamThreshold + 1)
+	template.Trailing = []byte("X-VENDOR: data\n")
+	for _, e := range []Encoder{
+		{KeepTrailing: true},
+		{Designations: DistinctDesignations},
+		{SelfCheck: true},
+		{StrictLocalTimeTypes: true},
+		{Indicators: WallIndicators},
+		{Indicators: NoIndicators},
+	} {
+		expected, err := e.Encode(template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := e.Write(&buf, template); err != nil {
+			t.Fatalf("%+v: unexpected error: %v", e, err)
+		}
+		if !bytes.Equal(buf.Bytes(), expected) {
+			t.Fatalf("%+v: written data differs from Encode", e)
+		}
+	}
+
+	v1 := template
+	v1.Extend = ""
+	// Version 1 only has 32-bit transition times.
+	v1.Changes = make([]Change, len(template.Changes))
+	for i, c := range template.Changes {
+		v1.Changes[i] = Change{Start: c.Start.AddDate(80, 0, 0), ZoneIndex: c.ZoneIndex}
+	}
+	e := Encoder{Version: 1}
+	expected, err := e.Encode(v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := e.Write(&buf, v1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Fatal("written version 1 data differs from Encode")
+	}
+}
+
+type failingWriter struct {
+	written int
+}
+
+var errWrite = errors.New("write failed")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.written += len(p)
+	return 0, errWrite
+}
+
+func TestWriteTZData_Errors(t *testing.T) {
+	template := streamTemplate(streamThreshold + 1)
+	var w failingWriter
+	if err := WriteTZData(&w, template); !errors.Is(err, errWrite) {
+		t.Fatalf("expected write error, got %v", err)
+	}
+
+	// Invalid templates are rejected before writing anything.
+	template.Changes[10].ZoneIndex = 5
+	w = failingWriter{}
+	if err := WriteTZData(&w, template); !errors.Is(err, ErrZoneIndex) {
+		t.Fatalf("expected ErrZoneIndex, got %v", err)
+	}
+	if w.written != 0 {
+		t.Fatalf("expected nothing written, got %d bytes", w.written)
+	}
+}
+
+func BenchmarkWriteTZData(b *testing.B) {
+	template := largeTemplate(100000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := WriteTZData(io.Discard, template); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
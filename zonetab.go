@@ -0,0 +1,94 @@
+package timezones
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ZoneTabEntry is a line of the zone1970.tab file of the tz database.
+type ZoneTabEntry struct {
+	// Countries are the ISO 3166 alpha-2 codes of the countries using the zone, e.g. "CZ" and "SK".
+	Countries []string
+
+	// Coordinates of the principal location of the zone in ISO 6709 sign-degrees-minutes-seconds format,
+	// e.g. "+5005+01426".
+	Coordinates string
+
+	// Zone is the name of the zone, e.g. "Europe/Prague".
+	Zone string
+
+	// Comments distinguish the zones of a country, if it has more than one. They are empty otherwise.
+	Comments string
+}
+
+// ParseZoneTab parses the zone1970.tab file of the tz database.
+// Lines starting with '#' and empty lines are skipped.
+// It returns an error wrapping ErrInvalidZoneTab if a line has less than three columns.
+func ParseZoneTab(r io.Reader) ([]ZoneTabEntry, error) {
+	var entries []ZoneTabEntry
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if text == "" || text[0] == '#' {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(fields) < 3 || fields[0] == "" || fields[2] == "" {
+			return nil, fmt.Errorf("%w: line %d: expected at least 3 tab-separated columns", ErrInvalidZoneTab, line)
+		}
+		entry := ZoneTabEntry{
+			Countries:   strings.Split(fields[0], ","),
+			Coordinates: fields[1],
+			Zone:        fields[2],
+		}
+		if len(fields) > 3 {
+			entry.Comments = fields[3]
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// RecommendZones returns the zones relevant for the country with the given ISO 3166 alpha-2 code
+// as of the given time, e.g. for pickers in signup forms.
+// Zones of the country in entries, parsed by ParseZoneTab, are deduplicated by their behavior within
+// two years after asOf using the templates, e.g. loaded by LoadAll, see EquivalenceClasses.
+// Of each group of zones that behave the same, the one listed first in entries is returned,
+// in the order of entries.
+// Zones without a template are skipped.
+func RecommendZones(entries []ZoneTabEntry, templates map[string]*Template, country string, asOf time.Time) []string {
+	to := asOf.AddDate(2, 0, 0)
+	seen := make(map[string]bool)
+	var zones []string
+	for _, entry := range entries {
+		if !entry.hasCountry(country) {
+			continue
+		}
+		t := templates[entry.Zone]
+		if t == nil {
+			continue
+		}
+		key := behaviorKey(t, asOf, to)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		zones = append(zones, entry.Zone)
+	}
+	return zones
+}
+
+func (e *ZoneTabEntry) hasCountry(country string) bool {
+	for _, c := range e.Countries {
+		if strings.EqualFold(c, country) {
+			return true
+		}
+	}
+	return false
+}
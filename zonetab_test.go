@@ -0,0 +1,74 @@
+package timezones
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testZoneTab = `# tzdb timezone descriptions
+#
+#country-
+#codes	coordinates	TZ	comments
+CZ,SK	+5005+01426	Europe/Prague
+DE,DK,NO,SE,SJ	+5230+01322	Europe/Berlin	most of Germany
+US	+404251-0740023	America/New_York	Eastern (most areas)
+US	+421953-0830245	America/Detroit	Eastern - MI (most areas)
+US	+415100-0873900	America/Chicago	Central (most areas)
+US	+332654-1120424	America/Phoenix	MST - AZ (except Navajo)
+US	+611305-1495401	America/Anchorage	Alaska (most areas)
+`
+
+func TestParseZoneTab(t *testing.T) {
+	entries, err := ParseZoneTab(strings.NewReader(testZoneTab))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 7 {
+		t.Fatalf("expected 7 entries, got %d", len(entries))
+	}
+	expected := ZoneTabEntry{
+		Countries:   []string{"DE", "DK", "NO", "SE", "SJ"},
+		Coordinates: "+5230+01322",
+		Zone:        "Europe/Berlin",
+		Comments:    "most of Germany",
+	}
+	if !reflect.DeepEqual(entries[1], expected) {
+		t.Fatalf("expected %+v, got %+v", expected, entries[1])
+	}
+	if entries[0].Comments != "" {
+		t.Fatalf("expected no comments, got %q", entries[0].Comments)
+	}
+
+	if _, err := ParseZoneTab(strings.NewReader("CZ\tEurope/Prague\n")); !errors.Is(err, ErrInvalidZoneTab) {
+		t.Fatalf("expected ErrInvalidZoneTab, got %v", err)
+	}
+}
+
+func TestRecommendZones(t *testing.T) {
+	entries, err := ParseZoneTab(strings.NewReader(testZoneTab))
+	if err != nil {
+		t.Fatal(err)
+	}
+	us := func(std string, offset time.Duration, extend string) *Template {
+		return &Template{Zones: []Zone{{Name: std, Offset: offset}}, Extend: extend}
+	}
+	templates := map[string]*Template{
+		"America/New_York": us("EST", -5*time.Hour, "EST5EDT,M3.2.0,M11.1.0"),
+		"America/Detroit":  us("EST", -5*time.Hour, "EST5EDT,M3.2.0,M11.1.0"),
+		"America/Chicago":  us("CST", -6*time.Hour, "CST6CDT,M3.2.0,M11.1.0"),
+		"America/Phoenix":  us("MST", -7*time.Hour, "MST7"),
+		// America/Anchorage is missing.
+	}
+	asOf := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	zones := RecommendZones(entries, templates, "us", asOf)
+	expected := []string{"America/New_York", "America/Chicago", "America/Phoenix"}
+	if !reflect.DeepEqual(zones, expected) {
+		t.Fatalf("expected %v, got %v", expected, zones)
+	}
+	if zones := RecommendZones(entries, templates, "XX", asOf); len(zones) != 0 {
+		t.Fatalf("expected no zones, got %v", zones)
+	}
+}